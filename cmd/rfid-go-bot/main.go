@@ -10,15 +10,18 @@ import (
 	"strings"
 	"syscall"
 
+	"new_era_go/internal/gobot/audit"
 	"new_era_go/internal/gobot/cache"
 	"new_era_go/internal/gobot/config"
 	"new_era_go/internal/gobot/erp"
+	"new_era_go/internal/gobot/filter"
 	"new_era_go/internal/gobot/httpapi"
 	"new_era_go/internal/gobot/ipc"
 	"new_era_go/internal/gobot/reader"
 	"new_era_go/internal/gobot/service"
 	"new_era_go/internal/gobot/telegram"
 	"new_era_go/internal/tui"
+	"new_era_go/sdk"
 )
 
 func main() {
@@ -48,24 +51,105 @@ func main() {
 	erpClient := erp.New(cfg.ERPURL, cfg.ERPAPIKey, cfg.ERPAPISecret, cfg.RequestTimeout)
 	cacheStore := cache.New()
 	svc := service.New(cfg, erpClient, cacheStore)
+	if cfg.AuditFile != "" {
+		auditLog, err := audit.Open(cfg.AuditFile, cfg.AuditMaxBytes, cfg.AuditKeep)
+		if err != nil {
+			log.Printf("[bot] audit log disabled: %v", err)
+		} else {
+			defer auditLog.Close()
+			svc.SetAuditLog(auditLog)
+		}
+	}
+
+	var filters *filter.Set
+	if cfg.FilterFile != "" {
+		filters, err = filter.Load(cfg.FilterFile)
+		if err != nil {
+			log.Fatalf("filter rules error: %v", err)
+		}
+		svc.SetFilters(filters)
+		log.Printf("[bot] filter rules loaded: %s (readers=%d)", cfg.FilterFile, filters.Readers())
+	}
 
 	backend := strings.ToLower(cfg.ScanBackend)
 	useSDKScanner := backend == "sdk" || backend == "hybrid"
 
 	// Keep scanner manager available for Telegram/HTTP commands regardless of ingest backend.
 	// In ingest mode we still avoid wiring scanner into IPC start/stop flow to prevent duplicate readers.
-	var tg *telegram.Bot
-	scanner := reader.New(cfg, func(epc string) {
-		svc.HandleEPC(context.Background(), epc, "sdk")
-		if tg != nil {
-			tg.OnReaderEPC(epc)
+	var scanner *reader.Manager
+	scanner = reader.New(cfg, func(endpoint string, tag sdk.TagEvent) {
+		// With a portal configured the service only sees decided passes.
+		if !scanner.DirectionEnabled() {
+			svc.HandleRead(context.Background(), service.TagRead{
+				EPC:       tag.EPC,
+				Source:    "sdk",
+				Reader:    endpoint,
+				Antenna:   tag.Antenna,
+				RSSI:      tag.RSSI,
+				TID:       tag.TID,
+				Prefilter: scanner.AppliedFilter(),
+			})
 		}
 	}, nil)
+	scanner.SetDirectionHandler(func(endpoint string, ev sdk.DirectionEvent) {
+		svc.HandleRead(context.Background(), service.TagRead{
+			EPC:       ev.EPC,
+			Source:    "sdk",
+			Reader:    endpoint,
+			Antenna:   ev.Last.Antenna,
+			RSSI:      ev.Last.RSSI,
+			TID:       ev.Last.TID,
+			Direction: string(ev.Direction),
+			Prefilter: scanner.AppliedFilter(),
+		})
+	})
+	scanner.SetTIDVerifier(func(endpoint string, tag sdk.TagEvent) bool {
+		return svc.VerifyTID(service.TagRead{
+			EPC:     tag.EPC,
+			Source:  "sdk",
+			Reader:  endpoint,
+			Antenna: tag.Antenna,
+			RSSI:    tag.RSSI,
+			TID:     tag.TID,
+		})
+	})
+	scanner.SetFilters(filters)
+	svc.SetSignaler(scanner)
 
-	tg = telegram.New(cfg.BotToken, cfg.RequestTimeout, cfg.PollTimeout, svc, scanner)
+	profiles, err := sdk.LoadProfileStore(cfg.ProfileFile)
+	if err != nil {
+		log.Fatalf("profile file error: %v", err)
+	}
+	scanner.SetProfiles(profiles)
+
+	if cfg.ReaderCaptureFile != "" {
+		capture, err := openCapture(cfg.ReaderCaptureFile)
+		if err != nil {
+			log.Printf("[bot] reader capture disabled: %v", err)
+		} else {
+			defer capture.Close()
+			scanner.SetRecorder(sdk.NewRecorder(capture))
+			log.Printf("[bot] reader capture: %s", cfg.ReaderCaptureFile)
+		}
+	}
+	if cfg.ReaderReplayFile != "" {
+		log.Printf("[bot] reader replay: %s", cfg.ReaderReplayFile)
+	}
+
+	tg := telegram.New(cfg.BotToken, cfg.RequestTimeout, cfg.PollTimeout, svc, scanner)
 	svc.SetNotifier(tg)
 	scanner.SetNotifier(tg.Notify)
 
+	// Test mode gets its own stream of every read, so tags the service
+	// path already saw in this session still count for a /test file.
+	testReads, stopTestReads := scanner.Subscribe(sdk.SubscribeFilter{})
+	defer stopTestReads()
+	go func() {
+		for tag := range testReads {
+			tg.OnReaderEPC(tag.EPC)
+		}
+	}()
+
 	startupRefs := tg.SendStartupNotice(ctx, "🤖 Bot ishga tushdi. Cache yangilanmoqda...")
 	if err := svc.RefreshCache(ctx, "startup", false); err != nil {
 		log.Printf("[bot] startup cache refresh failed: %v", err)
@@ -100,6 +184,7 @@ func main() {
 	}
 	if cfg.IPCEnabled && cfg.IPCSocket != "" {
 		ipcServer := ipc.New(cfg.IPCSocket, svc, ipcScanner)
+		ipcServer.SetProfileSwitcher(scanner)
 		go func() {
 			if err := ipcServer.Run(ctx); err != nil {
 				log.Printf("[bot] ipc server failed: %v", err)
@@ -164,3 +249,11 @@ func redirectBotLogs() func() {
 		_ = f.Close()
 	}
 }
+
+// openCapture appends to the reader capture file, creating its directory.
+func openCapture(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+}
@@ -0,0 +1,346 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is one audit record: a tag read or a submit decision.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	EPC     string    `json:"epc"`
+	Source  string    `json:"source,omitempty"`
+	Reader  string    `json:"reader,omitempty"`
+	Antenna int       `json:"antenna,omitempty"`
+	RSSI    int       `json:"rssi,omitempty"`
+	TID     string    `json:"tid,omitempty"`
+	// Direction is the portal decision ("in"/"out") for the read, if any.
+	Direction string `json:"direction,omitempty"`
+	Action    string `json:"action"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Query selects audit entries. Zero fields do not filter.
+type Query struct {
+	EPC    string
+	Action string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+// appendQueue is how many encoded entries Append can hand to the writer
+// before it waits for the disk.
+const appendQueue = 1024
+
+// Log is an append-only JSONL audit log with size-based rotation.
+// Rotated files are kept as path.1 (newest) .. path.N (oldest).
+// Append only queues the encoded entry; a single writer goroutine does the
+// file I/O and rotation, so the ingest path never waits on the disk unless
+// appendQueue entries are already pending.
+type Log struct {
+	path     string
+	maxBytes int64
+	keep     int
+
+	queue chan appendReq
+	done  chan struct{}
+
+	// mu guards the file and rotation; it is held by the writer
+	// goroutine, never by Append.
+	mu   sync.Mutex
+	file *os.File
+	buf  *bufio.Writer
+	size int64
+
+	// errMu guards err, the writer's last failure not yet reported.
+	errMu sync.Mutex
+	err   error
+
+	// closeMu keeps Append from sending on queue while Close closes it.
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+// appendReq is one queued line, or a flush barrier when flushed is set.
+type appendReq struct {
+	line    []byte
+	flushed chan struct{}
+}
+
+// Open opens (or creates) the audit log at path and starts its writer.
+func Open(path string, maxBytes int64, keep int) (*Log, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("audit path is empty")
+	}
+	if maxBytes <= 0 {
+		maxBytes = 32 << 20
+	}
+	if keep < 1 {
+		keep = 1
+	}
+
+	l := &Log{
+		path:     path,
+		maxBytes: maxBytes,
+		keep:     keep,
+		queue:    make(chan appendReq, appendQueue),
+		done:     make(chan struct{}),
+	}
+	if err := l.openLocked(); err != nil {
+		return nil, err
+	}
+	go l.writeLoop()
+	return l, nil
+}
+
+// Path returns the active audit file path.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append queues one entry for the writer; Time defaults to now. The error
+// is an encoding error, a closed log, or the writer's last write failure.
+func (l *Log) Append(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.closeMu.RLock()
+	defer l.closeMu.RUnlock()
+	if l.closed {
+		return fmt.Errorf("audit log closed")
+	}
+	l.queue <- appendReq{line: line}
+
+	l.errMu.Lock()
+	defer l.errMu.Unlock()
+	err, l.err = l.err, nil
+	return err
+}
+
+// Flush waits until every entry appended so far is written to the file.
+func (l *Log) Flush() {
+	l.closeMu.RLock()
+	if l.closed {
+		l.closeMu.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	l.queue <- appendReq{flushed: flushed}
+	l.closeMu.RUnlock()
+	<-flushed
+}
+
+// Query scans the active and rotated files and returns matching entries,
+// oldest first. With Limit set only the newest Limit matches are kept and
+// the scan stops at the first file, newest to oldest, that completes them.
+// The file list is taken under the log lock after pending appends are
+// flushed; files are read without it, so a rotation during the scan may
+// skip or repeat a few entries.
+func (l *Log) Query(q Query) ([]Entry, error) {
+	l.Flush()
+	l.mu.Lock()
+	paths := make([]string, 0, l.keep+1)
+	paths = append(paths, l.path)
+	for i := 1; i <= l.keep; i++ {
+		paths = append(paths, l.rotatedPath(i))
+	}
+	l.mu.Unlock()
+
+	var files [][]Entry
+	total := 0
+	for _, path := range paths {
+		matches, err := scanFile(path, q)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches)
+		total += len(matches)
+		if q.Limit > 0 && total >= q.Limit {
+			break
+		}
+	}
+	out := make([]Entry, 0, total)
+	for i := len(files) - 1; i >= 0; i-- {
+		out = append(out, files[i]...)
+	}
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[len(out)-q.Limit:]
+	}
+	return out, nil
+}
+
+// Close writes the queued entries and closes the active file.
+func (l *Log) Close() error {
+	l.closeMu.Lock()
+	if l.closed {
+		l.closeMu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.queue)
+	l.closeMu.Unlock()
+	<-l.done
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	err := l.buf.Flush()
+	if cerr := l.file.Close(); err == nil {
+		err = cerr
+	}
+	l.file = nil
+	return err
+}
+
+// writeLoop writes queued lines, flushing the buffer whenever the queue
+// runs dry so bursts become one write.
+func (l *Log) writeLoop() {
+	defer close(l.done)
+	for req := range l.queue {
+		l.mu.Lock()
+		if req.line != nil {
+			l.writeLocked(req.line)
+		}
+		if len(l.queue) == 0 || req.flushed != nil {
+			l.keepErr(l.buf.Flush())
+		}
+		l.mu.Unlock()
+		if req.flushed != nil {
+			close(req.flushed)
+		}
+	}
+}
+
+func (l *Log) writeLocked(line []byte) {
+	if l.file == nil {
+		return
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.maxBytes {
+		if err := l.rotateLocked(); err != nil {
+			l.keepErr(err)
+			if l.file == nil {
+				return
+			}
+		}
+	}
+	n, err := l.buf.Write(line)
+	l.size += int64(n)
+	l.keepErr(err)
+}
+
+func (l *Log) keepErr(err error) {
+	if err == nil {
+		return
+	}
+	l.errMu.Lock()
+	l.err = err
+	l.errMu.Unlock()
+}
+
+func (l *Log) openLocked() error {
+	dir := filepath.Dir(l.path)
+	if dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	l.file = f
+	if l.buf == nil {
+		l.buf = bufio.NewWriter(f)
+	} else {
+		l.buf.Reset(f)
+	}
+	l.size = info.Size()
+	return nil
+}
+
+func (l *Log) rotateLocked() error {
+	if err := l.buf.Flush(); err != nil {
+		return err
+	}
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	l.file = nil
+
+	_ = os.Remove(l.rotatedPath(l.keep))
+	for i := l.keep - 1; i >= 1; i-- {
+		if err := os.Rename(l.rotatedPath(i), l.rotatedPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(l.path, l.rotatedPath(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return l.openLocked()
+}
+
+func (l *Log) rotatedPath(n int) string {
+	return l.path + "." + strconv.Itoa(n)
+}
+
+func scanFile(path string, q Query) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	epc := strings.ToUpper(strings.TrimSpace(q.EPC))
+	action := strings.TrimSpace(q.Action)
+
+	out := make([]Entry, 0, 16)
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 4096), 1<<20)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			// A torn line after a crash must not hide the rest of the log.
+			continue
+		}
+		if epc != "" && entry.EPC != epc {
+			continue
+		}
+		if action != "" && entry.Action != action {
+			continue
+		}
+		if !q.Since.IsZero() && entry.Time.Before(q.Since) {
+			continue
+		}
+		if !q.Until.IsZero() && entry.Time.After(q.Until) {
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out, sc.Err()
+}
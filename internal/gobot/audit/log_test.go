@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendAndQueryByEPC(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path, 1<<20, 3)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	defer l.Close()
+
+	base := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	_ = l.Append(Entry{Time: base, EPC: "E2001", Action: "queued"})
+	_ = l.Append(Entry{Time: base.Add(2 * time.Minute), EPC: "E2002", Action: "miss"})
+	_ = l.Append(Entry{Time: base.Add(3 * time.Minute), EPC: "E2001", Action: "submitted"})
+
+	got, err := l.Query(Query{EPC: "e2001"})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Action != "queued" || got[1].Action != "submitted" {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+
+	window, _ := l.Query(Query{Since: base.Add(time.Minute), Until: base.Add(150 * time.Second)})
+	if len(window) != 1 || window[0].EPC != "E2002" {
+		t.Fatalf("unexpected window entries: %+v", window)
+	}
+}
+
+func TestRotationKeepsOldEntriesQueryable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path, 200, 2)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	defer l.Close()
+
+	for i := 0; i < 6; i++ {
+		if err := l.Append(Entry{EPC: "E2001", Action: "queued"}); err != nil {
+			t.Fatalf("append %d failed: %v", i, err)
+		}
+	}
+	l.Flush()
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected rotated file: %v", err)
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected at most 2 rotated files, stat err=%v", err)
+	}
+
+	got, err := l.Query(Query{EPC: "E2001", Limit: 2})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected limit=2 entries, got %d", len(got))
+	}
+}
+
+func TestQueryWithLimitStopsAtNewestFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path, 1<<20, 3)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	defer l.Close()
+	// An unreadable oldest file fails any query that has to reach it.
+	if err := os.Mkdir(path+".3", 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, action := range []string{"queued", "miss", "submitted"} {
+		_ = l.Append(Entry{EPC: "E2001", Action: action})
+	}
+
+	got, err := l.Query(Query{EPC: "E2001", Limit: 2})
+	if err != nil {
+		t.Fatalf("limited query must not read older files: %v", err)
+	}
+	if len(got) != 2 || got[0].Action != "miss" || got[1].Action != "submitted" {
+		t.Fatalf("expected the newest two entries, got %+v", got)
+	}
+	if _, err := l.Query(Query{EPC: "E2001"}); err == nil {
+		t.Fatalf("unlimited query should reach the unreadable file")
+	}
+}
@@ -3,9 +3,13 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
+
+	"new_era_go/internal/regions"
+	"new_era_go/sdk"
 )
 
 type Config struct {
@@ -33,6 +37,36 @@ type Config struct {
 	ReaderRetryDelay     time.Duration
 	ReaderHost           string
 	ReaderPort           int
+	ReaderSerial         string
+	ReaderAddress        int
+	ReaderCaptureFile    string
+	ReaderReplayFile     string
+	ReaderOverflow       string
+	ReaderPresence       time.Duration
+	ReaderDedup          time.Duration
+	ReaderDedupMax       int
+	ReaderTelemetry      time.Duration
+	ReaderTIDAddr        int
+	ReaderTIDWords       int
+	ReaderEPCMask        string
+	ReaderRegion         string
+	ReaderWorkMode       string
+	ReaderStreamTagTime  int
+	ReaderStreamFrame    string
+	ReaderAdaptive       bool
+	ProfileFile          string
+	SignalOK             string
+	SignalFail           string
+	SignalPulse          time.Duration
+	DirectionInsideAnt   int
+	DirectionOutsideAnt  int
+	DirectionWindow      time.Duration
+	SubmitDirection      string
+	FilterFile           string
+	AuditEnabled         bool
+	AuditFile            string
+	AuditMaxBytes        int64
+	AuditKeep            int
 }
 
 func Load() (Config, error) {
@@ -61,6 +95,36 @@ func Load() (Config, error) {
 		ReaderRetryDelay:     envDurationSec("BOT_READER_RETRY_SEC", 2),
 		ReaderHost:           strings.TrimSpace(os.Getenv("BOT_READER_HOST")),
 		ReaderPort:           envInt("BOT_READER_PORT", 0),
+		ReaderSerial:         strings.TrimSpace(os.Getenv("BOT_READER_SERIAL")),
+		ReaderAddress:        envInt("BOT_READER_ADDRESS", -1),
+		ReaderCaptureFile:    strings.TrimSpace(os.Getenv("BOT_READER_CAPTURE_FILE")),
+		ReaderReplayFile:     strings.TrimSpace(os.Getenv("BOT_READER_REPLAY_FILE")),
+		ReaderOverflow:       strings.ToLower(envOr("BOT_READER_OVERFLOW", "drop-newest")),
+		ReaderPresence:       envDurationSec("BOT_READER_PRESENCE_SEC", 0),
+		ReaderDedup:          envDurationSec("BOT_READER_DEDUP_SEC", 600),
+		ReaderDedupMax:       envInt("BOT_READER_DEDUP_MAX", sdk.DefaultDedupMaxEntries),
+		ReaderTelemetry:      envDurationSec("BOT_READER_TELEMETRY_SEC", 60),
+		ReaderTIDAddr:        envInt("BOT_READER_TID_ADDR", 0),
+		ReaderTIDWords:       envInt("BOT_READER_TID_WORDS", 0),
+		ReaderEPCMask:        strings.ToUpper(strings.TrimSpace(os.Getenv("BOT_READER_EPC_MASK"))),
+		ReaderRegion:         strings.ToUpper(strings.TrimSpace(os.Getenv("BOT_READER_REGION"))),
+		ReaderWorkMode:       strings.ToLower(envOr("BOT_READER_WORK_MODE", "answer")),
+		ReaderStreamTagTime:  envInt("BOT_READER_STREAM_TAG_SEC", 0),
+		ReaderStreamFrame:    strings.ToLower(envOr("BOT_READER_STREAM_FRAME", "antenna")),
+		ReaderAdaptive:       envBool("BOT_READER_ADAPTIVE", false),
+		ProfileFile:          envOr("BOT_PROFILE_FILE", "logs/reader_profiles.json"),
+		SignalOK:             strings.TrimSpace(os.Getenv("BOT_SIGNAL_OK")),
+		SignalFail:           strings.TrimSpace(os.Getenv("BOT_SIGNAL_FAIL")),
+		SignalPulse:          envDurationMS("BOT_SIGNAL_PULSE_MS", 1500),
+		DirectionInsideAnt:   envInt("BOT_DIRECTION_INSIDE_ANT", 0),
+		DirectionOutsideAnt:  envInt("BOT_DIRECTION_OUTSIDE_ANT", 0),
+		DirectionWindow:      envDurationMS("BOT_DIRECTION_WINDOW_MS", 3000),
+		SubmitDirection:      strings.ToLower(envOr("BOT_SUBMIT_DIRECTION", "any")),
+		FilterFile:           strings.TrimSpace(os.Getenv("BOT_FILTER_FILE")),
+		AuditEnabled:         envBool("BOT_AUDIT_ENABLED", true),
+		AuditFile:            envOr("BOT_AUDIT_FILE", "logs/rfid-audit.jsonl"),
+		AuditMaxBytes:        int64(envInt("BOT_AUDIT_MAX_MB", 32)) << 20,
+		AuditKeep:            envInt("BOT_AUDIT_KEEP", 10),
 	}
 
 	cfg.ERPURL = strings.TrimRight(cfg.ERPURL, "/")
@@ -112,10 +176,129 @@ func Load() (Config, error) {
 	if cfg.ReaderRetryDelay < 500*time.Millisecond {
 		cfg.ReaderRetryDelay = 2 * time.Second
 	}
+	if cfg.ReaderPresence < 0 {
+		cfg.ReaderPresence = 0
+	}
+	if cfg.ReaderDedup < 0 {
+		cfg.ReaderDedup = 0
+	}
+	if cfg.ReaderDedupMax < 1024 {
+		cfg.ReaderDedupMax = 1024
+	}
+	if cfg.ReaderTelemetry < 5*time.Second {
+		cfg.ReaderTelemetry = 5 * time.Second
+	}
+	if cfg.ReaderSerial != "" {
+		if _, err := sdk.ParseSerial(cfg.ReaderSerial); err != nil {
+			return Config{}, fmt.Errorf("BOT_READER_SERIAL: %w", err)
+		}
+	}
+	if _, err := sdk.ParseOverflowPolicy(cfg.ReaderOverflow); err != nil {
+		return Config{}, fmt.Errorf("BOT_READER_OVERFLOW: %w", err)
+	}
+	if cfg.ReaderAddress > 0xFE {
+		return Config{}, fmt.Errorf("BOT_READER_ADDRESS must be 0..254")
+	}
+	if cfg.ReaderAddress < 0 {
+		cfg.ReaderAddress = -1
+	}
+	if cfg.ReaderTIDAddr < 0 || cfg.ReaderTIDAddr > 0xFF {
+		cfg.ReaderTIDAddr = 0
+	}
+	if cfg.ReaderTIDWords < 0 {
+		cfg.ReaderTIDWords = 0
+	}
+	if cfg.ReaderTIDWords > 15 {
+		cfg.ReaderTIDWords = 15
+	}
+	if !isHexPrefix(cfg.ReaderEPCMask) {
+		return Config{}, fmt.Errorf("BOT_READER_EPC_MASK must be a hex EPC prefix up to 63 digits")
+	}
+	// Inventory 0x01 has no non-match Select, so an inverted mask would
+	// only be a host filter over a full inventory.
+	if envBool("BOT_READER_EPC_MASK_INVERT", false) {
+		return Config{}, fmt.Errorf("BOT_READER_EPC_MASK_INVERT is not supported: the reader only selects tags matching BOT_READER_EPC_MASK")
+	}
+	if cfg.ReaderRegion != "" {
+		if _, ok := regions.Lookup(cfg.ReaderRegion); !ok {
+			return Config{}, fmt.Errorf("BOT_READER_REGION: unknown region %q", cfg.ReaderRegion)
+		}
+	}
+	switch cfg.ReaderWorkMode {
+	case "answer", "active", "trigger-low", "trigger-high":
+	default:
+		return Config{}, fmt.Errorf("BOT_READER_WORK_MODE must be answer|active|trigger-low|trigger-high")
+	}
+	if cfg.ReaderStreamTagTime < 0 || cfg.ReaderStreamTagTime > 0xFF {
+		cfg.ReaderStreamTagTime = 0
+	}
+	if cfg.ReaderReplayFile != "" && cfg.ReaderCaptureFile != "" && samePath(cfg.ReaderReplayFile, cfg.ReaderCaptureFile) {
+		return Config{}, fmt.Errorf("BOT_READER_REPLAY_FILE and BOT_READER_CAPTURE_FILE must differ: replaying a capture into itself grows it forever")
+	}
+	if _, ok := sdk.ActiveFrameByName(cfg.ReaderStreamFrame); !ok {
+		return Config{}, fmt.Errorf("BOT_READER_STREAM_FRAME must be antenna|epc")
+	}
+	if cfg.ReaderWorkMode != "answer" && cfg.ReaderTIDWords > 0 {
+		// Streaming frames carry EPC only.
+		return Config{}, fmt.Errorf("BOT_READER_TID_WORDS is not supported with BOT_READER_WORK_MODE=%s", cfg.ReaderWorkMode)
+	}
+	if _, err := sdk.ParseSignal(cfg.SignalOK); err != nil {
+		return Config{}, fmt.Errorf("BOT_SIGNAL_OK: %w", err)
+	}
+	if _, err := sdk.ParseSignal(cfg.SignalFail); err != nil {
+		return Config{}, fmt.Errorf("BOT_SIGNAL_FAIL: %w", err)
+	}
+	if cfg.SignalPulse < 100*time.Millisecond {
+		cfg.SignalPulse = 100 * time.Millisecond
+	}
+	if cfg.DirectionInsideAnt < 0 || cfg.DirectionInsideAnt > 16 {
+		cfg.DirectionInsideAnt = 0
+	}
+	if cfg.DirectionOutsideAnt < 0 || cfg.DirectionOutsideAnt > 16 {
+		cfg.DirectionOutsideAnt = 0
+	}
+	if cfg.DirectionWindow < 200*time.Millisecond {
+		cfg.DirectionWindow = 200 * time.Millisecond
+	}
+	switch cfg.SubmitDirection {
+	case "in", "out":
+		if !cfg.DirectionPortal() {
+			return Config{}, fmt.Errorf("BOT_SUBMIT_DIRECTION=%s needs two different BOT_DIRECTION_INSIDE_ANT/BOT_DIRECTION_OUTSIDE_ANT antennas", cfg.SubmitDirection)
+		}
+	default:
+		cfg.SubmitDirection = "any"
+	}
+	if !cfg.AuditEnabled {
+		cfg.AuditFile = ""
+	}
+	if cfg.AuditMaxBytes < 1<<20 {
+		cfg.AuditMaxBytes = 1 << 20
+	}
+	if cfg.AuditKeep < 1 {
+		cfg.AuditKeep = 1
+	}
 
 	return cfg, nil
 }
 
+// DirectionPortal reports whether two different portal antennas are set,
+// which direction detection needs.
+func (c Config) DirectionPortal() bool {
+	return c.DirectionInsideAnt > 0 && c.DirectionOutsideAnt > 0 && c.DirectionInsideAnt != c.DirectionOutsideAnt
+}
+
+func isHexPrefix(v string) bool {
+	if len(v) > 63 {
+		return false
+	}
+	for _, r := range v {
+		if !strings.ContainsRune("0123456789ABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
 func envOr(key, fallback string) string {
 	val := strings.TrimSpace(os.Getenv(key))
 	if val == "" {
@@ -155,3 +338,13 @@ func envDurationSec(key string, fallbackSec int) time.Duration {
 func envDurationMS(key string, fallbackMS int) time.Duration {
 	return time.Duration(envInt(key, fallbackMS)) * time.Millisecond
 }
+
+// samePath reports whether a and b name the same file once made absolute.
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
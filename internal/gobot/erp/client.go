@@ -10,6 +10,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"new_era_go/internal/tds"
 )
 
 type Client struct {
@@ -111,7 +113,7 @@ func (c *Client) SubmitByEPC(ctx context.Context, epc string) (SubmitStatus, err
 		return "", fmt.Errorf("epc is empty")
 	}
 
-	body, _ := json.Marshal(map[string]string{"epc": epc})
+	body, _ := json.Marshal(submitPayload(epc))
 	endpoint := c.baseURL + "/api/method/titan_telegram.api.submit_open_stock_entry_by_epc"
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
 	if err != nil {
@@ -148,6 +150,30 @@ func (c *Client) SubmitByEPC(ctx context.Context, epc string) (SubmitStatus, err
 	return "", fmt.Errorf("ERP submit unexpected payload")
 }
 
+// submitPayload adds decoded GS1 identity fields when the EPC is a TDS scheme.
+func submitPayload(epc string) map[string]string {
+	payload := map[string]string{"epc": epc}
+	id, err := tds.DecodeHex(epc)
+	if err != nil {
+		return payload
+	}
+	payload["epc_uri"] = id.URI()
+	payload["epc_scheme"] = string(id.Scheme)
+	payload["company_prefix"] = id.CompanyPrefix
+	switch id.Scheme {
+	case tds.SchemeSGTIN96:
+		payload["gtin"] = id.GTIN()
+		payload["serial_no"] = id.Serial
+	case tds.SchemeSSCC96:
+		payload["sscc"] = id.SSCC()
+	case tds.SchemeGRAI96:
+		payload["grai"] = id.GRAI()
+	case tds.SchemeGIAI96:
+		payload["giai"] = id.GIAI()
+	}
+	return payload
+}
+
 func NormalizeEPC(raw string) string {
 	raw = strings.ToUpper(strings.TrimSpace(raw))
 	if raw == "" {
@@ -0,0 +1,28 @@
+package erp
+
+import "testing"
+
+func TestSubmitPayloadAddsGS1Identity(t *testing.T) {
+	got := submitPayload("3074257BF7194E4000001A85")
+	want := map[string]string{
+		"epc":            "3074257BF7194E4000001A85",
+		"epc_uri":        "urn:epc:id:sgtin:0614141.812345.6789",
+		"epc_scheme":     "sgtin-96",
+		"company_prefix": "0614141",
+		"gtin":           "80614141123458",
+		"serial_no":      "6789",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected payload: %v", got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("payload[%s]=%q want %q", k, got[k], v)
+		}
+	}
+
+	plain := submitPayload("E200001122334455")
+	if len(plain) != 1 || plain["epc"] != "E200001122334455" {
+		t.Fatalf("non-GS1 EPC must only carry epc: %v", plain)
+	}
+}
@@ -0,0 +1,82 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"new_era_go/sdk"
+)
+
+// File is the BOT_FILTER_FILE layout: default rules plus per-reader overrides.
+// Reader keys are matched against "host:port" first and then the bare host.
+type File struct {
+	Default sdk.FilterRules            `json:"default"`
+	Readers map[string]sdk.FilterRules `json:"readers,omitempty"`
+}
+
+// Set holds compiled filters keyed by reader.
+type Set struct {
+	def     *sdk.TagFilter
+	readers map[string]*sdk.TagFilter
+}
+
+// Load reads and compiles a filter file.
+func Load(path string) (*Set, error) {
+	data, err := os.ReadFile(strings.TrimSpace(path))
+	if err != nil {
+		return nil, err
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse filter file: %w", err)
+	}
+	return New(file)
+}
+
+// New compiles every rule set in file.
+func New(file File) (*Set, error) {
+	def, err := sdk.NewTagFilter(file.Default)
+	if err != nil {
+		return nil, fmt.Errorf("default rules: %w", err)
+	}
+	s := &Set{def: def, readers: make(map[string]*sdk.TagFilter, len(file.Readers))}
+	for key, rules := range file.Readers {
+		f, err := sdk.NewTagFilter(rules)
+		if err != nil {
+			return nil, fmt.Errorf("reader %q rules: %w", key, err)
+		}
+		s.readers[strings.ToLower(strings.TrimSpace(key))] = f
+	}
+	return s, nil
+}
+
+// For returns the filter for a reader, falling back to the default rules.
+// A nil Set returns nil, which accepts everything.
+func (s *Set) For(reader string) *sdk.TagFilter {
+	if s == nil {
+		return nil
+	}
+	key := strings.ToLower(strings.TrimSpace(reader))
+	if key != "" {
+		if f, ok := s.readers[key]; ok {
+			return f
+		}
+		if host, _, err := net.SplitHostPort(key); err == nil {
+			if f, ok := s.readers[host]; ok {
+				return f
+			}
+		}
+	}
+	return s.def
+}
+
+// Readers returns the number of per-reader overrides.
+func (s *Set) Readers() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.readers)
+}
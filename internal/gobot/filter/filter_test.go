@@ -0,0 +1,51 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"new_era_go/sdk"
+)
+
+func TestLoadSelectsPerReaderRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filters.json")
+	data := `{
+  "default": {"allow_prefixes": ["E2"]},
+  "readers": {
+    "192.168.1.50": {"antennas": [1]},
+    "10.0.0.7:6000": {"deny_prefixes": ["E2"]}
+  }
+}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	set, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if set.Readers() != 2 {
+		t.Fatalf("expected 2 reader overrides, got %d", set.Readers())
+	}
+
+	read := sdk.TagEvent{EPC: "E2000001", Antenna: 2}
+	if ok, _ := set.For("").Check(read); !ok {
+		t.Fatalf("default rules should accept E2 prefix")
+	}
+	if ok, reason := set.For("192.168.1.50:6000").Check(read); ok || reason != "antenna" {
+		t.Fatalf("host override should apply by host, got ok=%v reason=%q", ok, reason)
+	}
+	if ok, reason := set.For("10.0.0.7:6000").Check(read); ok || reason != "deny_prefix" {
+		t.Fatalf("endpoint override should apply, got ok=%v reason=%q", ok, reason)
+	}
+	if ok, reason := set.For("10.9.9.9:6000").Check(sdk.TagEvent{EPC: "AA01"}); ok || reason != "allow_prefix" {
+		t.Fatalf("unknown reader should use default rules, got ok=%v reason=%q", ok, reason)
+	}
+}
+
+func TestNilSetAcceptsEverything(t *testing.T) {
+	var set *Set
+	if ok, _ := set.For("any").Check(sdk.TagEvent{EPC: "AA"}); !ok {
+		t.Fatalf("nil set must accept reads")
+	}
+}
@@ -3,12 +3,17 @@ package httpapi
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"new_era_go/internal/gobot/audit"
+	"new_era_go/internal/gobot/erp"
 	"new_era_go/internal/gobot/service"
+	"new_era_go/sdk"
 )
 
 type Server struct {
@@ -25,6 +30,17 @@ type Scanner interface {
 	StatusText() string
 }
 
+// TelemetrySource is implemented by scanners that keep rolling read statistics.
+type TelemetrySource interface {
+	Telemetry() sdk.TelemetrySnapshot
+}
+
+// ProfileSwitcher is implemented by scanners with named inventory profiles.
+type ProfileSwitcher interface {
+	ProfileNames() []string
+	ApplyProfile(name string) (string, error)
+}
+
 func New(addr, webhookSecret string, svc *service.Service, scanner Scanner) *Server {
 	mux := http.NewServeMux()
 	s := &Server{
@@ -47,6 +63,8 @@ func New(addr, webhookSecret string, svc *service.Service, scanner Scanner) *Ser
 	mux.HandleFunc("/turbo", s.handleTurbo)
 	mux.HandleFunc("/scan/start", s.handleScanStart)
 	mux.HandleFunc("/scan/stop", s.handleScanStop)
+	mux.HandleFunc("/audit", s.handleAudit)
+	mux.HandleFunc("/profile", s.handleProfile)
 	return s
 }
 
@@ -78,7 +96,16 @@ func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
 }
 
 func (s *Server) handleStats(w http.ResponseWriter, _ *http.Request) {
-	writeJSON(w, http.StatusOK, s.svc.Status())
+	type statsResponse struct {
+		service.Stats
+		ReaderTelemetry *sdk.TelemetrySnapshot `json:"reader_telemetry,omitempty"`
+	}
+	resp := statsResponse{Stats: s.svc.Status()}
+	if src, ok := s.scanner.(TelemetrySource); ok {
+		snap := src.Telemetry()
+		resp.ReaderTelemetry = &snap
+	}
+	writeJSON(w, http.StatusOK, resp)
 }
 
 func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
@@ -100,7 +127,15 @@ func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
 
 	results := make([]service.IngestResult, 0, len(epcs))
 	for _, epc := range epcs {
-		results = append(results, s.svc.HandleEPC(r.Context(), epc, payload.Source))
+		results = append(results, s.svc.HandleRead(r.Context(), service.TagRead{
+			EPC:       epc,
+			Source:    payload.Source,
+			Reader:    payload.Reader,
+			Antenna:   payload.Antenna,
+			RSSI:      payload.RSSI,
+			TID:       payload.TID,
+			Direction: payload.Direction,
+		}))
 	}
 
 	writeJSON(w, http.StatusOK, map[string]any{
@@ -227,6 +262,99 @@ func (s *Server) handleScanStop(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{"ok": true, "stats": s.svc.Status()})
 }
 
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"ok": false, "error": "method not allowed"})
+		return
+	}
+
+	values := r.URL.Query()
+	q := audit.Query{
+		EPC:    erp.NormalizeEPC(values.Get("epc")),
+		Action: strings.TrimSpace(values.Get("action")),
+		Limit:  200,
+	}
+	if raw := strings.TrimSpace(values.Get("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"ok": false, "error": "invalid limit"})
+			return
+		}
+		q.Limit = n
+	}
+	var err error
+	if q.Since, err = parseQueryTime(values.Get("since")); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"ok": false, "error": "invalid since: " + err.Error()})
+		return
+	}
+	if q.Until, err = parseQueryTime(values.Get("until")); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"ok": false, "error": "invalid until: " + err.Error()})
+		return
+	}
+
+	entries, err := s.svc.AuditQuery(q)
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"ok": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"ok":      true,
+		"count":   len(entries),
+		"entries": entries,
+	})
+}
+
+// handleProfile lists profiles on GET and switches to {"name": ...} on POST.
+func (s *Server) handleProfile(w http.ResponseWriter, r *http.Request) {
+	switcher, ok := s.scanner.(ProfileSwitcher)
+	if !ok {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"ok": false, "error": "profiles not available"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{"ok": true, "profiles": switcher.ProfileNames()})
+	case http.MethodPost:
+		var payload struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&payload); err != nil || strings.TrimSpace(payload.Name) == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"ok": false, "error": "name is required"})
+			return
+		}
+		summary, err := switcher.ApplyProfile(strings.TrimSpace(payload.Name))
+		if err != nil && summary == "" {
+			writeJSON(w, http.StatusNotFound, map[string]any{"ok": false, "error": err.Error(), "profiles": switcher.ProfileNames()})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusOK, map[string]any{"ok": true, "profile": summary, "warning": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"ok": true, "profile": summary})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"ok": false, "error": "method not allowed"})
+	}
+}
+
+// parseQueryTime accepts RFC3339 or "2006-01-02 15:04[:05]" in local time.
+func parseQueryTime(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, raw, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported time %q", raw)
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -234,7 +362,12 @@ func writeJSON(w http.ResponseWriter, status int, payload any) {
 }
 
 type epcPayload struct {
-	EPC    string   `json:"epc"`
-	EPCs   []string `json:"epcs"`
-	Source string   `json:"source"`
+	EPC       string   `json:"epc"`
+	EPCs      []string `json:"epcs"`
+	Source    string   `json:"source"`
+	Reader    string   `json:"reader"`
+	Antenna   int      `json:"antenna"`
+	RSSI      int      `json:"rssi"`
+	TID       string   `json:"tid"`
+	Direction string   `json:"direction"`
 }
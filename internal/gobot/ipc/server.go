@@ -12,6 +12,8 @@ import (
 	"strings"
 	"time"
 
+	"new_era_go/internal/gobot/audit"
+	"new_era_go/internal/gobot/erp"
 	"new_era_go/internal/gobot/service"
 )
 
@@ -20,10 +22,17 @@ type Scanner interface {
 	Stop()
 }
 
+// ProfileSwitcher applies named inventory profiles at runtime.
+type ProfileSwitcher interface {
+	ProfileNames() []string
+	ApplyProfile(name string) (string, error)
+}
+
 type Server struct {
 	socketPath string
 	svc        *service.Service
 	scanner    Scanner
+	profiles   ProfileSwitcher
 }
 
 func New(socketPath string, svc *service.Service, scanner Scanner) *Server {
@@ -34,6 +43,12 @@ func New(socketPath string, svc *service.Service, scanner Scanner) *Server {
 	}
 }
 
+// SetProfileSwitcher enables the "profile" request. It is separate from the
+// scanner so profiles can be switched even when IPC does not drive the reader.
+func (s *Server) SetProfileSwitcher(profiles ProfileSwitcher) {
+	s.profiles = profiles
+}
+
 func (s *Server) Run(ctx context.Context) error {
 	if s.socketPath == "" || s.svc == nil {
 		return nil
@@ -144,13 +159,13 @@ func (s *Server) handleRequest(ctx context.Context, req request) response {
 		return response{OK: true, Action: "turbo", Stats: s.svc.Status()}
 
 	case "epc":
-		res := s.svc.HandleEPC(ctx, req.EPC, source)
+		res := s.svc.HandleRead(ctx, req.read(req.EPC, source))
 		return response{OK: true, Action: "epc", Results: []service.IngestResult{res}, Stats: s.svc.Status()}
 
 	case "epcs":
 		results := make([]service.IngestResult, 0, len(req.EPCs))
 		for _, epc := range req.EPCs {
-			results = append(results, s.svc.HandleEPC(ctx, epc, source))
+			results = append(results, s.svc.HandleRead(ctx, req.read(epc, source)))
 		}
 		return response{OK: true, Action: "epcs", Results: results, Stats: s.svc.Status()}
 
@@ -161,6 +176,38 @@ func (s *Server) handleRequest(ctx context.Context, req request) response {
 	case "draft_epcs":
 		added, replay := s.svc.AddDraftEPCs(ctx, req.EPCs)
 		return response{OK: true, Action: "draft_epcs", Added: added, Replay: replay, Stats: s.svc.Status()}
+
+	case "audit":
+		entries, err := s.svc.AuditQuery(audit.Query{
+			EPC:    erp.NormalizeEPC(req.EPC),
+			Action: req.Action,
+			Since:  req.Since,
+			Until:  req.Until,
+			Limit:  req.Limit,
+		})
+		if err != nil {
+			return response{OK: false, Action: "audit", Error: err.Error(), Stats: s.svc.Status()}
+		}
+		return response{OK: true, Action: "audit", Audit: entries, Stats: s.svc.Status()}
+
+	case "profile":
+		if s.profiles == nil {
+			return response{OK: false, Action: "profile", Error: "profiles not available", Stats: s.svc.Status()}
+		}
+		names := s.profiles.ProfileNames()
+		name := strings.TrimSpace(req.Profile)
+		if name == "" {
+			return response{OK: true, Action: "profile", Profiles: names, Stats: s.svc.Status()}
+		}
+		summary, err := s.profiles.ApplyProfile(name)
+		if err != nil && summary == "" {
+			return response{OK: false, Action: "profile", Error: err.Error(), Profiles: names, Stats: s.svc.Status()}
+		}
+		warn := ""
+		if err != nil {
+			warn = err.Error()
+		}
+		return response{OK: true, Action: "profile", Profile: summary, Warning: warn, Stats: s.svc.Status()}
 	}
 
 	return response{
@@ -171,19 +218,44 @@ func (s *Server) handleRequest(ctx context.Context, req request) response {
 }
 
 type request struct {
-	Type   string   `json:"type"`
-	Source string   `json:"source,omitempty"`
-	EPC    string   `json:"epc,omitempty"`
-	EPCs   []string `json:"epcs,omitempty"`
+	Type      string    `json:"type"`
+	Source    string    `json:"source,omitempty"`
+	EPC       string    `json:"epc,omitempty"`
+	EPCs      []string  `json:"epcs,omitempty"`
+	Reader    string    `json:"reader,omitempty"`
+	Antenna   int       `json:"antenna,omitempty"`
+	RSSI      int       `json:"rssi,omitempty"`
+	TID       string    `json:"tid,omitempty"`
+	Direction string    `json:"direction,omitempty"`
+	Action    string    `json:"action,omitempty"`
+	Since     time.Time `json:"since,omitempty"`
+	Until     time.Time `json:"until,omitempty"`
+	Limit     int       `json:"limit,omitempty"`
+	Profile   string    `json:"profile,omitempty"`
+}
+
+func (r request) read(epc, source string) service.TagRead {
+	return service.TagRead{
+		EPC:       epc,
+		Source:    source,
+		Reader:    r.Reader,
+		Antenna:   r.Antenna,
+		RSSI:      r.RSSI,
+		TID:       r.TID,
+		Direction: r.Direction,
+	}
 }
 
 type response struct {
-	OK      bool                   `json:"ok"`
-	Action  string                 `json:"action,omitempty"`
-	Error   string                 `json:"error,omitempty"`
-	Warning string                 `json:"warning,omitempty"`
-	Replay  int                    `json:"replayed_seen,omitempty"`
-	Added   int                    `json:"added_to_cache,omitempty"`
-	Results []service.IngestResult `json:"results,omitempty"`
-	Stats   service.Stats          `json:"stats"`
+	OK       bool                   `json:"ok"`
+	Action   string                 `json:"action,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+	Warning  string                 `json:"warning,omitempty"`
+	Replay   int                    `json:"replayed_seen,omitempty"`
+	Added    int                    `json:"added_to_cache,omitempty"`
+	Results  []service.IngestResult `json:"results,omitempty"`
+	Audit    []audit.Entry          `json:"audit,omitempty"`
+	Profile  string                 `json:"profile,omitempty"`
+	Profiles []string               `json:"profiles,omitempty"`
+	Stats    service.Stats          `json:"stats"`
 }
@@ -9,12 +9,22 @@ import (
 	"time"
 
 	"new_era_go/internal/gobot/config"
+	"new_era_go/internal/gobot/filter"
 	"new_era_go/sdk"
 )
 
-type EPCHandler func(epc string)
+// TagHandler receives each tag not seen within the dedup window
+// (BOT_READER_DEDUP_SEC) together with the reader endpoint.
+type TagHandler func(endpoint string, tag sdk.TagEvent)
 type Notifier func(text string)
 
+// DirectionHandler receives one decided pass per tag from the portal detector.
+type DirectionHandler func(endpoint string, ev sdk.DirectionEvent)
+
+// TIDVerifier judges every read that carries a TID, before dedup; false
+// drops the read (for example a cloned EPC).
+type TIDVerifier func(endpoint string, tag sdk.TagEvent) bool
+
 type Status struct {
 	Running      bool
 	Connected    bool
@@ -34,36 +44,110 @@ type Status struct {
 	RegionHigh   byte
 	RegionLow    byte
 	PerAntenna   int
+	DirectionIn  uint64
+	DirectionOut uint64
+	// SubscriberDrops counts reads lost to full Subscribe channels.
+	SubscriberDrops uint64
+	// Arrived and Departed count presence changes since the manager started.
+	Arrived  uint64
+	Departed uint64
+	// Drift lists settings the reader reported differently after the last start.
+	Drift          []string
+	DriftCheckedAt time.Time
 }
 
 type Manager struct {
 	cfg      config.Config
-	onEPC    EPCHandler
+	onTag    TagHandler
 	notifyFn Notifier
 
 	mu        sync.Mutex
 	running   bool
+	parent    context.Context
 	cancel    context.CancelFunc
 	done      chan struct{}
 	status    Status
 	invCfg    sdk.InventoryConfig
 	longRange bool
+
+	telemetry   *sdk.Telemetry
+	direction   *sdk.DirectionDetector
+	onDirection DirectionHandler
+	verifyTID   TIDVerifier
+	presence    *sdk.PresenceTracker
+	filters     *filter.Set
+	applied     *sdk.TagFilter
+	// dedup decides which reads reach onTag; it outlives reconnects. Nil
+	// falls back to the client's once-per-session IsNew.
+	dedup *sdk.Deduper
+
+	// client is the connected reader, used for I/O signals.
+	client     *sdk.Client
+	okSignal   sdk.Signal
+	failSignal sdk.Signal
+	signalBusy bool
+	tuning     bool
+
+	profiles *sdk.ProfileStore
+	recorder *sdk.Recorder
+	// fanout outlives reconnects, so subscribers keep their channel.
+	fanout sdk.TagFanout
+}
+
+// SetRecorder records the raw reader traffic of every later connection to
+// a capture file; nil stops recording.
+func (m *Manager) SetRecorder(r *sdk.Recorder) {
+	m.mu.Lock()
+	m.recorder = r
+	m.mu.Unlock()
 }
 
-func New(cfg config.Config, onEPC EPCHandler, notify Notifier) *Manager {
-	invCfg := sdk.DefaultInventoryConfig()
+// Subscribe returns a channel of every read from the reader, not only new
+// tags, across reconnects. cancel closes it.
+func (m *Manager) Subscribe(filter sdk.SubscribeFilter) (<-chan sdk.TagEvent, func()) {
+	return m.fanout.Subscribe(filter)
+}
+
+func New(cfg config.Config, onTag TagHandler, notify Notifier) *Manager {
+	invCfg := withReaderOptions(sdk.DefaultInventoryConfig(), cfg)
+	var direction *sdk.DirectionDetector
+	portal := sdk.DirectionConfig{
+		InsideAntenna:  cfg.DirectionInsideAnt,
+		OutsideAntenna: cfg.DirectionOutsideAnt,
+		Window:         cfg.DirectionWindow,
+	}
+	if portal.Enabled() {
+		direction = sdk.NewDirectionDetector(portal)
+	}
+	var dedup *sdk.Deduper
+	if cfg.ReaderDedup > 0 {
+		dedup = sdk.NewDeduper(cfg.ReaderDedup, cfg.ReaderDedupMax)
+	}
+	var presence *sdk.PresenceTracker
+	if cfg.ReaderPresence > 0 {
+		presence = sdk.NewPresenceTracker(sdk.PresenceConfig{AbsenceTimeout: cfg.ReaderPresence})
+	}
 	return &Manager{
-		cfg:      cfg,
-		onEPC:    onEPC,
-		notifyFn: notify,
-		invCfg:   invCfg,
+		cfg:        cfg,
+		onTag:      onTag,
+		notifyFn:   notify,
+		invCfg:     invCfg,
+		telemetry:  sdk.NewTelemetry(cfg.ReaderTelemetry),
+		direction:  direction,
+		presence:   presence,
+		dedup:      dedup,
+		okSignal:   signalFromConfig(cfg.SignalOK, cfg.SignalPulse),
+		failSignal: signalFromConfig(cfg.SignalFail, cfg.SignalPulse),
+		profiles:   sdk.NewProfileStore(""),
 		status: Status{
 			ScanProfile: "balanced",
 			OutputPower: invCfg.OutputPower,
 			ScanTime:    invCfg.ScanTime,
 			PollCycle:   invCfg.EffectiveInterval(),
 			AntennaMask: invCfg.AntennaMask,
-			RegionCode:  "-",
+			RegionCode:  fallback(invCfg.RegionCode(), "-"),
+			RegionHigh:  invCfg.RegionHigh,
+			RegionLow:   invCfg.RegionLow,
 		},
 	}
 }
@@ -74,6 +158,49 @@ func (m *Manager) SetNotifier(notify Notifier) {
 	m.mu.Unlock()
 }
 
+// SetDirectionHandler registers the receiver of portal in/out decisions.
+func (m *Manager) SetDirectionHandler(handler DirectionHandler) {
+	m.mu.Lock()
+	m.onDirection = handler
+	m.mu.Unlock()
+}
+
+// SetTIDVerifier registers the TID check run on every read with a TID.
+func (m *Manager) SetTIDVerifier(verify TIDVerifier) {
+	m.mu.Lock()
+	m.verifyTID = verify
+	m.mu.Unlock()
+}
+
+// SetFilters installs per-reader read filters; they apply from the next connect.
+func (m *Manager) SetFilters(set *filter.Set) {
+	m.mu.Lock()
+	m.filters = set
+	m.mu.Unlock()
+}
+
+// AppliedFilter returns the filter installed on the current connection;
+// reads from the scanner have already passed it.
+func (m *Manager) AppliedFilter() *sdk.TagFilter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.applied
+}
+
+// Present returns the tags currently in the reader field; nil unless
+// BOT_READER_PRESENCE_SEC is set.
+func (m *Manager) Present() []sdk.PresentTag {
+	if m.presence == nil {
+		return nil
+	}
+	return m.presence.Present()
+}
+
+// DirectionEnabled reports whether inside/outside portal antennas are configured.
+func (m *Manager) DirectionEnabled() bool {
+	return m.direction != nil
+}
+
 func (m *Manager) Start(parent context.Context) error {
 	m.mu.Lock()
 	if m.running {
@@ -82,6 +209,7 @@ func (m *Manager) Start(parent context.Context) error {
 	}
 	ctx, cancel := context.WithCancel(parent)
 	m.running = true
+	m.parent = parent
 	m.cancel = cancel
 	m.done = make(chan struct{})
 	m.status.Running = true
@@ -117,7 +245,7 @@ func (m *Manager) Status() Status {
 
 func (m *Manager) StatusText() string {
 	st := m.Status()
-	return fmt.Sprintf(
+	text := fmt.Sprintf(
 		"running=%v connected=%v endpoint=%s\nprofile=%s power=0x%02X scan=%d cycle=%s ant_mask=0x%02X region=%s [0x%02X/0x%02X] per_ant=%d\nseen=%d last_tag=%s at=%s\nrestarts=%d last_error=%s",
 		st.Running,
 		st.Connected,
@@ -137,38 +265,101 @@ func (m *Manager) StatusText() string {
 		st.RestartCount,
 		fallback(st.LastError, "-"),
 	)
+	if !st.DriftCheckedAt.IsZero() {
+		drift := "ok"
+		if len(st.Drift) > 0 {
+			drift = strings.Join(st.Drift, "; ")
+		}
+		text += "\nreadback: " + drift
+	}
+	m.mu.Lock()
+	client := m.client
+	m.mu.Unlock()
+	if client != nil {
+		cs := client.Stats()
+		text += fmt.Sprintf("\nrx: overflow=%s dropped packets=%d tags=%d crc_err=%d resync=%d truncated=%d",
+			fallback(m.cfg.ReaderOverflow, "drop-newest"), cs.DroppedPackets, cs.DroppedTags, cs.CRCErrors, cs.ResyncBytes, cs.BufferTruncations)
+		if cs.EmptyEPCReads > 0 {
+			text += fmt.Sprintf(" empty_epc=%d", cs.EmptyEPCReads)
+		}
+		if cs.TIDReadErrors > 0 {
+			text += fmt.Sprintf(" tid_err=%d", cs.TIDReadErrors)
+		}
+	}
+	if m.dedup != nil {
+		text += fmt.Sprintf("\ndedup: window=%s remembered=%d", m.dedup.Window(), m.dedup.Len())
+	}
+	if m.presence != nil {
+		text += fmt.Sprintf("\npresence: in_field=%d arrived=%d departed=%d timeout=%s",
+			m.presence.Len(), st.Arrived, st.Departed, m.presence.Config().AbsenceTimeout)
+	}
+	if subs := m.fanout.Len(); subs > 0 {
+		text += fmt.Sprintf("\nsubscribers=%d dropped=%d", subs, st.SubscriberDrops)
+	}
+	if m.cfg.ReaderWorkMode != "" && m.cfg.ReaderWorkMode != "answer" {
+		text += fmt.Sprintf("\nwork_mode=%s tag_time=%ds frame=%s", m.cfg.ReaderWorkMode, m.cfg.ReaderStreamTagTime, m.cfg.ReaderStreamFrame)
+	}
+	if m.cfg.ReaderEPCMask != "" {
+		text += fmt.Sprintf("\nepc_mask=%s (match)", m.cfg.ReaderEPCMask)
+	}
+	if m.direction != nil {
+		portal := m.direction.Config()
+		text += fmt.Sprintf("\nportal: inside=ANT%d outside=ANT%d window=%s in=%d out=%d",
+			portal.InsideAntenna, portal.OutsideAntenna, portal.Window, st.DirectionIn, st.DirectionOut)
+	}
+	return text
+}
+
+// Telemetry returns rolling read statistics; the window survives reconnects.
+func (m *Manager) Telemetry() sdk.TelemetrySnapshot {
+	return m.telemetry.Snapshot()
+}
+
+// TelemetryText renders per-antenna and top-EPC statistics for chat.
+func (m *Manager) TelemetryText(topTags int) string {
+	snap := m.Telemetry()
+	if snap.Reads == 0 {
+		return fmt.Sprintf("Telemetry (%s): o'qish yo'q", snap.Window)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Telemetry (%s): reads=%d (%.1f/s) unique=%d (%.2f/s)",
+		snap.Window, snap.Reads, snap.ReadsPerSec, snap.UniqueTags, snap.UniquePerSec)
+	for _, ant := range snap.Antennas {
+		fmt.Fprintf(&b, "\nANT%d: reads=%d (%.1f/s) unique=%d rssi=%s",
+			ant.Antenna, ant.Reads, ant.ReadsPerSec, ant.UniqueTags, formatRSSI(ant.RSSI))
+	}
+	if topTags > len(snap.Tags) {
+		topTags = len(snap.Tags)
+	}
+	for _, tag := range snap.Tags[:max(topTags, 0)] {
+		fmt.Fprintf(&b, "\n%s reads=%d ant=%v rssi=%s",
+			trimEPC(tag.EPC), tag.Reads, tag.Antennas, formatRSSI(tag.RSSI))
+	}
+	return b.String()
 }
 
-func (m *Manager) SetLongRangeMode(enabled bool) string {
+// SetLongRangeMode switches between the balanced and long-range profiles
+// and applies the result live. sdk.ErrRestartRequired means the caller must
+// restart a running scan.
+func (m *Manager) SetLongRangeMode(enabled bool) (string, error) {
 	nextCfg := sdk.DefaultInventoryConfig()
 	profile := "balanced"
-	regionCode := "-"
 	if enabled {
-		nextCfg, regionCode = longRangeInventoryConfig()
+		nextCfg = longRangeInventoryConfig()
 		profile = "long_range"
 	}
+	nextCfg = withReaderOptions(nextCfg, m.cfg)
+	regionCode := fallback(nextCfg.RegionCode(), "-")
 
 	m.mu.Lock()
 	m.longRange = enabled
-	m.invCfg = nextCfg
-	m.status.ScanProfile = profile
-	m.status.OutputPower = nextCfg.OutputPower
-	m.status.ScanTime = nextCfg.ScanTime
-	m.status.PollCycle = nextCfg.EffectiveInterval()
-	m.status.AntennaMask = nextCfg.AntennaMask
-	m.status.RegionCode = regionCode
-	if nextCfg.RegionSet {
-		m.status.RegionHigh = nextCfg.RegionHigh
-		m.status.RegionLow = nextCfg.RegionLow
-	} else {
-		m.status.RegionHigh = 0
-		m.status.RegionLow = 0
-	}
-	m.status.PerAntenna = len(nextCfg.PerAntennaPower)
+	m.setInventoryLocked(profile, nextCfg)
 	m.mu.Unlock()
 
+	summary := "long-range o'chirildi: balanced profilga qaytdi"
 	if enabled {
-		return fmt.Sprintf(
+		summary = fmt.Sprintf(
 			"long-range yoqildi: power=0x%02X scan=%d cycle=%s mask=0x%02X region=%s [0x%02X/0x%02X] per_ant=%d",
 			nextCfg.OutputPower,
 			nextCfg.ScanTime,
@@ -180,7 +371,30 @@ func (m *Manager) SetLongRangeMode(enabled bool) string {
 			len(nextCfg.PerAntennaPower),
 		)
 	}
-	return "long-range o'chirildi: balanced profilga qaytdi"
+	note, err := m.applyInventory(nextCfg)
+	if note == "" {
+		return summary, err
+	}
+	return summary + "\n" + note, err
+}
+
+// setInventoryLocked stages cfg for the next start and mirrors it in status.
+func (m *Manager) setInventoryLocked(profile string, cfg sdk.InventoryConfig) {
+	m.invCfg = cfg
+	m.status.ScanProfile = profile
+	m.status.OutputPower = cfg.OutputPower
+	m.status.ScanTime = cfg.ScanTime
+	m.status.PollCycle = cfg.EffectiveInterval()
+	m.status.AntennaMask = cfg.AntennaMask
+	m.status.RegionCode = fallback(cfg.RegionCode(), "-")
+	if cfg.RegionSet {
+		m.status.RegionHigh = cfg.RegionHigh
+		m.status.RegionLow = cfg.RegionLow
+	} else {
+		m.status.RegionHigh = 0
+		m.status.RegionLow = 0
+	}
+	m.status.PerAntenna = len(cfg.PerAntennaPower)
 }
 
 func (m *Manager) LongRangeMode() bool {
@@ -197,6 +411,8 @@ func (m *Manager) scanLoop(ctx context.Context, done chan struct{}) {
 	if retry < 500*time.Millisecond {
 		retry = 2 * time.Second
 	}
+	// A replay file is played once; reconnecting would play it again.
+	replay := m.cfg.ReaderReplayFile != ""
 
 	for {
 		select {
@@ -206,10 +422,19 @@ func (m *Manager) scanLoop(ctx context.Context, done chan struct{}) {
 		}
 
 		client := sdk.NewClient()
+		// config.Load already validated the policy.
+		overflow, _ := sdk.ParseOverflowPolicy(m.cfg.ReaderOverflow)
+		client.SetOverflowPolicy(overflow)
+		m.mu.Lock()
+		client.SetRecorder(m.recorder)
+		m.mu.Unlock()
 		connected, err := m.connectAndStart(ctx, client)
 		if err != nil {
 			m.setError(err)
 			log.Printf("[reader] start failed: %v", err)
+			if replay {
+				return
+			}
 			if !sleepWithContext(ctx, retry) {
 				return
 			}
@@ -219,8 +444,14 @@ func (m *Manager) scanLoop(ctx context.Context, done chan struct{}) {
 		if connected {
 			m.notify("RFID scan boshlandi: " + m.Status().Endpoint)
 		}
+		m.mu.Lock()
+		m.client = client
+		m.mu.Unlock()
 
 		shouldReconnect := m.consumeTags(ctx, client)
+		m.mu.Lock()
+		m.client = nil
+		m.mu.Unlock()
 		_ = client.StopInventory()
 		_ = client.Close()
 
@@ -229,6 +460,12 @@ func (m *Manager) scanLoop(ctx context.Context, done chan struct{}) {
 		m.status.Endpoint = ""
 		m.mu.Unlock()
 
+		if replay {
+			if shouldReconnect {
+				m.notify("RFID replay tugadi: " + m.cfg.ReaderReplayFile)
+			}
+			return
+		}
 		if !shouldReconnect {
 			return
 		}
@@ -249,7 +486,23 @@ func (m *Manager) connectAndStart(ctx context.Context, client *sdk.Client) (bool
 	}
 
 	var endpoint string
-	if m.cfg.ReaderHost != "" && m.cfg.ReaderPort > 0 {
+	if m.cfg.ReaderReplayFile != "" {
+		target := sdk.Endpoint{Replay: sdk.ReplayConfig{File: m.cfg.ReaderReplayFile}}
+		if err := client.Reconnect(ctx, target, timeout); err != nil {
+			return false, err
+		}
+		endpoint = target.Address()
+	} else if m.cfg.ReaderSerial != "" {
+		// config.Load already validated the spec.
+		line, _ := sdk.ParseSerial(m.cfg.ReaderSerial)
+		target := sdk.Endpoint{Serial: line}
+		// The reader address picks this client's frames on an RS-485 bus.
+		client.SetInventoryConfig(m.inventoryConfig())
+		if err := client.Reconnect(ctx, target, timeout); err != nil {
+			return false, fmt.Errorf("serial connect %s: %w", line, err)
+		}
+		endpoint = target.Address()
+	} else if m.cfg.ReaderHost != "" && m.cfg.ReaderPort > 0 {
 		dialCtx, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 		target := sdk.Endpoint{Host: m.cfg.ReaderHost, Port: m.cfg.ReaderPort}
@@ -291,6 +544,11 @@ func (m *Manager) connectAndStart(ctx context.Context, client *sdk.Client) (bool
 
 	cfg := m.inventoryConfig()
 	client.SetInventoryConfig(cfg)
+	m.mu.Lock()
+	m.applied = m.filters.For(endpoint)
+	client.SetTagFilter(m.applied)
+	m.mu.Unlock()
+	client.SetPresence(m.presence)
 
 	if err := client.StartInventory(ctx); err != nil {
 		return false, fmt.Errorf("start inventory: %w", err)
@@ -304,6 +562,7 @@ func (m *Manager) connectAndStart(ctx context.Context, client *sdk.Client) (bool
 	m.status.ScanTime = cfg.ScanTime
 	m.status.PollCycle = cfg.EffectiveInterval()
 	m.status.AntennaMask = cfg.AntennaMask
+	m.status.RegionCode = fallback(cfg.RegionCode(), "-")
 	if cfg.RegionSet {
 		m.status.RegionHigh = cfg.RegionHigh
 		m.status.RegionLow = cfg.RegionLow
@@ -312,46 +571,137 @@ func (m *Manager) connectAndStart(ctx context.Context, client *sdk.Client) (bool
 		m.status.RegionLow = 0
 	}
 	m.status.PerAntenna = len(cfg.PerAntennaPower)
+	m.status.Drift = nil
+	m.status.DriftCheckedAt = time.Time{}
 	m.mu.Unlock()
+
+	go m.checkDrift(ctx, client, endpoint, cfg)
 	return true, nil
 }
 
+// checkDrift reads the live reader config back and warns when it differs
+// from what was just applied.
+func (m *Manager) checkDrift(ctx context.Context, client *sdk.Client, endpoint string, want sdk.InventoryConfig) {
+	readCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	got, err := client.ReadConfig(readCtx)
+	if err != nil {
+		log.Printf("[reader] config readback failed: %v", err)
+		return
+	}
+
+	drift := sdk.DiffConfig(want, got)
+	lines := make([]string, 0, len(drift))
+	for _, d := range drift {
+		lines = append(lines, d.String())
+	}
+	m.mu.Lock()
+	m.status.Drift = lines
+	m.status.DriftCheckedAt = time.Now()
+	m.mu.Unlock()
+
+	if len(lines) > 0 {
+		log.Printf("[reader] config drift on %s: %s", endpoint, strings.Join(lines, "; "))
+		m.notify("⚠️ Reader sozlamasi farq qiladi (" + endpoint + "):\n" + strings.Join(lines, "\n"))
+	}
+}
+
 func (m *Manager) inventoryConfig() sdk.InventoryConfig {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	return m.invCfg
 }
 
+func (m *Manager) tidAccepted(tag sdk.TagEvent) bool {
+	m.mu.Lock()
+	verify := m.verifyTID
+	endpoint := m.status.Endpoint
+	m.mu.Unlock()
+	return verify == nil || verify(endpoint, tag)
+}
+
+// isTuning reports whether an AutoTune sweep owns the reader.
+func (m *Manager) isTuning() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tuning
+}
+
 func (m *Manager) consumeTags(ctx context.Context, client *sdk.Client) bool {
 	tags := client.Tags()
 	errs := client.Errors()
+	var presence <-chan sdk.PresenceEvent
+	if m.presence != nil {
+		presence = client.PresenceEvents()
+	}
+	overflow, _ := sdk.ParseOverflowPolicy(m.cfg.ReaderOverflow)
+
+	var flush <-chan time.Time
+	if m.direction != nil {
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+		flush = ticker.C
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
 			return false
+		case now := <-flush:
+			m.dispatchDirection(m.direction.Flush(now))
 		case tag, ok := <-tags:
 			if !ok {
 				m.setError(fmt.Errorf("tag channel closed"))
 				return true
 			}
-			if !tag.IsNew {
-				continue
-			}
 			epc := strings.TrimSpace(tag.EPC)
 			if epc == "" {
 				continue
 			}
+			tag.EPC = epc
+			m.telemetry.Observe(tag)
+			if lost := m.fanout.Publish(tag, overflow, ctx.Done()); lost > 0 {
+				m.mu.Lock()
+				m.status.SubscriberDrops += uint64(lost)
+				m.mu.Unlock()
+			}
+			// Sweep reads are measurements, not hand-ins: keep them out of
+			// dedup, direction and ERP submission.
+			if m.isTuning() {
+				continue
+			}
+			if tag.TID != "" && !m.tidAccepted(tag) {
+				continue
+			}
+			if m.direction != nil {
+				m.dispatchDirection(m.direction.Observe(tag))
+			}
+			if m.dedup != nil {
+				tag.IsNew = m.dedup.Check(epc, tag.When)
+			}
+			if !tag.IsNew {
+				continue
+			}
 
 			m.mu.Lock()
 			m.status.UniqueSeen++
 			m.status.LastTagAt = time.Now()
 			m.status.LastTagEPC = epc
+			endpoint := m.status.Endpoint
 			m.mu.Unlock()
 
-			if m.onEPC != nil {
-				m.onEPC(epc)
+			if m.onTag != nil {
+				m.onTag(endpoint, tag)
+			}
+		case ev := <-presence:
+			m.mu.Lock()
+			switch ev.Kind {
+			case sdk.TagArrived:
+				m.status.Arrived++
+			case sdk.TagDeparted:
+				m.status.Departed++
 			}
+			m.mu.Unlock()
 		case err, ok := <-errs:
 			if !ok {
 				m.setError(fmt.Errorf("error channel closed"))
@@ -365,6 +715,31 @@ func (m *Manager) consumeTags(ctx context.Context, client *sdk.Client) bool {
 	}
 }
 
+func (m *Manager) dispatchDirection(events []sdk.DirectionEvent) {
+	if len(events) == 0 {
+		return
+	}
+	m.mu.Lock()
+	endpoint := m.status.Endpoint
+	handler := m.onDirection
+	for _, ev := range events {
+		switch ev.Direction {
+		case sdk.DirectionIn:
+			m.status.DirectionIn++
+		case sdk.DirectionOut:
+			m.status.DirectionOut++
+		}
+	}
+	m.mu.Unlock()
+
+	if handler == nil {
+		return
+	}
+	for _, ev := range events {
+		handler(endpoint, ev)
+	}
+}
+
 func (m *Manager) setError(err error) {
 	if err == nil {
 		return
@@ -420,6 +795,13 @@ func trimEPC(epc string) string {
 	return epc[:16] + "..."
 }
 
+func formatRSSI(r sdk.RSSIStats) string {
+	if r.Samples == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%.0f/%d", r.Min, r.Avg, r.Max)
+}
+
 func formatTime(t time.Time) string {
 	if t.IsZero() {
 		return "never"
@@ -427,33 +809,36 @@ func formatTime(t time.Time) string {
 	return t.Format(time.RFC3339)
 }
 
-func longRangeInventoryConfig() (sdk.InventoryConfig, string) {
-	cfg := sdk.DefaultInventoryConfig()
-	// Vendor Java manual exposes SetRfPower range as 0..30.
-	cfg.OutputPower = 30
-	cfg.ScanTime = 10
-	cfg.QValue = 4
-	cfg.Session = 0
-	cfg.NoTagABSwitch = 0
-	cfg.SingleFallbackEach = 4
-	cfg.PollInterval = 200 * time.Millisecond
-	cfg.AntennaMask = 0x0F
-
-	regionCode, high, low := defaultRange20Region()
-	cfg.RegionSet = true
-	cfg.RegionHigh = high
-	cfg.RegionLow = low
-	cfg.PerAntennaPower = []byte{30, 30, 30, 30, 0, 0, 0, 0}
-	return cfg, regionCode
-}
-
-func defaultRange20Region() (string, byte, byte) {
-	high, low := encodeRegion(2, 49, 0)
-	return "US", high, low
+// withReaderOptions applies env-level reader settings that every profile keeps.
+func withReaderOptions(inv sdk.InventoryConfig, cfg config.Config) sdk.InventoryConfig {
+	inv.TIDAddr = byte(cfg.ReaderTIDAddr)
+	inv.TIDWords = byte(cfg.ReaderTIDWords)
+	inv.AdaptiveQ = cfg.ReaderAdaptive
+	inv.AdaptiveSession = cfg.ReaderAdaptive
+	if cfg.ReaderAddress >= 0 {
+		inv.ReaderAddress = byte(cfg.ReaderAddress)
+		inv.AutoAddress = false
+	}
+	if mode, ok := sdk.WorkModeByName(cfg.ReaderWorkMode); ok {
+		inv.WorkMode = mode
+		inv.StreamTagTime = byte(cfg.ReaderStreamTagTime)
+	}
+	if layout, ok := sdk.ActiveFrameByName(cfg.ReaderStreamFrame); ok {
+		inv.StreamFrame = layout
+	}
+	if cfg.ReaderRegion != "" {
+		// config.Load already validated the code.
+		_ = inv.SetRegion(cfg.ReaderRegion)
+	}
+	// config.Load already validated the prefix.
+	if mask, err := sdk.EPCPrefixMask(cfg.ReaderEPCMask); err == nil {
+		inv.Mask = mask
+	}
+	return inv
 }
 
-func encodeRegion(band, maxFre, minFre int) (byte, byte) {
-	high := byte(((band & 0x0C) << 4) | (maxFre & 0x3F))
-	low := byte(((band & 0x03) << 6) | (minFre & 0x3F))
-	return high, low
+// longRangeInventoryConfig is the built-in long-range profile; the full US
+// band applies unless BOT_READER_REGION overrides it.
+func longRangeInventoryConfig() sdk.InventoryConfig {
+	return sdk.LongRangeInventoryConfig()
 }
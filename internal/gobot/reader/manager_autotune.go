@@ -0,0 +1,71 @@
+package reader
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"new_era_go/sdk"
+)
+
+// AutoTune sweeps power and scan time on the connected reader against the
+// reference EPCs. With apply the recommended profile replaces the current
+// one until the next /range20 or /profile switch. Reads seen during the
+// sweep still feed telemetry and subscribers but are not passed to onTag,
+// so nothing is submitted to ERP while tuning.
+func (m *Manager) AutoTune(ctx context.Context, reference []string, apply bool) (string, error) {
+	m.mu.Lock()
+	client := m.client
+	if client == nil {
+		m.mu.Unlock()
+		return "", fmt.Errorf("reader ulanmagan, avval /scan")
+	}
+	if m.tuning {
+		m.mu.Unlock()
+		return "", fmt.Errorf("auto-tune allaqachon ishlayapti")
+	}
+	m.tuning = true
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.tuning = false
+		m.mu.Unlock()
+	}()
+
+	report, err := client.AutoTune(ctx, sdk.AutoTuneOptions{Reference: reference, Apply: apply})
+	if len(report.Results) == 0 {
+		if err == nil {
+			err = fmt.Errorf("no candidates measured")
+		}
+		return "", err
+	}
+	if report.Applied {
+		cfg := report.Recommended
+		m.mu.Lock()
+		m.longRange = false
+		m.setInventoryLocked("autotune", cfg)
+		m.mu.Unlock()
+	}
+	return autoTuneText(report), err
+}
+
+func autoTuneText(report sdk.AutoTuneReport) string {
+	var b strings.Builder
+	best := report.Best
+	fmt.Fprintf(&b, "Auto-tune: eng yaxshi power=%d scan=%d topildi=%d/%d stray=%d reads/s=%.1f",
+		best.OutputPower, best.ScanTime, best.Found, best.Expected, best.Stray, best.ReadsPerSec)
+	if len(report.Recommended.PerAntennaPower) > 0 {
+		fmt.Fprintf(&b, "\nper_ant power=%v", report.Recommended.PerAntennaPower)
+	}
+	limit := min(len(report.Results), 5)
+	b.WriteString("\nTop natijalar:")
+	for _, r := range report.Results[:limit] {
+		b.WriteString("\n" + r.String())
+	}
+	if report.Applied {
+		b.WriteString("\nProfil qo'llandi (profile=autotune).")
+	} else {
+		b.WriteString("\nQo'llash uchun: /autotune apply")
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"new_era_go/sdk"
+)
+
+// liveApplyTimeout bounds one live config update, including long scan times.
+const liveApplyTimeout = 15 * time.Second
+
+// applyInventory makes a staged config take effect. A connected reader gets
+// it live between inventory rounds; otherwise the next connect picks it up.
+// A work mode change returns sdk.ErrRestartRequired and the caller restarts
+// the scan loop. It returns a note for the user.
+func (m *Manager) applyInventory(cfg sdk.InventoryConfig) (string, error) {
+	m.mu.Lock()
+	client := m.client
+	running := m.running
+	parent := m.parent
+	endpoint := m.status.Endpoint
+	m.mu.Unlock()
+
+	if !running || parent == nil {
+		return "keyingi /scan da qo'llanadi", nil
+	}
+	if client == nil {
+		return "reader ulanganda qo'llanadi", nil
+	}
+
+	ctx, cancel := context.WithTimeout(parent, liveApplyTimeout)
+	defer cancel()
+	results, err := client.UpdateInventoryConfig(ctx, cfg)
+	if errors.Is(err, sdk.ErrRestartRequired) {
+		return "", err
+	}
+	go m.checkDrift(parent, client, endpoint, cfg)
+	return liveResultText(results), err
+}
+
+func liveResultText(results []sdk.SettingResult) string {
+	if len(results) == 0 {
+		return "live: reader sozlamalari o'zgarmadi, inventory parametrlari keyingi raunddan"
+	}
+	parts := make([]string, 0, len(results))
+	for _, r := range results {
+		parts = append(parts, r.String())
+	}
+	return "live: " + strings.Join(parts, " ")
+}
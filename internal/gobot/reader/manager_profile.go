@@ -0,0 +1,71 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"new_era_go/sdk"
+)
+
+// SetProfiles installs the named profile store used by ApplyProfile.
+func (m *Manager) SetProfiles(store *sdk.ProfileStore) {
+	if store == nil {
+		return
+	}
+	m.mu.Lock()
+	m.profiles = store
+	m.mu.Unlock()
+}
+
+// ProfileNames lists the profiles ApplyProfile accepts.
+func (m *Manager) ProfileNames() []string {
+	m.mu.Lock()
+	store := m.profiles
+	m.mu.Unlock()
+	return store.Names()
+}
+
+// ApplyProfile switches to a named profile. Reader options from env (address,
+// TID, mask, work mode) are kept; a running scan gets the new config live,
+// or restarts when the change cannot be applied live.
+func (m *Manager) ApplyProfile(name string) (string, error) {
+	m.mu.Lock()
+	store := m.profiles
+	m.mu.Unlock()
+
+	profile, ok := store.Get(name)
+	if !ok {
+		return "", fmt.Errorf("profil topilmadi: %s (bor: %s)", name, strings.Join(store.Names(), ", "))
+	}
+	nextCfg := withReaderOptions(profile.Apply(sdk.DefaultInventoryConfig()), m.cfg)
+
+	m.mu.Lock()
+	m.longRange = profile.Name == sdk.ProfileLongRange
+	m.setInventoryLocked(profile.Name, nextCfg)
+	m.mu.Unlock()
+
+	summary := fmt.Sprintf("profil %s: power=0x%02X scan=%d cycle=%s q=%d session=%d mask=0x%02X region=%s per_ant=%d",
+		profile.Name,
+		nextCfg.OutputPower,
+		nextCfg.ScanTime,
+		nextCfg.EffectiveInterval(),
+		nextCfg.QValue,
+		nextCfg.Session,
+		nextCfg.AntennaMask,
+		fallback(nextCfg.RegionCode(), "-"),
+		len(nextCfg.PerAntennaPower),
+	)
+	note, err := m.applyInventory(nextCfg)
+	if !errors.Is(err, sdk.ErrRestartRequired) {
+		return summary + "\n" + note, err
+	}
+	m.mu.Lock()
+	parent := m.parent
+	m.mu.Unlock()
+	m.Stop()
+	if err := m.Start(parent); err != nil {
+		return summary, fmt.Errorf("reader restart: %w", err)
+	}
+	return summary + " (reader qayta ishga tushirildi)", nil
+}
@@ -0,0 +1,53 @@
+package reader
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"new_era_go/sdk"
+)
+
+// SubmitSignal plays BOT_SIGNAL_OK or BOT_SIGNAL_FAIL on the connected
+// reader. It returns at once; a signal still playing makes it a no-op.
+func (m *Manager) SubmitSignal(ok bool) {
+	sig := m.failSignal
+	if ok {
+		sig = m.okSignal
+	}
+	if !sig.Enabled() {
+		return
+	}
+
+	m.mu.Lock()
+	client := m.client
+	if client == nil || m.signalBusy {
+		m.mu.Unlock()
+		return
+	}
+	m.signalBusy = true
+	m.mu.Unlock()
+
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			m.signalBusy = false
+			m.mu.Unlock()
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), sig.Pulse+5*time.Second)
+		defer cancel()
+		if err := client.Signal(ctx, sig); err != nil {
+			log.Printf("[reader] signal ok=%v failed: %v", ok, err)
+		}
+	}()
+}
+
+// signalFromConfig parses a BOT_SIGNAL_* spec already validated by config.Load.
+func signalFromConfig(spec string, pulse time.Duration) sdk.Signal {
+	sig, err := sdk.ParseSignal(spec)
+	if err != nil {
+		return sdk.Signal{}
+	}
+	sig.Pulse = pulse
+	return sig
+}
@@ -1,10 +1,18 @@
 package reader
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"new_era_go/internal/gobot/config"
+	reader18 "new_era_go/internal/protocol/reader18"
+	"new_era_go/sdk"
 )
 
 func TestSetLongRangeMode(t *testing.T) {
@@ -18,7 +26,7 @@ func TestSetLongRangeMode(t *testing.T) {
 		t.Fatalf("unexpected default power: 0x%02X", initial.OutputPower)
 	}
 
-	summaryOn := m.SetLongRangeMode(true)
+	summaryOn, _ := m.SetLongRangeMode(true)
 	if !strings.Contains(summaryOn, "long-range yoqildi") {
 		t.Fatalf("unexpected enable summary: %q", summaryOn)
 	}
@@ -36,7 +44,7 @@ func TestSetLongRangeMode(t *testing.T) {
 		t.Fatalf("unexpected per-antenna count after enable: %d", stOn.PerAntenna)
 	}
 
-	summaryOff := m.SetLongRangeMode(false)
+	summaryOff, _ := m.SetLongRangeMode(false)
 	if !strings.Contains(summaryOff, "o'chirildi") {
 		t.Fatalf("unexpected disable summary: %q", summaryOff)
 	}
@@ -51,3 +59,190 @@ func TestSetLongRangeMode(t *testing.T) {
 		t.Fatalf("unexpected region after disable: %q", stOff.RegionCode)
 	}
 }
+
+func TestTelemetryTextListsAntennasAndTopTags(t *testing.T) {
+	m := New(config.Config{}, nil, nil)
+	if text := m.TelemetryText(5); !strings.Contains(text, "o'qish yo'q") {
+		t.Fatalf("unexpected empty telemetry text: %q", text)
+	}
+
+	m.telemetry.Observe(sdk.TagEvent{EPC: "E200001", Antenna: 1, RSSI: 60})
+	m.telemetry.Observe(sdk.TagEvent{EPC: "E200001", Antenna: 2, RSSI: 72})
+	m.telemetry.Observe(sdk.TagEvent{EPC: "E200002", Antenna: 1, RSSI: 55})
+
+	text := m.TelemetryText(1)
+	for _, want := range []string{"reads=3", "ANT1: reads=2", "ANT2: reads=1", "rssi=55/58/60", "E200001 reads=2 ant=[1 2]"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("telemetry text missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "E200002 reads") {
+		t.Fatalf("expected only the top tag, got:\n%s", text)
+	}
+}
+
+func TestReaderOptionsSurviveProfileSwitch(t *testing.T) {
+	m := New(config.Config{ReaderTIDWords: 6, ReaderEPCMask: "E2", ReaderAdaptive: true}, nil, nil)
+	for _, longRange := range []bool{true, false} {
+		m.SetLongRangeMode(longRange)
+		cfg := m.inventoryConfig()
+		if !cfg.AdaptiveQ || !cfg.AdaptiveSession {
+			t.Fatalf("long_range=%v: adaptive tuning lost", longRange)
+		}
+		if cfg.TIDWords != 6 {
+			t.Fatalf("long_range=%v: TID words lost: %d", longRange, cfg.TIDWords)
+		}
+		if cfg.Mask.Length != 8 || cfg.Mask.Bank != 0x01 {
+			t.Fatalf("long_range=%v: unexpected mask: %+v", longRange, cfg.Mask)
+		}
+	}
+	if text := m.StatusText(); !strings.Contains(text, "epc_mask=E2 (match)") {
+		t.Fatalf("status should show EPC mask: %q", text)
+	}
+}
+
+func TestReaderRegionOverridesProfiles(t *testing.T) {
+	m := New(config.Config{ReaderRegion: "EU"}, nil, nil)
+	if st := m.Status(); st.RegionCode != "EU" || st.RegionHigh != 0x4E || st.RegionLow != 0x00 {
+		t.Fatalf("unexpected balanced region: %+v", st)
+	}
+	m.SetLongRangeMode(true)
+	if st := m.Status(); st.RegionCode != "EU" {
+		t.Fatalf("long-range must keep configured region, got %q", st.RegionCode)
+	}
+}
+
+func TestReaderWorkModeSurvivesProfileSwitch(t *testing.T) {
+	m := New(config.Config{ReaderWorkMode: "trigger-low", ReaderStreamTagTime: 3, ReaderStreamFrame: "epc"}, nil, nil)
+	m.SetLongRangeMode(true)
+	cfg := m.inventoryConfig()
+	if cfg.WorkMode != sdk.WorkModeTriggerLow || cfg.StreamTagTime != 3 || cfg.StreamFrame != sdk.ActiveFrameEPC {
+		t.Fatalf("unexpected work mode: mode=%d tag_time=%d frame=%d", cfg.WorkMode, cfg.StreamTagTime, cfg.StreamFrame)
+	}
+	if text := m.StatusText(); !strings.Contains(text, "work_mode=trigger-low tag_time=3s frame=epc") {
+		t.Fatalf("status should show work mode: %q", text)
+	}
+}
+
+func TestAutoTuneNeedsConnectedReader(t *testing.T) {
+	m := New(config.Config{}, nil, nil)
+	if _, err := m.AutoTune(context.Background(), []string{"E200AA"}, false); err == nil {
+		t.Fatal("expected error without a connected reader")
+	}
+
+	text := autoTuneText(sdk.AutoTuneReport{
+		Results:     []sdk.TuneResult{{OutputPower: 20, ScanTime: 1, Found: 2, Expected: 2}},
+		Best:        sdk.TuneResult{OutputPower: 20, ScanTime: 1, Found: 2, Expected: 2},
+		Recommended: sdk.InventoryConfig{PerAntennaPower: []byte{15, 20}},
+	})
+	if !strings.Contains(text, "power=20 scan=1 topildi=2/2") || !strings.Contains(text, "per_ant power=[15 20]") || !strings.Contains(text, "/autotune apply") {
+		t.Fatalf("unexpected report text: %q", text)
+	}
+}
+
+func TestApplyProfileFromStore(t *testing.T) {
+	store := sdk.NewProfileStore(filepath.Join(t.TempDir(), "profiles.json"))
+	if err := store.Put(sdk.Profile{Name: "dock", QValue: 6, Session: 2, AntennaMask: 0x03, ScanTime: 3, PollMS: 80, OutputPower: 22}); err != nil {
+		t.Fatalf("put profile: %v", err)
+	}
+	m := New(config.Config{ReaderTIDWords: 4}, nil, nil)
+	m.SetProfiles(store)
+
+	if _, err := m.ApplyProfile("missing"); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+	summary, err := m.ApplyProfile("dock")
+	if err != nil {
+		t.Fatalf("apply profile: %v", err)
+	}
+	if !strings.Contains(summary, "profil dock: power=0x16 scan=3") {
+		t.Fatalf("unexpected summary: %q", summary)
+	}
+	cfg := m.inventoryConfig()
+	if cfg.QValue != 6 || cfg.Session != 2 || cfg.AntennaMask != 0x03 || cfg.TIDWords != 4 {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if st := m.Status(); st.ScanProfile != "dock" || m.LongRangeMode() {
+		t.Fatalf("unexpected status: profile=%q long_range=%v", st.ScanProfile, m.LongRangeMode())
+	}
+
+	if _, err := m.ApplyProfile(sdk.ProfileLongRange); err != nil {
+		t.Fatalf("apply long-range: %v", err)
+	}
+	if !m.LongRangeMode() || m.Status().ScanTime != 0x0A {
+		t.Fatalf("long-range profile not applied: %+v", m.Status())
+	}
+}
+
+func TestLiveApplyReportsPerSetting(t *testing.T) {
+	m := New(config.Config{}, nil, nil)
+	if summary, _ := m.SetLongRangeMode(true); !strings.Contains(summary, "keyingi /scan da qo'llanadi") {
+		t.Fatalf("idle manager should defer the change: %q", summary)
+	}
+
+	text := liveResultText([]sdk.SettingResult{
+		{Setting: "scan_time"},
+		{Setting: "power", Err: fmt.Errorf("command 0x2F status 0x05")},
+	})
+	if text != "live: scan_time=ok power=command 0x2F status 0x05" {
+		t.Fatalf("unexpected live text: %q", text)
+	}
+	if text := liveResultText(nil); !strings.Contains(text, "o'zgarmadi") {
+		t.Fatalf("unexpected empty live text: %q", text)
+	}
+}
+
+func TestReplayPlaysOnce(t *testing.T) {
+	epc := []byte{0xE2, 0x00, 0x00, 0x42}
+	data := append([]byte{reader18.StatusNoTag, 0x01, 0x01, byte(len(epc))}, epc...)
+	frame := reader18.BuildCommand(0x00, reader18.CmdInventory, append(data, 0x55))
+	info := reader18.BuildCommand(0x00, reader18.CmdGetReaderInfo, []byte{reader18.StatusSuccess})
+	capture := fmt.Sprintf(`{"t":"2026-03-01T10:00:00Z","dir":"open","endpoint":"192.168.1.190:6000"}
+{"t":"2026-03-01T10:00:00.00Z","dir":"rx","hex":"%X"}
+{"t":"2026-03-01T10:00:00.30Z","dir":"rx","hex":"%X"}
+`, info, frame)
+	path := filepath.Join(t.TempDir(), "site.jsonl")
+	if err := os.WriteFile(path, []byte(capture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var reads, notes []string
+	m := New(config.Config{ReaderReplayFile: path, ReaderRetryDelay: time.Second},
+		func(_ string, tag sdk.TagEvent) {
+			mu.Lock()
+			reads = append(reads, tag.EPC)
+			mu.Unlock()
+		},
+		func(text string) {
+			mu.Lock()
+			notes = append(notes, text)
+			mu.Unlock()
+		})
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for m.Status().Running {
+		if time.Now().After(deadline) {
+			m.Stop()
+			t.Fatal("replay did not stop at the end of the file")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reads) != 1 || reads[0] != "E2000042" {
+		t.Fatalf("replay must deliver the capture once: %v", reads)
+	}
+	started := 0
+	for _, note := range notes {
+		if strings.HasPrefix(note, "RFID scan boshlandi") {
+			started++
+		}
+	}
+	if started != 1 {
+		t.Fatalf("start notice sent %d times: %v", started, notes)
+	}
+}
@@ -8,15 +8,43 @@ import (
 	"sync"
 	"time"
 
+	"new_era_go/internal/gobot/audit"
 	"new_era_go/internal/gobot/cache"
 	"new_era_go/internal/gobot/config"
 	"new_era_go/internal/gobot/erp"
+	"new_era_go/internal/gobot/filter"
+	"new_era_go/internal/tds"
+	"new_era_go/sdk"
 )
 
 type Notifier interface {
 	Notify(text string)
 }
 
+// Signaler gives operator feedback at the reader (buzzer, stack light)
+// after each ERP submit outcome: true for "submitted", false for
+// "not_found" and errors.
+type Signaler interface {
+	SubmitSignal(ok bool)
+}
+
+// TagRead is one EPC observation with optional reader metadata.
+type TagRead struct {
+	EPC     string
+	Source  string
+	Reader  string
+	Antenna int
+	RSSI    int
+	// Direction is "in"/"out" when a portal detector decided the pass.
+	Direction string
+	// TID is the tag's TID in hex when the reader reports it; cached EPCs
+	// are bound to the first TID seen.
+	TID string
+	// Prefilter is the filter the SDK scanner already applied to the read.
+	// The service skips its own rules only when they are that same filter.
+	Prefilter *sdk.TagFilter
+}
+
 type IngestResult struct {
 	EPC    string `json:"epc"`
 	Action string `json:"action"`
@@ -40,6 +68,10 @@ type Stats struct {
 	SubmitErrors   uint64 `json:"submit_errors"`
 	QueueDropped   uint64 `json:"queue_dropped"`
 	ScanInactive   uint64 `json:"scan_inactive"`
+	DirectionSkip  uint64 `json:"direction_skipped"`
+	Filtered       uint64 `json:"filtered"`
+	TIDMismatch    uint64 `json:"tid_mismatch"`
+	DryRun         uint64 `json:"dry_run"`
 }
 
 type Service struct {
@@ -52,6 +84,7 @@ type Service struct {
 	inflight    map[string]struct{}
 	queued      map[string]struct{}
 	recentSeen  map[string]time.Time
+	tids        *tidTable
 	draftCount  int
 	lastRefresh time.Time
 	lastErr     string
@@ -59,6 +92,9 @@ type Service struct {
 	scanSince   time.Time
 	stats       Stats
 	notifier    Notifier
+	signaler    Signaler
+	auditLog    *audit.Log
+	filters     *filter.Set
 }
 
 func New(cfg config.Config, erpClient *erp.Client, c *cache.Store) *Service {
@@ -75,6 +111,7 @@ func New(cfg config.Config, erpClient *erp.Client, c *cache.Store) *Service {
 		inflight:   make(map[string]struct{}),
 		queued:     make(map[string]struct{}),
 		recentSeen: make(map[string]time.Time),
+		tids:       newTIDTable(maxTIDBindings),
 		scanActive: cfg.ScanDefaultActive,
 		scanSince:  scanSince,
 		stats: Stats{
@@ -90,6 +127,13 @@ func (s *Service) SetNotifier(n Notifier) {
 	s.mu.Unlock()
 }
 
+// SetSignaler installs the reader feedback hook for submit results.
+func (s *Service) SetSignaler(sig Signaler) {
+	s.mu.Lock()
+	s.signaler = sig
+	s.mu.Unlock()
+}
+
 func (s *Service) Bootstrap(ctx context.Context) error {
 	return s.RefreshCache(ctx, "startup", true)
 }
@@ -195,11 +239,34 @@ func (s *Service) AddDraftEPCs(_ context.Context, epcs []string) (int, int) {
 	return added, len(replay)
 }
 
-func (s *Service) HandleEPC(_ context.Context, rawEPC, _ string) IngestResult {
-	epc := erp.NormalizeEPC(rawEPC)
+func (s *Service) HandleEPC(ctx context.Context, rawEPC, source string) IngestResult {
+	return s.HandleRead(ctx, TagRead{EPC: rawEPC, Source: source})
+}
+
+// HandleRead ingests one tag read and records the decision in the audit log.
+func (s *Service) HandleRead(_ context.Context, read TagRead) IngestResult {
+	res := s.ingest(read)
+	s.auditRead(read, res)
+	return res
+}
+
+func (s *Service) ingest(read TagRead) IngestResult {
+	epc := erp.NormalizeEPC(read.EPC)
 	if epc == "" {
 		return IngestResult{Action: "invalid", Error: "epc is empty"}
 	}
+	if reason, ok := s.filterRead(epc, read); !ok {
+		s.mu.Lock()
+		s.stats.Filtered++
+		s.mu.Unlock()
+		return IngestResult{EPC: epc, Action: "filtered", Error: reason}
+	}
+	if !s.directionAllowed(read.Direction) {
+		s.mu.Lock()
+		s.stats.DirectionSkip++
+		s.mu.Unlock()
+		return IngestResult{EPC: epc, Action: "direction_skip"}
+	}
 
 	now := time.Now()
 	s.mu.Lock()
@@ -226,12 +293,32 @@ func (s *Service) HandleEPC(_ context.Context, rawEPC, _ string) IngestResult {
 	s.stats.CacheHits++
 	s.mu.Unlock()
 
+	if res, ok, _ := s.tidResult(epc, read); !ok {
+		return res
+	}
+
+	if s.dryRun() {
+		s.mu.Lock()
+		s.stats.DryRun++
+		s.mu.Unlock()
+		return IngestResult{EPC: epc, Action: "dry_run"}
+	}
 	if !s.enqueue(epc) {
 		return IngestResult{EPC: epc, Action: "queued_or_dropped"}
 	}
 	return IngestResult{EPC: epc, Action: "queued"}
 }
 
+// directionAllowed reports whether a read passes BOT_SUBMIT_DIRECTION.
+// Skipped reads are not remembered, so they are never replayed later.
+func (s *Service) directionAllowed(direction string) bool {
+	want := s.cfg.SubmitDirection
+	if want != "in" && want != "out" {
+		return true
+	}
+	return strings.EqualFold(strings.TrimSpace(direction), want)
+}
+
 func (s *Service) SetScanActive(active bool, reason string) int {
 	now := time.Now()
 	var becameActive bool
@@ -281,8 +368,8 @@ func (s *Service) Status() Stats {
 
 func (s *Service) StatusText() string {
 	st := s.Status()
-	return fmt.Sprintf(
-		"Scan: active=%v since=%s\nCache: %d EPC (draft=%d)\nSeen: %d | hit=%d miss=%d inactive=%d\nSubmit: ok=%d not_found=%d err=%d\nLast refresh: %s (ok=%v)",
+	text := fmt.Sprintf(
+		"Scan: active=%v since=%s\nCache: %d EPC (draft=%d)\nSeen: %d | hit=%d miss=%d inactive=%d filtered=%d\nSubmit: ok=%d not_found=%d err=%d\nLast refresh: %s (ok=%v)",
 		st.ScanActive,
 		formatTime(st.ScanSince),
 		st.CacheSize,
@@ -291,12 +378,23 @@ func (s *Service) StatusText() string {
 		st.CacheHits,
 		st.CacheMisses,
 		st.ScanInactive,
+		st.Filtered,
 		st.SubmittedOK,
 		st.SubmitNotFound,
 		st.SubmitErrors,
 		formatTime(st.LastRefreshAt),
 		st.LastRefreshOK,
 	)
+	if s.cfg.SubmitDirection == "in" || s.cfg.SubmitDirection == "out" {
+		text += fmt.Sprintf("\nDirection: submit=%s skipped=%d", s.cfg.SubmitDirection, st.DirectionSkip)
+	}
+	if st.TIDMismatch > 0 {
+		text += fmt.Sprintf("\nTID mismatch: %d", st.TIDMismatch)
+	}
+	if s.dryRun() {
+		text += fmt.Sprintf("\nReplay (dry-run, ERPga yuborilmaydi): %d", st.DryRun)
+	}
+	return text
 }
 
 func (s *Service) worker(ctx context.Context, workerID int) {
@@ -344,7 +442,9 @@ func (s *Service) processSubmit(parent context.Context, epc string) error {
 				s.stats.SubmittedOK++
 				s.stats.CacheSize = s.cache.Size()
 				s.mu.Unlock()
-				s.notify("Submit OK: " + trimEPC(epc))
+				s.auditSubmit(epc, "submitted", nil)
+				s.signal(true)
+				s.notify("Submit OK: " + epcLabel(epc))
 				return nil
 			case erp.SubmitStatusNotFound:
 				s.cache.Remove(epc)
@@ -352,6 +452,8 @@ func (s *Service) processSubmit(parent context.Context, epc string) error {
 				s.stats.SubmitNotFound++
 				s.stats.CacheSize = s.cache.Size()
 				s.mu.Unlock()
+				s.auditSubmit(epc, "not_found", nil)
+				s.signal(false)
 				return nil
 			default:
 				lastErr = fmt.Errorf("unexpected submit status: %s", status)
@@ -368,12 +470,20 @@ func (s *Service) processSubmit(parent context.Context, epc string) error {
 	s.mu.Lock()
 	s.stats.SubmitErrors++
 	s.mu.Unlock()
-	s.notify("Submit xato: " + trimEPC(epc))
+	s.auditSubmit(epc, "error", lastErr)
+	s.signal(false)
+	s.notify("Submit xato: " + epcLabel(epc))
 	return lastErr
 }
 
+// dryRun is true in replay mode: recorded reads are matched against the
+// cache but never submitted to ERP.
+func (s *Service) dryRun() bool {
+	return s.cfg.ReaderReplayFile != ""
+}
+
 func (s *Service) enqueue(epc string) bool {
-	if epc == "" {
+	if epc == "" || s.dryRun() {
 		return false
 	}
 
@@ -471,6 +581,15 @@ func (s *Service) notify(text string) {
 	}
 }
 
+func (s *Service) signal(ok bool) {
+	s.mu.Lock()
+	sig := s.signaler
+	s.mu.Unlock()
+	if sig != nil {
+		sig.SubmitSignal(ok)
+	}
+}
+
 func normalizeEPCList(values []string) []string {
 	uniq := make(map[string]struct{}, len(values))
 	out := make([]string, 0, len(values))
@@ -525,6 +644,15 @@ func summarizeEPCs(epcs []string, max int) string {
 	return strings.Join(parts, ", ")
 }
 
+// epcLabel is trimEPC plus the decoded GS1 identity, when there is one.
+func epcLabel(epc string) string {
+	id, err := tds.DecodeHex(epc)
+	if err != nil {
+		return trimEPC(epc)
+	}
+	return trimEPC(epc) + " (" + id.Label() + ")"
+}
+
 func trimEPC(epc string) string {
 	if len(epc) <= 16 {
 		return epc
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"new_era_go/internal/gobot/audit"
+)
+
+// SetAuditLog enables recording of every read and submit decision.
+func (s *Service) SetAuditLog(l *audit.Log) {
+	s.mu.Lock()
+	s.auditLog = l
+	s.mu.Unlock()
+}
+
+// AuditQuery searches the audit log; it fails when auditing is disabled.
+func (s *Service) AuditQuery(q audit.Query) ([]audit.Entry, error) {
+	l := s.currentAuditLog()
+	if l == nil {
+		return nil, fmt.Errorf("audit log disabled")
+	}
+	return l.Query(q)
+}
+
+// AuditText renders the newest audit entries for one EPC as chat text.
+func (s *Service) AuditText(epc string, limit int) string {
+	entries, err := s.AuditQuery(audit.Query{EPC: epc, Limit: limit})
+	if err != nil {
+		return "Audit: " + err.Error()
+	}
+	if len(entries) == 0 {
+		return "Audit: " + trimEPC(epc) + " uchun yozuv yo'q"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Audit %s (%d):", trimEPC(epc), len(entries))
+	for _, e := range entries {
+		fmt.Fprintf(&b, "\n%s %s", e.Time.Format("2006-01-02 15:04:05"), e.Action)
+		if e.Source != "" {
+			b.WriteString(" src=" + e.Source)
+		}
+		if e.Reader != "" {
+			b.WriteString(" reader=" + e.Reader)
+		}
+		if e.Antenna > 0 {
+			fmt.Fprintf(&b, " ant=%d", e.Antenna)
+		}
+		if e.Direction != "" {
+			b.WriteString(" dir=" + e.Direction)
+		}
+		if e.TID != "" {
+			b.WriteString(" tid=" + e.TID)
+		}
+		if e.Error != "" {
+			b.WriteString(" err=" + e.Error)
+		}
+	}
+	return b.String()
+}
+
+func (s *Service) auditRead(read TagRead, res IngestResult) {
+	epc := res.EPC
+	if epc == "" {
+		return
+	}
+	s.appendAudit(audit.Entry{
+		Time:      time.Now(),
+		EPC:       epc,
+		Source:    strings.TrimSpace(read.Source),
+		Reader:    strings.TrimSpace(read.Reader),
+		Antenna:   read.Antenna,
+		RSSI:      read.RSSI,
+		TID:       strings.ToUpper(strings.TrimSpace(read.TID)),
+		Direction: strings.ToLower(strings.TrimSpace(read.Direction)),
+		Action:    res.Action,
+		Error:     res.Error,
+	})
+}
+
+func (s *Service) auditSubmit(epc, action string, err error) {
+	entry := audit.Entry{
+		Time:   time.Now(),
+		EPC:    epc,
+		Source: "submit",
+		Action: action,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	s.appendAudit(entry)
+}
+
+func (s *Service) appendAudit(entry audit.Entry) {
+	l := s.currentAuditLog()
+	if l == nil {
+		return
+	}
+	if err := l.Append(entry); err != nil {
+		log.Printf("[bot] audit append failed: %v", err)
+	}
+}
+
+func (s *Service) currentAuditLog() *audit.Log {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.auditLog
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"time"
+
+	"new_era_go/internal/gobot/filter"
+	"new_era_go/sdk"
+)
+
+// SetFilters installs per-reader rules for reads arriving via IPC/HTTP.
+func (s *Service) SetFilters(set *filter.Set) {
+	s.mu.Lock()
+	s.filters = set
+	s.mu.Unlock()
+}
+
+// filterRead applies the reader's rules unless the SDK scanner already ran
+// exactly those rules. MinReads is left to the SDK scanner: the service gets
+// one HandleRead per reported tag, not per radio read, so counting calls
+// would hold back IPC/HTTP reads that were read plenty of times.
+func (s *Service) filterRead(epc string, read TagRead) (string, bool) {
+	s.mu.Lock()
+	set := s.filters
+	s.mu.Unlock()
+	if set == nil {
+		return "", true
+	}
+	rules := set.For(read.Reader)
+	if read.Prefilter != nil && read.Prefilter == rules {
+		return "", true
+	}
+	ok, reason := rules.CheckWithoutMinReads(sdk.TagEvent{
+		When:    time.Now(),
+		EPC:     epc,
+		Antenna: read.Antenna,
+		RSSI:    read.RSSI,
+	})
+	return reason, ok
+}
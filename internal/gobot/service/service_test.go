@@ -4,14 +4,18 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"new_era_go/internal/gobot/audit"
 	"new_era_go/internal/gobot/cache"
 	"new_era_go/internal/gobot/config"
 	"new_era_go/internal/gobot/erp"
+	"new_era_go/internal/gobot/filter"
+	"new_era_go/sdk"
 )
 
 func testConfig() config.Config {
@@ -198,3 +202,257 @@ func TestDraftEPCsReturnsSortedSnapshot(t *testing.T) {
 		t.Fatalf("draft snapshot mismatch: got %v want %v", got, want)
 	}
 }
+
+func TestHandleReadWritesAuditEntries(t *testing.T) {
+	c := cache.New()
+	c.Add([]string{"E200001122334455"})
+	svc := New(testConfig(), nil, c)
+
+	auditLog, err := audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"), 1<<20, 2)
+	if err != nil {
+		t.Fatalf("audit open failed: %v", err)
+	}
+	defer auditLog.Close()
+	svc.SetAuditLog(auditLog)
+
+	_ = svc.HandleRead(context.Background(), TagRead{EPC: "e200001122334455", Source: "sdk", Reader: "10.0.0.5:6000", Antenna: 2})
+	svc.SetScanActive(true, "unit_test")
+	_ = svc.HandleEPC(context.Background(), "E200009999", "ipc")
+
+	entries, err := svc.AuditQuery(audit.Query{})
+	if err != nil {
+		t.Fatalf("audit query failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %+v", entries)
+	}
+	first := entries[0]
+	if first.EPC != "E200001122334455" || first.Action != "scan_inactive" || first.Reader != "10.0.0.5:6000" || first.Antenna != 2 {
+		t.Fatalf("unexpected first entry: %+v", first)
+	}
+	if entries[1].Action != "miss" || entries[1].Source != "ipc" {
+		t.Fatalf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestHandleReadSkipsWrongDirection(t *testing.T) {
+	c := cache.New()
+	c.Add([]string{"E200001122334455"})
+	cfg := testConfig()
+	cfg.ScanDefaultActive = true
+	cfg.SubmitDirection = "in"
+	svc := New(cfg, nil, c)
+
+	for _, dir := range []string{"", "out"} {
+		res := svc.HandleRead(context.Background(), TagRead{EPC: "E200001122334455", Source: "sdk", Direction: dir})
+		if res.Action != "direction_skip" {
+			t.Fatalf("direction %q: expected direction_skip, got %q", dir, res.Action)
+		}
+	}
+	if st := svc.Status(); st.DirectionSkip != 2 || st.SeenTotal != 0 {
+		t.Fatalf("unexpected stats after skips: %+v", st)
+	}
+	if replay := svc.collectReplayCandidates(time.Now(), nil); len(replay) != 0 {
+		t.Fatalf("skipped reads must not be replayed: %v", replay)
+	}
+
+	res := svc.HandleRead(context.Background(), TagRead{EPC: "E200001122334455", Source: "sdk", Direction: "IN"})
+	if res.Action != "queued" {
+		t.Fatalf("expected queued for inbound pass, got %q", res.Action)
+	}
+}
+
+func TestHandleReadAppliesReaderFilters(t *testing.T) {
+	c := cache.New()
+	c.Add([]string{"E200001122334455", "AA00001122334455"})
+	cfg := testConfig()
+	cfg.ScanDefaultActive = true
+	svc := New(cfg, nil, c)
+
+	set, err := filter.New(filter.File{
+		Default: sdk.FilterRules{AllowPrefixes: []string{"E2"}},
+		Readers: map[string]sdk.FilterRules{"dock-1": {MinRSSI: 50}, "dock-2": {MinReads: 3}},
+	})
+	if err != nil {
+		t.Fatalf("filter set: %v", err)
+	}
+	svc.SetFilters(set)
+
+	res := svc.HandleRead(context.Background(), TagRead{EPC: "AA00001122334455", Source: "http"})
+	if res.Action != "filtered" || res.Error != "allow_prefix" {
+		t.Fatalf("expected allow_prefix filter, got %+v", res)
+	}
+	res = svc.HandleRead(context.Background(), TagRead{EPC: "E200001122334455", Source: "ipc", Reader: "dock-1", RSSI: 30})
+	if res.Action != "filtered" || res.Error != "min_rssi" {
+		t.Fatalf("expected min_rssi filter for dock-1, got %+v", res)
+	}
+	// IPC/HTTP ingest sees one call per tag, so MinReads must not hold it back.
+	res = svc.HandleRead(context.Background(), TagRead{EPC: "E200001122334455", Source: "ipc", Reader: "dock-2"})
+	if res.Action != "queued" {
+		t.Fatalf("min_reads must not apply to ingest reads, got %+v", res)
+	}
+	res = svc.HandleRead(context.Background(), TagRead{EPC: "AA00001122334455", Source: "sdk", Prefilter: set.For("")})
+	if res.Action != "queued" {
+		t.Fatalf("reads checked by the same rules must bypass service filters, got %+v", res)
+	}
+	// The SDK ran other (older) rules: the service still applies its own.
+	stale, _ := sdk.NewTagFilter(sdk.FilterRules{})
+	res = svc.HandleRead(context.Background(), TagRead{EPC: "AB00001122334455", Source: "sdk", Prefilter: stale})
+	if res.Action != "filtered" || res.Error != "allow_prefix" {
+		t.Fatalf("expected allow_prefix for reads filtered by other rules, got %+v", res)
+	}
+	if st := svc.Status(); st.Filtered != 3 {
+		t.Fatalf("expected filtered=3, got %d", st.Filtered)
+	}
+}
+
+func TestHandleReadFlagsTIDMismatch(t *testing.T) {
+	c := cache.New()
+	c.Add([]string{"E200001122334455"})
+	cfg := testConfig()
+	cfg.ScanDefaultActive = true
+	svc := New(cfg, nil, c)
+	notifier := &captureNotifier{}
+	svc.SetNotifier(notifier)
+
+	first := svc.HandleRead(context.Background(), TagRead{EPC: "E200001122334455", TID: "e2801160aaaa"})
+	if first.Action != "queued" {
+		t.Fatalf("expected first read to bind TID and queue, got %q", first.Action)
+	}
+	clone := svc.HandleRead(context.Background(), TagRead{EPC: "E200001122334455", TID: "E2801160BBBB"})
+	if clone.Action != "tid_mismatch" {
+		t.Fatalf("expected tid_mismatch, got %q", clone.Action)
+	}
+	if st := svc.Status(); st.TIDMismatch != 1 {
+		t.Fatalf("unexpected mismatch count: %d", st.TIDMismatch)
+	}
+	if msgs := notifier.messages; len(msgs) != 1 || !strings.Contains(msgs[0], "E2801160BBBB") {
+		t.Fatalf("expected one mismatch warning, got %v", msgs)
+	}
+
+	// Reads without TID are not judged.
+	if res := svc.HandleRead(context.Background(), TagRead{EPC: "E200001122334455"}); res.Action == "tid_mismatch" {
+		t.Fatalf("read without TID must not be flagged")
+	}
+}
+
+func TestVerifyTIDCatchesRepeatedCloneReads(t *testing.T) {
+	c := cache.New()
+	c.Add([]string{"E200001122334455"})
+	cfg := testConfig()
+	cfg.ScanDefaultActive = true
+	svc := New(cfg, nil, c)
+	notifier := &captureNotifier{}
+	svc.SetNotifier(notifier)
+	auditLog, err := audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"), 1<<20, 2)
+	if err != nil {
+		t.Fatalf("audit open failed: %v", err)
+	}
+	defer auditLog.Close()
+	svc.SetAuditLog(auditLog)
+
+	if !svc.VerifyTID(TagRead{EPC: "E200001122334455", TID: "E2801160AAAA"}) {
+		t.Fatal("first TID must bind")
+	}
+	// A clone keeps being read while the EPC is deduped downstream.
+	for i := 0; i < 3; i++ {
+		if svc.VerifyTID(TagRead{EPC: "E200001122334455", TID: "E2801160BBBB"}) {
+			t.Fatal("clone read accepted")
+		}
+	}
+	if st := svc.Status(); st.TIDMismatch != 3 {
+		t.Fatalf("unexpected mismatch count: %d", st.TIDMismatch)
+	}
+	if len(notifier.messages) != 1 {
+		t.Fatalf("a clone staying in the field must be reported once, got %v", notifier.messages)
+	}
+	entries, err := svc.AuditQuery(audit.Query{})
+	if err != nil {
+		t.Fatalf("audit query failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != "tid_mismatch" {
+		t.Fatalf("a clone staying in the field must be audited once, got %+v", entries)
+	}
+	if !svc.VerifyTID(TagRead{EPC: "E2FFFF", TID: "E2801160CCCC"}) {
+		t.Fatal("EPCs outside the cache are not judged")
+	}
+}
+
+func TestTIDTableForgetsLeastRecentlyRead(t *testing.T) {
+	table := newTIDTable(2)
+	table.get("A", "1")
+	table.get("B", "2")
+	table.get("A", "1")
+	table.get("C", "3")
+	if _, ok := table.items["B"]; ok || len(table.items) != 2 {
+		t.Fatalf("expected B evicted, have %v", table.items)
+	}
+	if entry, existed := table.get("A", "9"); !existed || entry.tid != "1" {
+		t.Fatalf("A must keep its binding: %+v existed=%v", entry, existed)
+	}
+}
+
+type captureSignaler struct {
+	results []bool
+}
+
+func (s *captureSignaler) SubmitSignal(ok bool) {
+	s.results = append(s.results, ok)
+}
+
+func TestProcessSubmitSignalsResult(t *testing.T) {
+	fail := false
+	status := "submitted"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"message":{"ok":true,"status":"` + status + `"}}`))
+	}))
+	defer srv.Close()
+
+	cfg := testConfig()
+	c := cache.New()
+	c.Add([]string{"E200000000000001", "E200000000000002", "E200000000000003"})
+	svc := New(cfg, erp.New(srv.URL, "k", "s", cfg.RequestTimeout), c)
+	sig := &captureSignaler{}
+	svc.SetSignaler(sig)
+
+	if err := svc.processSubmit(context.Background(), "E200000000000001"); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	status = "not_found"
+	if err := svc.processSubmit(context.Background(), "E200000000000003"); err != nil {
+		t.Fatalf("not found submit: %v", err)
+	}
+	fail = true
+	if err := svc.processSubmit(context.Background(), "E200000000000002"); err == nil {
+		t.Fatalf("expected submit error")
+	}
+	if !reflect.DeepEqual(sig.results, []bool{true, false, false}) {
+		t.Fatalf("unexpected signals: %v", sig.results)
+	}
+}
+
+func TestReplayModeIsDryRun(t *testing.T) {
+	cfg := testConfig()
+	cfg.ReaderReplayFile = "logs/site.jsonl"
+	c := cache.New()
+	c.Add([]string{"E200001122334455"})
+	svc := New(cfg, nil, c)
+
+	_ = svc.HandleEPC(context.Background(), "E200001122334455", "test")
+	_ = svc.SetScanActive(true, "unit_test")
+	if len(svc.queue) != 0 {
+		t.Fatalf("replay mode must not queue seen EPCs, got %d", len(svc.queue))
+	}
+	res := svc.HandleEPC(context.Background(), "E200001122334455", "test")
+	if res.Action != "dry_run" {
+		t.Fatalf("expected dry_run, got %q", res.Action)
+	}
+	if st := svc.Status(); st.DryRun != 1 || len(svc.queue) != 0 {
+		t.Fatalf("unexpected dry-run state: dry_run=%d queue=%d", st.DryRun, len(svc.queue))
+	}
+}
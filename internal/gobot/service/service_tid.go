@@ -0,0 +1,104 @@
+package service
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+
+	"new_era_go/internal/gobot/erp"
+)
+
+// maxTIDBindings bounds the EPC->TID table; past it the binding of the
+// least recently read EPC is forgotten and rebinds on its next read.
+const maxTIDBindings = 65536
+
+type tidEntry struct {
+	epc string
+	tid string
+	// alerted is the last mismatching TID reported for this EPC, so a
+	// clone that stays in the field is announced once, not per read.
+	alerted string
+}
+
+// tidTable is an LRU of EPC->TID bindings; callers hold Service.mu.
+type tidTable struct {
+	max   int
+	order *list.List
+	items map[string]*list.Element
+}
+
+func newTIDTable(max int) *tidTable {
+	return &tidTable{max: max, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+// get returns the entry for epc, binding tid when there is none.
+func (t *tidTable) get(epc, tid string) (*tidEntry, bool) {
+	if el, ok := t.items[epc]; ok {
+		t.order.MoveToFront(el)
+		return el.Value.(*tidEntry), true
+	}
+	entry := &tidEntry{epc: epc, tid: tid}
+	t.items[epc] = t.order.PushFront(entry)
+	for t.order.Len() > t.max {
+		oldest := t.order.Back()
+		t.order.Remove(oldest)
+		delete(t.items, oldest.Value.(*tidEntry).epc)
+	}
+	return entry, false
+}
+
+// checkTID binds the first TID seen for a cached EPC and reports whether
+// read's TID still matches it. Reads without TID are always accepted.
+// alert is true the first time a given mismatching TID shows up.
+func (s *Service) checkTID(epc, tid string) (bound string, ok, alert bool) {
+	tid = strings.ToUpper(strings.TrimSpace(tid))
+	if tid == "" {
+		return "", true, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, existed := s.tids.get(epc, tid)
+	if !existed || entry.tid == tid {
+		return entry.tid, true, false
+	}
+	s.stats.TIDMismatch++
+	alert = entry.alerted != tid
+	entry.alerted = tid
+	return entry.tid, false, alert
+}
+
+// VerifyTID checks a scanner read against the TID bound to its cached EPC.
+// The scanner calls it for every read that carries a TID, before dedup, so
+// a clone is caught even when its EPC was already seen this session. Every
+// mismatch is counted, but like the notification it is audited once per
+// clone TID; false means the read must be dropped.
+func (s *Service) VerifyTID(read TagRead) bool {
+	epc := erp.NormalizeEPC(read.EPC)
+	if epc == "" || strings.TrimSpace(read.TID) == "" || !s.cache.Has(epc) {
+		return true
+	}
+	res, ok, alert := s.tidResult(epc, read)
+	if alert {
+		s.auditRead(read, res)
+	}
+	return ok
+}
+
+// tidResult runs checkTID and builds the tid_mismatch result for a clone.
+// alert is true the first time that clone TID shows up for the EPC.
+func (s *Service) tidResult(epc string, read TagRead) (res IngestResult, ok, alert bool) {
+	bound, ok, alert := s.checkTID(epc, read.TID)
+	if ok {
+		return IngestResult{}, true, false
+	}
+	if alert {
+		s.notify(tidMismatchText(epc, bound, read.TID))
+	}
+	return IngestResult{EPC: epc, Action: "tid_mismatch", Error: "bound TID " + bound}, false, alert
+}
+
+func tidMismatchText(epc, bound, got string) string {
+	return fmt.Sprintf("Ogohlantirish: TID mos emas, klon bo'lishi mumkin\nEPC: %s\nkutilgan TID: %s\nkelgan TID: %s",
+		epcLabel(epc), bound, strings.ToUpper(strings.TrimSpace(got)))
+}
@@ -3,6 +3,7 @@ package telegram
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -15,8 +16,10 @@ import (
 	"sync"
 	"time"
 
+	"new_era_go/internal/gobot/erp"
 	"new_era_go/internal/gobot/service"
 	"new_era_go/internal/gobot/testmode"
+	"new_era_go/sdk"
 )
 
 type Bot struct {
@@ -49,8 +52,12 @@ type Scanner interface {
 	StatusText() string
 }
 
+type TelemetryReporter interface {
+	TelemetryText(topTags int) string
+}
+
 type RangeTuner interface {
-	SetLongRangeMode(enabled bool) string
+	SetLongRangeMode(enabled bool) (string, error)
 	LongRangeMode() bool
 }
 
@@ -151,10 +158,14 @@ func (b *Bot) handleUpdate(ctx context.Context, upd update) error {
 			"/read stop - /stop bilan bir xil\n" +
 			"/stop - reader scan ni to'xtatish ⏹️\n" +
 			"/status - holat ℹ️\n" +
+			"/stats [top] - antenna/EPC bo'yicha o'qish tezligi va RSSI 📊\n" +
 			"/cache - draft/epc snapshot fayllarini yozish 📁\n" +
 			"/range20 on|off|status - long-range profil 📡\n" +
 			"/range20_on | /range20_off - tez yoqish/o'chirish ⚡\n" +
+			"/autotune [apply] - reference taglar bo'yicha power/scan sozlash 🎛️\n" +
+			"/profile [nom] - saqlangan inventory profillari / almashtirish 🗂️\n" +
 			"/turbo - cache ni darrov yangilash 🚀\n" +
+			"/audit <EPC> [soni] - EPC o'qilish/submit tarixi 🗂️\n" +
 			"/test - EPC test uchun txt fayl kutish 🧪\n" +
 			"/test_stop - testni yakunlash va natijani olish 🛑"
 		return b.sendMessage(ctx, msg.Chat.ID, text)
@@ -185,6 +196,20 @@ func (b *Bot) handleUpdate(ctx context.Context, upd update) error {
 		}
 		return b.sendMessage(ctx, msg.Chat.ID, text)
 
+	case "/stats":
+		b.addChat(msg.Chat.ID)
+		reporter, ok := b.scanner.(TelemetryReporter)
+		if !ok {
+			return b.sendMessage(ctx, msg.Chat.ID, "⚠️ Reader telemetry mavjud emas.")
+		}
+		top := 10
+		if len(args) > 0 {
+			if n, err := strconv.Atoi(args[0]); err == nil && n >= 0 && n <= 50 {
+				top = n
+			}
+		}
+		return b.sendMessage(ctx, msg.Chat.ID, "📊 "+reporter.TelemetryText(top))
+
 	case "/cache":
 		return b.handleCacheDump(ctx, msg.Chat.ID)
 
@@ -200,6 +225,12 @@ func (b *Bot) handleUpdate(ctx context.Context, upd update) error {
 	case "/range20_status", "range20_status":
 		return b.handleRange20(ctx, msg.Chat.ID, []string{"status"})
 
+	case "/autotune":
+		return b.handleAutoTune(ctx, msg.Chat.ID, args)
+
+	case "/profile":
+		return b.handleProfile(ctx, msg.Chat.ID, args)
+
 	case "/turbo":
 		b.addChat(msg.Chat.ID)
 		if err := b.sendMessage(ctx, msg.Chat.ID, "🚀 Turbo rejim: ERPNext dan cache yangilanmoqda..."); err != nil {
@@ -210,6 +241,19 @@ func (b *Bot) handleUpdate(ctx context.Context, upd update) error {
 		}
 		return b.sendMessage(ctx, msg.Chat.ID, "✅ Turbo tayyor: cache yangilandi.")
 
+	case "/audit":
+		b.addChat(msg.Chat.ID)
+		if len(args) == 0 {
+			return b.sendMessage(ctx, msg.Chat.ID, "ℹ️ Foydalanish: /audit <EPC> [soni]")
+		}
+		limit := 20
+		if len(args) > 1 {
+			if n, err := strconv.Atoi(args[1]); err == nil && n > 0 && n <= 100 {
+				limit = n
+			}
+		}
+		return b.sendMessage(ctx, msg.Chat.ID, b.svc.AuditText(erp.NormalizeEPC(args[0]), limit))
+
 	case "/test":
 		b.addChat(msg.Chat.ID)
 		b.testMode.RequestFile(msg.Chat.ID)
@@ -288,18 +332,22 @@ func (b *Bot) handleRange20(ctx context.Context, chatID int64, args []string) er
 		return b.sendMessage(ctx, chatID, "📡 range20 holati: "+state+"\n\nReader:\n"+b.scanner.StatusText())
 
 	case "on", "1", "start", "enable":
-		summary := tuner.SetLongRangeMode(true)
-		if b.svc.ScanActive() {
-			b.scanner.Stop()
-			if err := b.scanner.Start(ctx); err != nil {
-				return b.sendMessage(ctx, chatID, summary+"\n❌ Reader restart xato: "+err.Error())
-			}
-			return b.sendMessage(ctx, chatID, "✅ "+summary+"\nQo'llandi va reader qayta ishga tushirildi.")
-		}
-		return b.sendMessage(ctx, chatID, "✅ "+summary+"\nQo'llandi. Real ta'sir /scan bilan ishga tushganda ko'rinadi.")
+		summary, err := tuner.SetLongRangeMode(true)
+		return b.finishRange20(ctx, chatID, summary, err)
 
 	case "off", "0", "stop", "disable":
-		summary := tuner.SetLongRangeMode(false)
+		summary, err := tuner.SetLongRangeMode(false)
+		return b.finishRange20(ctx, chatID, summary, err)
+	}
+
+	return b.sendMessage(ctx, chatID, "ℹ️ Foydalanish: /range20 on | /range20 off | /range20 status")
+}
+
+// finishRange20 reports a range switch the manager applied live, and
+// restarts a running scan when the change cannot be applied live.
+func (b *Bot) finishRange20(ctx context.Context, chatID int64, summary string, err error) error {
+	switch {
+	case errors.Is(err, sdk.ErrRestartRequired):
 		if b.svc.ScanActive() {
 			b.scanner.Stop()
 			if err := b.scanner.Start(ctx); err != nil {
@@ -307,10 +355,11 @@ func (b *Bot) handleRange20(ctx context.Context, chatID int64, args []string) er
 			}
 			return b.sendMessage(ctx, chatID, "✅ "+summary+"\nQo'llandi va reader qayta ishga tushirildi.")
 		}
-		return b.sendMessage(ctx, chatID, "✅ "+summary)
+		return b.sendMessage(ctx, chatID, "✅ "+summary+"\nQo'llandi. Real ta'sir /scan bilan ishga tushganda ko'rinadi.")
+	case err != nil:
+		return b.sendMessage(ctx, chatID, "⚠️ "+summary+"\nxato: "+err.Error())
 	}
-
-	return b.sendMessage(ctx, chatID, "ℹ️ Foydalanish: /range20 on | /range20 off | /range20 status")
+	return b.sendMessage(ctx, chatID, "✅ "+summary)
 }
 
 func (b *Bot) getUpdates(ctx context.Context, offset int64) ([]update, error) {
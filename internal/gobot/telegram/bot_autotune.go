@@ -0,0 +1,77 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"new_era_go/internal/gobot/testmode"
+)
+
+// AutoTuner sweeps reader power/scan time against reference tags.
+type AutoTuner interface {
+	AutoTune(ctx context.Context, reference []string, apply bool) (string, error)
+}
+
+// autoTuneTimeout covers the default sweep (15 candidates x 3s) with margin.
+const autoTuneTimeout = 3 * time.Minute
+
+func (b *Bot) handleAutoTune(ctx context.Context, chatID int64, args []string) error {
+	b.addChat(chatID)
+	tuner, ok := b.scanner.(AutoTuner)
+	if !ok {
+		return b.sendMessage(ctx, chatID, "⚠️ /autotune faqat SDK scanner bilan ishlaydi.")
+	}
+	apply := len(args) > 0 && strings.EqualFold(strings.TrimSpace(args[0]), "apply")
+
+	reference, source, err := b.autoTuneReference()
+	if err != nil {
+		return b.sendMessage(ctx, chatID, "⚠️ "+err.Error())
+	}
+	if err := b.sendMessage(ctx, chatID, fmt.Sprintf("⏳ Auto-tune boshlandi: %d ta reference EPC (%s). Reference taglarni zonada ushlab turing.", len(reference), source)); err != nil {
+		return err
+	}
+
+	// The sweep takes about a minute; run it off the update loop, but stop
+	// it with the bot.
+	go func() {
+		tuneCtx, cancel := context.WithTimeout(ctx, autoTuneTimeout)
+		defer cancel()
+		text, err := tuner.AutoTune(tuneCtx, reference, apply)
+		switch {
+		case err != nil && text == "":
+			text = "❌ Auto-tune xato: " + err.Error()
+		case err != nil:
+			text = "⚠️ " + text + "\nXato: " + err.Error()
+		default:
+			text = "🎛️ " + text
+		}
+		if err := b.sendMessageWithRetry(chatID, text); err != nil {
+			log.Printf("[bot] autotune result chat=%d failed: %v", chatID, err)
+		}
+	}()
+	return nil
+}
+
+// autoTuneReference prefers the EPCs of an active /test, then BOT_AUTOTUNE_FILE.
+func (b *Bot) autoTuneReference() ([]string, string, error) {
+	if epcs := b.testMode.Expected(); len(epcs) > 0 {
+		return epcs, "/test fayli", nil
+	}
+	path := strings.TrimSpace(os.Getenv("BOT_AUTOTUNE_FILE"))
+	if path == "" {
+		return nil, "", fmt.Errorf("reference EPC yo'q: avval /test bilan fayl yuklang yoki BOT_AUTOTUNE_FILE bering")
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("BOT_AUTOTUNE_FILE o'qilmadi: %v", err)
+	}
+	epcs, _ := testmode.ParseEPCFile(content)
+	if len(epcs) == 0 {
+		return nil, "", fmt.Errorf("BOT_AUTOTUNE_FILE da yaroqli EPC yo'q")
+	}
+	return epcs, path, nil
+}
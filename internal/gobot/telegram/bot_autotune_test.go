@@ -0,0 +1,31 @@
+package telegram
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"new_era_go/internal/gobot/testmode"
+)
+
+func TestAutoTuneReferencePrefersActiveTest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ref.txt")
+	if err := os.WriteFile(path, []byte("# dock reference\nE200AA\nE200BB\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BOT_AUTOTUNE_FILE", path)
+
+	b := &Bot{testMode: testmode.New()}
+	epcs, source, err := b.autoTuneReference()
+	if err != nil || len(epcs) != 2 || source != path {
+		t.Fatalf("file fallback: epcs=%v source=%q err=%v", epcs, source, err)
+	}
+
+	if _, err := b.testMode.LoadFile(1, "t.txt", []byte("E200CC\n")); err != nil {
+		t.Fatal(err)
+	}
+	epcs, _, err = b.autoTuneReference()
+	if err != nil || len(epcs) != 1 || epcs[0] != "E200CC" {
+		t.Fatalf("active test must win: epcs=%v err=%v", epcs, err)
+	}
+}
@@ -0,0 +1,38 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+)
+
+// ProfileSwitcher applies named inventory profiles from the profile store.
+type ProfileSwitcher interface {
+	ProfileNames() []string
+	ApplyProfile(name string) (string, error)
+}
+
+func (b *Bot) handleProfile(ctx context.Context, chatID int64, args []string) error {
+	b.addChat(chatID)
+	switcher, ok := b.scanner.(ProfileSwitcher)
+	if !ok {
+		return b.sendMessage(ctx, chatID, "⚠️ /profile faqat SDK scanner bilan ishlaydi.")
+	}
+	if len(args) == 0 {
+		return b.sendMessage(ctx, chatID, profileListText(switcher.ProfileNames()))
+	}
+	summary, err := switcher.ApplyProfile(strings.TrimSpace(args[0]))
+	if err != nil {
+		if summary == "" {
+			return b.sendMessage(ctx, chatID, "❌ "+err.Error())
+		}
+		return b.sendMessage(ctx, chatID, "⚠️ "+summary+"\n❌ "+err.Error())
+	}
+	return b.sendMessage(ctx, chatID, "✅ "+summary)
+}
+
+func profileListText(names []string) string {
+	if len(names) == 0 {
+		return "ℹ️ Profil yo'q."
+	}
+	return "🗂️ Profillar: " + strings.Join(names, ", ") + "\nQo'llash: /profile <nom>"
+}
@@ -0,0 +1,16 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProfileListText(t *testing.T) {
+	text := profileListText([]string{"balanced", "dock", "long-range"})
+	if !strings.Contains(text, "balanced, dock, long-range") || !strings.Contains(text, "/profile <nom>") {
+		t.Fatalf("unexpected list text: %q", text)
+	}
+	if text := profileListText(nil); !strings.Contains(text, "Profil yo'q") {
+		t.Fatalf("unexpected empty text: %q", text)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"new_era_go/internal/gobot/testmode"
+	"new_era_go/internal/tds"
 )
 
 const maxTestFileSize = 2 << 20
@@ -97,6 +98,9 @@ func (b *Bot) sendTestMatch(match testmode.MatchResult) {
 	defer cancel()
 
 	text := fmt.Sprintf("✅ O'qildi: %s (%d/%d)", match.EPC, match.ReadCount, match.Total)
+	if id, err := tds.DecodeHex(match.EPC); err == nil {
+		text += "\n🏷️ " + id.Label() + "\n" + id.URI()
+	}
 	messageID, err := b.sendMessageWithID(ctx, match.ChatID, text)
 	if err != nil {
 		return
@@ -2,6 +2,7 @@ package testmode
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -81,7 +82,7 @@ func (m *Manager) IsAwaitingFile(chatID int64) bool {
 }
 
 func (m *Manager) LoadFile(chatID int64, fileName string, content []byte) (LoadStats, error) {
-	epcs, stats := parseEPCFile(content)
+	epcs, stats := ParseEPCFile(content)
 	stats.FileName = fileName
 	if stats.UniqueEPCs == 0 {
 		return stats, fmt.Errorf("faylda yaroqli EPC topilmadi")
@@ -183,6 +184,21 @@ func (m *Manager) Stop(chatID int64) (StopResult, error) {
 	return result, nil
 }
 
+// Expected returns the EPCs of the active test, sorted; nil when none is active.
+func (m *Manager) Expected() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.active {
+		return nil
+	}
+	out := make([]string, 0, len(m.expected))
+	for epc := range m.expected {
+		out = append(out, epc)
+	}
+	sort.Strings(out)
+	return out
+}
+
 func (m *Manager) IsSessionActive(sessionID uint64) bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -8,7 +8,9 @@ import (
 	"new_era_go/internal/gobot/erp"
 )
 
-func parseEPCFile(content []byte) ([]string, LoadStats) {
+// ParseEPCFile reads one EPC per line; blank lines and "#" comments are
+// skipped and duplicates counted. The same format feeds /test and /autotune.
+func ParseEPCFile(content []byte) ([]string, LoadStats) {
 	stats := LoadStats{}
 	if len(content) == 0 {
 		return nil, stats
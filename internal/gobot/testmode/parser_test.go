@@ -5,7 +5,7 @@ import "testing"
 func TestParseEPCFileStats(t *testing.T) {
 	content := []byte("\uFEFF# sample\n\nE20000112233\nE20000112233\nxx-yy\n  e20000aa  \n")
 
-	epcs, stats := parseEPCFile(content)
+	epcs, stats := ParseEPCFile(content)
 
 	if stats.TotalLines != 4 {
 		t.Fatalf("TotalLines mismatch: got=%d want=4", stats.TotalLines)
@@ -47,6 +47,9 @@ func TestManagerLoadFileReplacesPreviousState(t *testing.T) {
 	if second.SessionID == first.SessionID {
 		t.Fatalf("session id did not increment: %d", second.SessionID)
 	}
+	if expected := m.Expected(); len(expected) != 1 || expected[0] != "E200CC" {
+		t.Fatalf("expected only the new file's EPCs, got %v", expected)
+	}
 
 	oldMatch := m.RecordRead("E200AA")
 	if oldMatch.Matched {
@@ -0,0 +1,152 @@
+package reader18
+
+import "iter"
+
+// maxFrame is the longest wire frame: a Len byte of 0xFF plus itself.
+const maxFrame = 256
+
+// decoderSize is the ring capacity. Undecoded bytes never reach maxFrame,
+// so two frames' worth leaves room to take input in large steps.
+const decoderSize = 2 * maxFrame
+
+// Decoder is a streaming frame decoder over a fixed ring buffer. It makes
+// the same decisions as ParseFrames, including across chunk boundaries,
+// but does not allocate: the Frame handed to a callback has Data and Raw
+// pointing into the decoder and is only valid until the callback returns
+// (use Frame.Clone to keep it). A Decoder is not safe for concurrent use.
+type Decoder struct {
+	ring    [decoderSize]byte
+	scratch [maxFrame]byte
+	head    int
+	size    int
+	stats   ParseStats
+}
+
+// NewDecoder returns an empty decoder.
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// Feed consumes p and calls fn for every complete frame, in order.
+func (d *Decoder) Feed(p []byte, fn func(Frame)) {
+	for len(p) > 0 {
+		n := d.fill(p)
+		p = p[n:]
+		d.decode(fn)
+	}
+}
+
+// Frames is Feed as an iterator. Breaking out of the loop drops the frames
+// still in p but keeps the decoder in sync for the next chunk.
+func (d *Decoder) Frames(p []byte) iter.Seq[Frame] {
+	return func(yield func(Frame) bool) {
+		open := true
+		for len(p) > 0 {
+			n := d.fill(p)
+			p = p[n:]
+			d.decode(func(f Frame) {
+				if open {
+					open = yield(f)
+				}
+			})
+		}
+	}
+}
+
+// Stats returns the resync and truncation counters accumulated since
+// NewDecoder.
+func (d *Decoder) Stats() ParseStats {
+	return d.stats
+}
+
+// Buffered is the number of bytes waiting for the rest of a frame.
+func (d *Decoder) Buffered() int {
+	return d.size
+}
+
+// Discard drops buffered bytes, e.g. when a new session starts. Counters
+// are kept; dropping a partial frame counts as a truncation.
+func (d *Decoder) Discard() {
+	if d.size > 0 {
+		d.stats.Truncations++
+	}
+	d.head, d.size = 0, 0
+}
+
+// fill copies as much of p as fits after the buffered bytes.
+func (d *Decoder) fill(p []byte) int {
+	copied := 0
+	for copied < len(p) && d.size < decoderSize {
+		tail := (d.head + d.size) % decoderSize
+		end := decoderSize
+		if tail < d.head {
+			end = d.head
+		}
+		n := copy(d.ring[tail:end], p[copied:])
+		d.size += n
+		copied += n
+	}
+	return copied
+}
+
+func (d *Decoder) at(i int) byte {
+	return d.ring[(d.head+i)%decoderSize]
+}
+
+func (d *Decoder) skip(n int) {
+	d.head = (d.head + n) % decoderSize
+	d.size -= n
+}
+
+// contiguous returns the next n buffered bytes as one slice, copying into
+// scratch only when they wrap around the end of the ring.
+func (d *Decoder) contiguous(n int) []byte {
+	if d.head+n <= decoderSize {
+		return d.ring[d.head : d.head+n]
+	}
+	first := copy(d.scratch[:], d.ring[d.head:])
+	copy(d.scratch[first:n], d.ring[:n-first])
+	return d.scratch[:n]
+}
+
+// decode mirrors the loop in ParseFramesStats over the ring contents.
+func (d *Decoder) decode(fn func(Frame)) {
+	for d.size >= 6 {
+		total := int(d.at(0)) + 1
+		if total < 6 {
+			d.skip(1)
+			d.stats.SkippedBytes++
+			continue
+		}
+		if total > d.size {
+			return
+		}
+		raw := d.contiguous(total)
+		if !VerifyPacket(raw) {
+			d.skip(1)
+			d.stats.CRCErrors++
+			d.stats.SkippedBytes++
+			continue
+		}
+		frame := Frame{
+			Length:   raw[0],
+			Address:  raw[1],
+			Command:  raw[2],
+			Status:   raw[3],
+			Data:     raw[4 : total-2 : total-2],
+			Raw:      raw[:total:total],
+			CRCValid: true,
+		}
+		d.skip(total)
+		fn(frame)
+	}
+}
+
+// Clone returns a copy of f that owns its Data and Raw.
+func (f Frame) Clone() Frame {
+	f.Raw = append([]byte(nil), f.Raw...)
+	if f.Data != nil {
+		f.Data = append([]byte{}, f.Data...)
+	}
+	return f
+}
@@ -0,0 +1,155 @@
+package reader18
+
+import (
+	"bytes"
+	"testing"
+)
+
+// inventoryStream returns n single-tag inventory replies with 12-byte EPCs.
+func inventoryStream(n int) []byte {
+	var stream []byte
+	epc := []byte{0x30, 0x34, 0x25, 0x7B, 0xF7, 0x19, 0x4E, 0x40, 0x00, 0x00, 0x00, 0x00}
+	for i := 0; i < n; i++ {
+		epc[10], epc[11] = byte(i>>8), byte(i)
+		data := append([]byte{0x01, 0x01, byte(len(epc))}, epc...)
+		stream = append(stream, buildResponseFrame(0x00, CmdInventory, StatusNoTag, append(data, 0x50))...)
+	}
+	return stream
+}
+
+func TestDecoderMatchesParseFrames(t *testing.T) {
+	corrupt := buildResponseFrame(0x00, CmdGetReaderInfo, StatusSuccess, []byte{0x02, 0x03})
+	corrupt[len(corrupt)-1] ^= 0xFF
+	stream := append([]byte{0x00, 0x03}, corrupt...)
+	stream = append(stream, inventoryStream(200)...)
+	stream = append(stream, 0x10, 0x00)
+
+	want, remaining, stats := ParseFramesStats(stream)
+	for _, chunk := range []int{1, 5, 7, 64, 255, 511, 4096} {
+		d := NewDecoder()
+		var got [][]byte
+		for start := 0; start < len(stream); start += chunk {
+			end := min(start+chunk, len(stream))
+			d.Feed(stream[start:end], func(f Frame) {
+				got = append(got, f.Clone().Raw)
+			})
+		}
+		if len(got) != len(want) {
+			t.Fatalf("chunk %d: %d frames, want %d", chunk, len(got), len(want))
+		}
+		for i := range want {
+			if !bytes.Equal(got[i], want[i].Raw) {
+				t.Fatalf("chunk %d: frame %d differs", chunk, i)
+			}
+		}
+		if d.Stats() != stats || d.Buffered() != len(remaining) {
+			t.Fatalf("chunk %d: stats %+v buffered %d, want %+v %d", chunk, d.Stats(), d.Buffered(), stats, len(remaining))
+		}
+	}
+}
+
+func TestDecoderFeedDoesNotAllocate(t *testing.T) {
+	stream := inventoryStream(64)
+	d := NewDecoder()
+	frames := 0
+	count := func(Frame) { frames++ }
+	allocs := testing.AllocsPerRun(20, func() {
+		d.Feed(stream, count)
+	})
+	if allocs != 0 {
+		t.Fatalf("Feed allocated %.1f times per run", allocs)
+	}
+	// AllocsPerRun adds one warm-up run.
+	if frames != 21*64 {
+		t.Fatalf("decoded %d frames", frames)
+	}
+}
+
+func TestDecoderFramesIteratorBreakStaysInSync(t *testing.T) {
+	stream := inventoryStream(10)
+	d := NewDecoder()
+	seen := 0
+	for range d.Frames(stream) {
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+	if seen != 3 || d.Buffered() != 0 {
+		t.Fatalf("seen %d buffered %d", seen, d.Buffered())
+	}
+	next := 0
+	for f := range d.Frames(stream[:len(stream)/2+3]) {
+		if f.Command != CmdInventory {
+			t.Fatalf("unexpected command 0x%02X", f.Command)
+		}
+		next++
+	}
+	if next != 5 {
+		t.Fatalf("decoded %d frames after break", next)
+	}
+}
+
+func BenchmarkParseFrames(b *testing.B) {
+	benchmarkStream(b, func(stream []byte) int {
+		var pending []byte
+		frames := 0
+		for start := 0; start < len(stream); start += 4096 {
+			end := min(start+4096, len(stream))
+			var parsed []Frame
+			parsed, pending = ParseFrames(append(pending, stream[start:end]...))
+			frames += len(parsed)
+		}
+		return frames
+	})
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	d := NewDecoder()
+	benchmarkStream(b, func(stream []byte) int {
+		frames := 0
+		for start := 0; start < len(stream); start += 4096 {
+			end := min(start+4096, len(stream))
+			d.Feed(stream[start:end], func(Frame) { frames++ })
+		}
+		return frames
+	})
+}
+
+// benchmarkStream decodes 10k single-tag replies per iteration and reports
+// tags per second.
+func benchmarkStream(b *testing.B, decode func([]byte) int) {
+	const tags = 10_000
+	stream := inventoryStream(tags)
+	b.SetBytes(int64(len(stream)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if got := decode(stream); got != tags {
+			b.Fatalf("decoded %d frames, want %d", got, tags)
+		}
+	}
+	b.ReportMetric(float64(tags*b.N)/b.Elapsed().Seconds(), "tags/s")
+}
+
+func TestDecoderDiscardCountsTruncatedFrame(t *testing.T) {
+	frame := inventoryStream(1)
+	d := NewDecoder()
+	d.Discard()
+	if got := d.Stats().Truncations; got != 0 {
+		t.Fatalf("empty discard counted %d truncations", got)
+	}
+
+	d.Feed(frame[:len(frame)-3], func(Frame) { t.Fatal("partial frame decoded") })
+	d.Discard()
+	if got := d.Stats().Truncations; got != 1 || d.Buffered() != 0 {
+		t.Fatalf("truncations %d buffered %d, want 1 and 0", got, d.Buffered())
+	}
+
+	// The next frame decodes on its own, without the discarded head.
+	frames := 0
+	d.Feed(frame, func(Frame) { frames++ })
+	if frames != 1 {
+		t.Fatalf("decoded %d frames after discard, want 1", frames)
+	}
+}
@@ -0,0 +1,111 @@
+package reader18
+
+import (
+	"bytes"
+	"testing"
+)
+
+func fuzzSeedStreams() [][]byte {
+	inv := buildResponseFrame(0x00, CmdInventory, StatusNoTag, []byte{0x01, 0x01, 0x04, 0xE2, 0x00, 0x00, 0x01, 0x50})
+	info := buildResponseFrame(0x00, CmdGetReaderInfo, StatusSuccess, []byte{0x02, 0x03, 0x09, 0x80})
+	single := buildResponseFrame(0x01, CmdInventorySingle, StatusSuccess, []byte{0x01, 0x01, 0x02, 0xAA, 0xBB})
+	corrupt := append([]byte{}, inv...)
+	corrupt[len(corrupt)-1] ^= 0xFF
+	return [][]byte{
+		inv,
+		append(append([]byte{}, inv...), info...),
+		append([]byte{0x00, 0x05, 0xFF}, single...),
+		append(corrupt, info...),
+		inv[:len(inv)-3],
+		{0xFF, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05},
+	}
+}
+
+// FuzzParseFrames checks that every input byte is accounted for, that
+// frames round-trip through BuildCommand, and that feeding the stream in
+// two chunks gives the same result as feeding it at once.
+func FuzzParseFrames(f *testing.F) {
+	for _, seed := range fuzzSeedStreams() {
+		f.Add(seed, uint16(len(seed)/2))
+	}
+	f.Fuzz(func(t *testing.T, stream []byte, cut uint16) {
+		frames, remaining, stats := ParseFramesStats(stream)
+
+		used := stats.SkippedBytes + len(remaining)
+		for _, frame := range frames {
+			used += len(frame.Raw)
+			if !VerifyPacket(frame.Raw) || !frame.CRCValid {
+				t.Fatalf("frame with bad crc: % X", frame.Raw)
+			}
+			rebuilt := BuildCommand(frame.Address, frame.Command, append([]byte{frame.Status}, frame.Data...))
+			if !bytes.Equal(rebuilt, frame.Raw) {
+				t.Fatalf("frame does not round-trip: % X vs % X", rebuilt, frame.Raw)
+			}
+		}
+		if used != len(stream) {
+			t.Fatalf("accounted %d of %d bytes", used, len(stream))
+		}
+		if stats.CRCErrors > stats.SkippedBytes {
+			t.Fatalf("crc errors %d > skipped %d", stats.CRCErrors, stats.SkippedBytes)
+		}
+		if len(remaining) > 255 {
+			t.Fatalf("remaining %d bytes, longer than any frame", len(remaining))
+		}
+
+		split := int(cut) % (len(stream) + 1)
+		first, rest, firstStats := ParseFramesStats(stream[:split])
+		second, tail, secondStats := ParseFramesStats(append(rest, stream[split:]...))
+		firstStats.Add(secondStats)
+		chunked := append(first, second...)
+		if len(chunked) != len(frames) || !bytes.Equal(tail, remaining) || firstStats != stats {
+			t.Fatalf("chunked parse differs: %d frames %+v, want %d frames %+v", len(chunked), firstStats, len(frames), stats)
+		}
+		for i := range frames {
+			if !bytes.Equal(chunked[i].Raw, frames[i].Raw) {
+				t.Fatalf("chunked frame %d differs", i)
+			}
+		}
+	})
+}
+
+func FuzzParseInventoryG2Tags(f *testing.F) {
+	f.Add([]byte{0x01, 0x01, 0x04, 0xE2, 0x00, 0x00, 0x01, 0x50})
+	f.Add([]byte{0x02, 0x02, 0x02, 0xE2, 0x00, 0x40, 0x04, 0xAA, 0xBB, 0xCC, 0xDD, 0x41})
+	f.Add([]byte{0x80, 0x03, 0x00})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		frame := Frame{Command: CmdInventory, Status: StatusNoTag, Data: append([]byte(nil), data...)}
+		tags, err := ParseInventoryG2Tags(frame)
+		if err != nil {
+			if tags != nil {
+				t.Fatalf("tags returned with error: %v", err)
+			}
+			return
+		}
+		if len(data) >= 2 && len(tags) != int(data[1]) {
+			t.Fatalf("parsed %d tags, header says %d", len(tags), data[1])
+		}
+		consumed := 2
+		for _, tag := range tags {
+			if len(tag.EPC) == 0 {
+				t.Fatal("empty tag record")
+			}
+			if tag.Antenna < 1 || tag.Antenna > 8 {
+				t.Fatalf("antenna %d out of range", tag.Antenna)
+			}
+			consumed += 2 + len(tag.EPC)
+		}
+		if consumed > len(data) && len(tags) > 0 {
+			t.Fatalf("consumed %d of %d bytes", consumed, len(data))
+		}
+		// Tags must own their bytes, not alias the frame buffer.
+		for i := range frame.Data {
+			frame.Data[i] ^= 0xFF
+		}
+		again, _ := ParseInventoryG2Tags(Frame{Command: CmdInventory, Data: data})
+		for i := range tags {
+			if !bytes.Equal(tags[i].EPC, again[i].EPC) {
+				t.Fatalf("tag %d aliases the frame buffer", i)
+			}
+		}
+	})
+}
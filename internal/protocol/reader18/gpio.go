@@ -0,0 +1,71 @@
+package reader18
+
+import "fmt"
+
+// GPIO commands as sent by the vendor SDK's SetGPIO and GetGPIOStatus.
+// Firmware without an I/O board answers StatusCmdError. The packets are
+// pinned byte for byte in gpio_test.go.
+const (
+	CmdSetGPIO byte = 0x46
+	CmdGetGPIO byte = 0x47
+)
+
+// GPIO pin counts documented for the reader: Out1-Out2 and IN1.
+const (
+	GPIOOutputs = 2
+	GPIOInputs  = 1
+)
+
+// AcoustoOpticUnit is the time unit of the 0x33 ActiveT/SilentT fields.
+const AcoustoOpticUnit = 50 // milliseconds
+
+// AcoustoOpticCommand drives the buzzer and LED together (0x33).
+// Payload: ActiveT, SilentT, Times; times are in AcoustoOpticUnit steps.
+func AcoustoOpticCommand(address, activeT, silentT, times byte) []byte {
+	return BuildCommand(address, CmdAcoustoOptic, []byte{activeT, silentT, times})
+}
+
+// SetGPIOCommand sets the output pins; bit 0-1 drive Out1-Out2 and the
+// reserved bits 2-7 are sent cleared.
+func SetGPIOCommand(address, outputs byte) []byte {
+	return BuildCommand(address, CmdSetGPIO, []byte{outputs & gpioOutputMask})
+}
+
+// GetGPIOCommand asks for the current pin levels.
+func GetGPIOCommand(address byte) []byte {
+	return BuildCommand(address, CmdGetGPIO, nil)
+}
+
+const (
+	gpioOutputMask = 1<<GPIOOutputs - 1
+	gpioInputMask  = 1<<GPIOInputs - 1
+)
+
+// GPIOState is decoded from a GetGPIO (0x47) response byte: bit 0 is IN1,
+// bits 4-5 are Out1-Out2 and the other bits are reserved.
+type GPIOState struct {
+	Inputs  byte
+	Outputs byte
+}
+
+// Input reports the level of input pin (1-based).
+func (s GPIOState) Input(pin int) bool {
+	return pin >= 1 && pin <= GPIOInputs && s.Inputs&(1<<(pin-1)) != 0
+}
+
+// ParseGPIO decodes a successful 0x47 response.
+func ParseGPIO(frame Frame) (GPIOState, error) {
+	if frame.Command != CmdGetGPIO {
+		return GPIOState{}, fmt.Errorf("not gpio frame")
+	}
+	if frame.Status != StatusSuccess {
+		return GPIOState{}, fmt.Errorf("gpio status 0x%02X", frame.Status)
+	}
+	if len(frame.Data) < 1 {
+		return GPIOState{}, fmt.Errorf("gpio payload empty")
+	}
+	return GPIOState{
+		Inputs:  frame.Data[0] & gpioInputMask,
+		Outputs: frame.Data[0] >> 4 & gpioOutputMask,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package reader18
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAcoustoOpticCommand(t *testing.T) {
+	packet := AcoustoOpticCommand(0x00, 2, 2, 3)
+	if !VerifyPacket(packet) {
+		t.Fatalf("bad packet: % X", packet)
+	}
+	if packet[2] != CmdAcoustoOptic || !bytes.Equal(packet[3:6], []byte{2, 2, 3}) {
+		t.Fatalf("unexpected payload: % X", packet)
+	}
+}
+
+func TestParseGPIO(t *testing.T) {
+	// Reserved bits set around IN1 and Out2 must not leak into the state.
+	frames, _ := ParseFrames(buildResponseFrame(0x00, CmdGetGPIO, StatusSuccess, []byte{0xE7}))
+	if len(frames) != 1 {
+		t.Fatalf("expected one frame, got %d", len(frames))
+	}
+	state, err := ParseGPIO(frames[0])
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if state.Inputs != 0x01 || state.Outputs != 0x02 || !state.Input(1) || state.Input(2) {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+	if _, err := ParseGPIO(Frame{Command: CmdGetGPIO, Status: StatusCmdError}); err == nil {
+		t.Fatalf("expected error for unsupported firmware")
+	}
+}
+
+func TestGPIOCommandPackets(t *testing.T) {
+	cases := []struct {
+		name string
+		got  []byte
+		want []byte
+	}{
+		{"set gpio", SetGPIOCommand(0x00, 0x02), []byte{0x05, 0x00, 0x46, 0x02, 0xD2, 0x5C}},
+		{"set gpio reserved bits", SetGPIOCommand(0x00, 0xFE), []byte{0x05, 0x00, 0x46, 0x02, 0xD2, 0x5C}},
+		{"get gpio", GetGPIOCommand(0x00), []byte{0x04, 0x00, 0x47, 0xE9, 0x6C}},
+	}
+	for _, tc := range cases {
+		if !bytes.Equal(tc.got, tc.want) {
+			t.Fatalf("%s packet: got % X want % X", tc.name, tc.got, tc.want)
+		}
+	}
+
+	// GetGPIO answer with IN1 high and Out2 set.
+	frames, _ := ParseFrames([]byte{0x06, 0x00, 0x47, 0x00, 0x21, 0xE8, 0xB1})
+	if len(frames) != 1 || !frames[0].CRCValid {
+		t.Fatalf("fixture must decode as one valid frame: %+v", frames)
+	}
+	if state, err := ParseGPIO(frames[0]); err != nil || state.Inputs != 0x01 || state.Outputs != 0x02 {
+		t.Fatalf("unexpected fixture state: %+v err=%v", state, err)
+	}
+}
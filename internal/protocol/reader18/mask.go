@@ -0,0 +1,113 @@
+package reader18
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Gen2 memory banks addressed by inventory masks.
+const (
+	MemBankEPC  byte = 0x01
+	MemBankTID  byte = 0x02
+	MemBankUser byte = 0x03
+)
+
+// EPCBitOffset is the first EPC bit in the EPC bank, after StoredCRC and PC.
+const EPCBitOffset uint16 = 0x20
+
+// InventoryMask is a Gen2 Select mask sent with inventory command 0x01.
+// Only tags whose Bank bits [Pointer, Pointer+Length) equal Data take part in
+// the round. Only match masks exist: the 0x01 mask has no inversion flag, so
+// "every tag but these" cannot be selected by the reader.
+type InventoryMask struct {
+	Bank    byte
+	Pointer uint16 // bit address inside Bank
+	Length  byte   // mask length in bits
+	Data    []byte // at least ceil(Length/8) bytes, MSB first
+}
+
+// Enabled reports whether the mask selects anything.
+func (m InventoryMask) Enabled() bool {
+	return m.Length > 0
+}
+
+// Validate checks bank and data length.
+func (m InventoryMask) Validate() error {
+	if !m.Enabled() {
+		return nil
+	}
+	if m.Bank < MemBankEPC || m.Bank > MemBankUser {
+		return fmt.Errorf("mask bank out of range: %d", m.Bank)
+	}
+	if need := (int(m.Length) + 7) / 8; len(m.Data) < need {
+		return fmt.Errorf("mask needs %d bytes for %d bits, got %d", need, m.Length, len(m.Data))
+	}
+	return nil
+}
+
+// EPCPrefixMask builds an EPC-bank mask from a hex prefix. Each hex digit is
+// 4 bits, so odd-length prefixes are allowed.
+func EPCPrefixMask(prefix string) (InventoryMask, error) {
+	prefix = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(prefix), " ", ""))
+	if prefix == "" {
+		return InventoryMask{}, nil
+	}
+	if len(prefix)*4 > 0xFF {
+		return InventoryMask{}, fmt.Errorf("mask prefix too long: %d hex digits", len(prefix))
+	}
+	padded := prefix
+	if len(padded)%2 == 1 {
+		padded += "0"
+	}
+	data, err := hex.DecodeString(padded)
+	if err != nil {
+		return InventoryMask{}, fmt.Errorf("invalid mask prefix %q", prefix)
+	}
+	return InventoryMask{
+		Bank:    MemBankEPC,
+		Pointer: EPCBitOffset,
+		Length:  byte(len(prefix) * 4),
+		Data:    data,
+	}, nil
+}
+
+// MatchEPC reports whether epc satisfies the mask. ok is
+// false when the mask cannot be judged from the EPC alone (other banks, or
+// a pointer into StoredCRC/PC).
+func (m InventoryMask) MatchEPC(epc []byte) (match bool, ok bool) {
+	if !m.Enabled() {
+		return true, true
+	}
+	if m.Bank != MemBankEPC || m.Pointer < EPCBitOffset {
+		return false, false
+	}
+	start := int(m.Pointer - EPCBitOffset)
+	match = start+int(m.Length) <= len(epc)*8
+	for i := 0; match && i < int(m.Length); i++ {
+		match = bitAt(epc, start+i) == bitAt(m.Data, i)
+	}
+	return match, true
+}
+
+func bitAt(data []byte, bit int) byte {
+	return (data[bit/8] >> (7 - uint(bit%8))) & 1
+}
+
+// InventoryG2MaskedCommand is InventoryG2Command with a Select mask.
+// Payload: Q,Session,MaskMem,MaskAdr(2),MaskLen,MaskData,[TIDAddr,TIDLen],Target,Antenna,ScanTime.
+// An empty or invalid mask is not sent; the plain command is returned.
+func InventoryG2MaskedCommand(address, qValue, session byte, mask InventoryMask, tidAddr, tidLen, target, antenna, scanTime byte) []byte {
+	if !mask.Enabled() || mask.Validate() != nil {
+		return InventoryG2Command(address, qValue, session, tidAddr, tidLen, target, antenna, scanTime)
+	}
+	maskBytes := (int(mask.Length) + 7) / 8
+	payload := make([]byte, 0, 10+maskBytes)
+	payload = append(payload, qValue, session, mask.Bank, byte(mask.Pointer>>8), byte(mask.Pointer), mask.Length)
+	payload = append(payload, mask.Data[:maskBytes]...)
+	if tidLen > 0 {
+		payload = append(payload, tidAddr, tidLen)
+	}
+	payload = append(payload, target, antenna, scanTime)
+	return BuildCommand(address, CmdInventory, payload)
+}
@@ -0,0 +1,58 @@
+package reader18
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestInventoryG2MaskedCommandLayout(t *testing.T) {
+	mask, err := EPCPrefixMask("E20")
+	if err != nil {
+		t.Fatalf("prefix mask: %v", err)
+	}
+	if mask.Length != 12 || !bytes.Equal(mask.Data, []byte{0xE2, 0x00}) {
+		t.Fatalf("unexpected mask: %+v", mask)
+	}
+
+	got := InventoryG2MaskedCommand(0x00, 0x04, 0x01, mask, 0x00, 0x06, 0x00, 0x80, 0x0A)
+	wantPayload := []byte{0x04, 0x01, MemBankEPC, 0x00, 0x20, 0x0C, 0xE2, 0x00, 0x00, 0x06, 0x00, 0x80, 0x0A}
+	if !bytes.Equal(got, BuildCommand(0x00, CmdInventory, wantPayload)) {
+		t.Fatalf("masked command mismatch: got %X", got)
+	}
+	if !VerifyPacket(got) {
+		t.Fatalf("masked command CRC invalid: %X", got)
+	}
+
+	short := InventoryMask{Bank: MemBankEPC, Pointer: EPCBitOffset, Length: 16, Data: []byte{0xE2}}
+	plain := InventoryG2Command(0x00, 0x04, 0x01, 0x00, 0x00, 0x00, 0x80, 0x0A)
+	if got := InventoryG2MaskedCommand(0x00, 0x04, 0x01, short, 0x00, 0x00, 0x00, 0x80, 0x0A); !bytes.Equal(got, plain) {
+		t.Fatalf("invalid mask must fall back to plain inventory: got %X", got)
+	}
+}
+
+func TestInventoryMaskMatchEPC(t *testing.T) {
+	mask, _ := EPCPrefixMask("E20")
+	cases := []struct {
+		epc   []byte
+		match bool
+	}{
+		{[]byte{0xE2, 0x0F, 0x11}, true},
+		{[]byte{0xE2, 0x1F, 0x11}, false},
+		{[]byte{0xE2}, false},
+		{[]byte{0x30, 0x00}, false},
+	}
+	for _, tc := range cases {
+		match, ok := mask.MatchEPC(tc.epc)
+		if !ok || match != tc.match {
+			t.Fatalf("%X: got match=%v ok=%v", tc.epc, match, ok)
+		}
+	}
+
+	tidMask := InventoryMask{Bank: MemBankTID, Length: 8, Data: []byte{0xE2}}
+	if _, ok := tidMask.MatchEPC([]byte{0xE2}); ok {
+		t.Fatalf("TID-bank mask cannot be judged from EPC")
+	}
+	if err := (InventoryMask{Bank: MemBankEPC, Length: 16, Data: []byte{0xE2}}).Validate(); err == nil {
+		t.Fatalf("expected short mask data error")
+	}
+}
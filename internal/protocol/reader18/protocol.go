@@ -2,6 +2,7 @@ package reader18
 
 import (
 	"fmt"
+	"math/bits"
 )
 
 // Command codes from UHFReader18 style protocol.
@@ -64,11 +65,37 @@ func VerifyPacket(packet []byte) bool {
 	return byte(crc&0xFF) == packet[len(packet)-2] && byte(crc>>8) == packet[len(packet)-1]
 }
 
+// ParseStats counts what ParseFramesStats discarded while resynchronising.
+type ParseStats struct {
+	// CRCErrors is the number of length-plausible candidates whose CRC failed.
+	CRCErrors int
+	// SkippedBytes is the number of bytes dropped to find the next frame.
+	SkippedBytes int
+	// Truncations is the number of times the buffered start of a frame was
+	// thrown away before the rest of it arrived (Decoder.Discard).
+	Truncations int
+}
+
+// Add accumulates o into s.
+func (s *ParseStats) Add(o ParseStats) {
+	s.CRCErrors += o.CRCErrors
+	s.SkippedBytes += o.SkippedBytes
+	s.Truncations += o.Truncations
+}
+
 // ParseFrames decodes as many valid frames as possible from stream data.
 // It returns parsed frames and remaining bytes that were not enough for a full frame.
 func ParseFrames(stream []byte) (frames []Frame, remaining []byte) {
+	frames, remaining, _ = ParseFramesStats(stream)
+	return frames, remaining
+}
+
+// ParseFramesStats is ParseFrames that also reports resync statistics.
+// Every input byte ends up in exactly one of: a frame's Raw, the skipped
+// count or remaining. remaining is always shorter than the longest frame.
+func ParseFramesStats(stream []byte) (frames []Frame, remaining []byte, stats ParseStats) {
 	if len(stream) == 0 {
-		return nil, nil
+		return nil, nil, stats
 	}
 
 	buf := stream
@@ -82,6 +109,7 @@ func ParseFrames(stream []byte) (frames []Frame, remaining []byte) {
 		total := int(buf[0]) + 1
 		if total < 6 {
 			buf = buf[1:]
+			stats.SkippedBytes++
 			continue
 		}
 		if total > len(buf) {
@@ -91,6 +119,8 @@ func ParseFrames(stream []byte) (frames []Frame, remaining []byte) {
 		raw := buf[:total]
 		if !VerifyPacket(raw) {
 			buf = buf[1:]
+			stats.CRCErrors++
+			stats.SkippedBytes++
 			continue
 		}
 
@@ -116,7 +146,7 @@ func ParseFrames(stream []byte) (frames []Frame, remaining []byte) {
 
 	remaining = make([]byte, len(buf))
 	copy(remaining, buf)
-	return frames, remaining
+	return frames, remaining, stats
 }
 
 // InventorySingleCommand returns a one-shot inventory command.
@@ -215,6 +245,8 @@ type InventoryG2Tag struct {
 
 // ParseInventoryG2Tags parses inventory payload from command 0x01.
 // Data format: AntMask(1), TagNum(1), repeated [EpcLen(1), EPC(n), RSSI(1)].
+// A round sent with TIDLen > 0 carries the TID in place of the EPC, so the
+// SDK always inventories EPCs and reads TIDs with ReadData.
 func ParseInventoryG2Tags(frame Frame) ([]InventoryG2Tag, error) {
 	if frame.Command != CmdInventory {
 		return nil, fmt.Errorf("not inventory frame")
@@ -288,6 +320,10 @@ func ParseSingleInventoryResult(frame Frame) (SingleInventoryResult, error) {
 	}, nil
 }
 
+// antennaIDFromMask maps the antenna byte of a tag record to a 1-based
+// port. Readers send a one-hot mask; some firmware sends a 0-based index
+// instead. Anything else resolves to the lowest set bit so the port stays
+// within 1..8.
 func antennaIDFromMask(mask byte) int {
 	switch mask {
 	case 1:
@@ -306,9 +342,11 @@ func antennaIDFromMask(mask byte) int {
 		return 7
 	case 128:
 		return 8
-	default:
+	}
+	if mask < 8 {
 		return int(mask) + 1
 	}
+	return bits.TrailingZeros8(mask) + 1
 }
 
 // crc-16-mcrf4xx (poly 0x8408, init 0xFFFF, refin/refout true).
@@ -180,3 +180,30 @@ func TestParseSingleInventoryResultStatusSuccess(t *testing.T) {
 		t.Fatalf("unexpected tag count: got %d", result.TagCount)
 	}
 }
+
+func TestParseInventoryG2TagsWireFrame(t *testing.T) {
+	// Len, Adr, 0x01, Status, Ant, Num, then Num x [EPCLen, EPC, RSSI].
+	wire := []byte{
+		0x1B, 0x00, 0x01, 0x01, 0x02, 0x02,
+		0x04, 0xE2, 0x00, 0x12, 0x34, 0x4A,
+		0x0C, 0x30, 0x34, 0x25, 0x7B, 0xF7, 0x19, 0x4E, 0x40, 0x00, 0x00, 0x00, 0x42, 0x3C,
+		0x64, 0xCD,
+	}
+	frames, _ := ParseFrames(wire)
+	if len(frames) != 1 || !frames[0].CRCValid || frames[0].Status != StatusNoTag {
+		t.Fatalf("fixture must decode as one valid frame: %+v", frames)
+	}
+	tags, err := ParseInventoryG2Tags(frames[0])
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if len(tags) != 2 {
+		t.Fatalf("expected 2 tags, got %d", len(tags))
+	}
+	if !bytes.Equal(tags[0].EPC, []byte{0xE2, 0x00, 0x12, 0x34}) || tags[0].RSSI != 0x4A || tags[0].Antenna != 2 {
+		t.Fatalf("unexpected first tag: %+v", tags[0])
+	}
+	if len(tags[1].EPC) != 12 || tags[1].EPC[0] != 0x30 || tags[1].RSSI != 0x3C {
+		t.Fatalf("unexpected second tag: %+v", tags[1])
+	}
+}
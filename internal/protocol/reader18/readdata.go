@@ -0,0 +1,51 @@
+package reader18
+
+import "fmt"
+
+// CmdReadData reads words from one memory bank of the tag whose EPC is given
+// (vendor SDK ReadData_G2).
+const CmdReadData byte = 0x02
+
+// StatusTagError is the ReadData status for a tag-side failure; the Gen2
+// error code follows in the data byte.
+const StatusTagError byte = 0xFC
+
+// ReadDataCommand builds a 0x02 packet reading words words from bank,
+// starting at word wordPtr, on the tag with the given EPC.
+// Payload: ENum, EPC(ENum words), Mem, WordPtr, Num, Password(4).
+// The EPC must be 1..15 whole words.
+func ReadDataCommand(address byte, epc []byte, bank, wordPtr, words byte, password [4]byte) ([]byte, error) {
+	if len(epc) == 0 || len(epc)%2 != 0 || len(epc) > 30 {
+		return nil, fmt.Errorf("read data needs an EPC of 1..15 words, got %d bytes", len(epc))
+	}
+	if words == 0 {
+		return nil, fmt.Errorf("read data needs at least one word")
+	}
+	payload := make([]byte, 0, 8+len(epc))
+	payload = append(payload, byte(len(epc)/2))
+	payload = append(payload, epc...)
+	payload = append(payload, bank, wordPtr, words)
+	payload = append(payload, password[:]...)
+	return BuildCommand(address, CmdReadData, payload), nil
+}
+
+// ParseReadData returns a copy of the words*2 data bytes of a 0x02 reply.
+func ParseReadData(frame Frame, words int) ([]byte, error) {
+	if frame.Command != CmdReadData {
+		return nil, fmt.Errorf("not read data frame")
+	}
+	switch frame.Status {
+	case StatusSuccess:
+	case StatusTagError:
+		if len(frame.Data) > 0 {
+			return nil, fmt.Errorf("read data tag error 0x%02X", frame.Data[0])
+		}
+		return nil, fmt.Errorf("read data tag error")
+	default:
+		return nil, fmt.Errorf("read data status 0x%02X", frame.Status)
+	}
+	if len(frame.Data) < words*2 {
+		return nil, fmt.Errorf("read data returned %d bytes, want %d", len(frame.Data), words*2)
+	}
+	return append([]byte(nil), frame.Data[:words*2]...), nil
+}
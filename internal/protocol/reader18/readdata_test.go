@@ -0,0 +1,53 @@
+package reader18
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReadDataCommandPacket(t *testing.T) {
+	got, err := ReadDataCommand(0x00, []byte{0xE2, 0x00, 0x12, 0x34}, MemBankTID, 0, 2, [4]byte{})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	// Len, Adr, 0x02, ENum, EPC, Mem, WordPtr, Num, Pwd(4), CRC.
+	want := []byte{0x10, 0x00, 0x02, 0x02, 0xE2, 0x00, 0x12, 0x34, 0x02, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0xCA, 0xC0}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("read data packet: got % X want % X", got, want)
+	}
+	for _, epc := range [][]byte{nil, {0xE2}, make([]byte, 32)} {
+		if _, err := ReadDataCommand(0x00, epc, MemBankTID, 0, 2, [4]byte{}); err == nil {
+			t.Fatalf("expected error for %d-byte EPC", len(epc))
+		}
+	}
+}
+
+func TestParseReadData(t *testing.T) {
+	// Six TID words read from a tag.
+	frames, _ := ParseFrames([]byte{
+		0x11, 0x00, 0x02, 0x00,
+		0xE2, 0x80, 0x11, 0x60, 0x20, 0x00, 0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC,
+		0x0E, 0x69,
+	})
+	if len(frames) != 1 || !frames[0].CRCValid {
+		t.Fatalf("fixture must decode as one valid frame: %+v", frames)
+	}
+	data, err := ParseReadData(frames[0], 6)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !bytes.Equal(data, []byte{0xE2, 0x80, 0x11, 0x60, 0x20, 0x00, 0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC}) {
+		t.Fatalf("unexpected data: % X", data)
+	}
+	if _, err := ParseReadData(frames[0], 7); err == nil {
+		t.Fatalf("expected error for a short reply")
+	}
+
+	tagErr, _ := ParseFrames([]byte{0x06, 0x00, 0x02, 0xFC, 0x04, 0x24, 0x2D})
+	if len(tagErr) != 1 {
+		t.Fatalf("tag error fixture must decode")
+	}
+	if _, err := ParseReadData(tagErr[0], 6); err == nil || err.Error() != "read data tag error 0x04" {
+		t.Fatalf("expected tag error 0x04, got %v", err)
+	}
+}
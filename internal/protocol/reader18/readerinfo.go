@@ -0,0 +1,135 @@
+package reader18
+
+import "fmt"
+
+// Work modes for SetWorkMode (0x35) Read_mode byte.
+const (
+	WorkModeAnswer      byte = 0x00
+	WorkModeActive      byte = 0x01
+	WorkModeTriggerLow  byte = 0x02
+	WorkModeTriggerHigh byte = 0x03
+)
+
+// ReaderInfo is decoded data from GetReaderInfo (0x21).
+// Layout: Version(2), Type(1), Protocols(1), MaxFre(1), MinFre(1), Power(1),
+// ScanTime(1), then optional Ant(1), BeepEn(1), Reserved(2), CheckAnt(1)
+// on multi-antenna firmware.
+type ReaderInfo struct {
+	VersionMajor byte
+	VersionMinor byte
+	Type         byte
+	Protocols    byte
+	// RegionHigh/RegionLow use the same packing as SetRegion (0x22).
+	RegionHigh  byte
+	RegionLow   byte
+	OutputPower byte
+	ScanTime    byte
+	// HasAntenna is false on firmware that does not report the antenna mask.
+	HasAntenna  bool
+	AntennaMask byte
+	BeepOn      bool
+}
+
+// Version renders the firmware version as "major.minor".
+func (i ReaderInfo) Version() string {
+	return fmt.Sprintf("%d.%02d", i.VersionMajor, i.VersionMinor)
+}
+
+// ParseReaderInfo decodes a successful 0x21 response.
+func ParseReaderInfo(frame Frame) (ReaderInfo, error) {
+	if frame.Command != CmdGetReaderInfo {
+		return ReaderInfo{}, fmt.Errorf("not reader-info frame")
+	}
+	if frame.Status != StatusSuccess {
+		return ReaderInfo{}, fmt.Errorf("reader-info status 0x%02X", frame.Status)
+	}
+	d := frame.Data
+	if len(d) < 8 {
+		return ReaderInfo{}, fmt.Errorf("reader-info payload too short: %d", len(d))
+	}
+	info := ReaderInfo{
+		VersionMajor: d[0],
+		VersionMinor: d[1],
+		Type:         d[2],
+		Protocols:    d[3],
+		RegionHigh:   d[4],
+		RegionLow:    d[5],
+		OutputPower:  d[6],
+		ScanTime:     d[7],
+	}
+	if len(d) >= 9 {
+		info.HasAntenna = true
+		info.AntennaMask = d[8]
+	}
+	if len(d) >= 10 {
+		info.BeepOn = d[9] != 0
+	}
+	return info, nil
+}
+
+// WorkMode is decoded data from GetWorkMode (0x36).
+// Layout: Wg_mode, Wg_Data_Interval, Wg_Pulse_Width, Wg_Pulse_Interval,
+// Read_mode, Mode_state, Mem_Inven, First_Adr, Word_Num, Tag_Time, ...
+type WorkMode struct {
+	ReadMode  byte
+	ModeState byte
+	MemInven  byte
+	FirstAdr  byte
+	WordNum   byte
+	TagTime   byte
+	Raw       []byte
+}
+
+// GetWorkModeCommand queries work mode parameters.
+func GetWorkModeCommand(address byte) []byte {
+	return BuildCommand(address, CmdGetWorkMode, nil)
+}
+
+// ParseWorkMode decodes a successful 0x36 response. Fields past Read_mode
+// are optional.
+func ParseWorkMode(frame Frame) (WorkMode, error) {
+	if frame.Command != CmdGetWorkMode {
+		return WorkMode{}, fmt.Errorf("not work-mode frame")
+	}
+	if frame.Status != StatusSuccess {
+		return WorkMode{}, fmt.Errorf("work-mode status 0x%02X", frame.Status)
+	}
+	d := frame.Data
+	if len(d) < 5 {
+		return WorkMode{}, fmt.Errorf("work-mode payload too short: %d", len(d))
+	}
+	mode := WorkMode{ReadMode: d[4], Raw: append([]byte(nil), d...)}
+	fields := []*byte{&mode.ModeState, &mode.MemInven, &mode.FirstAdr, &mode.WordNum, &mode.TagTime}
+	for i, field := range fields {
+		if 5+i < len(d) {
+			*field = d[5+i]
+		}
+	}
+	return mode, nil
+}
+
+// WorkModeByName maps a WorkModeName label back to its Read_mode value.
+func WorkModeByName(name string) (byte, bool) {
+	for _, mode := range []byte{WorkModeAnswer, WorkModeActive, WorkModeTriggerLow, WorkModeTriggerHigh} {
+		if WorkModeName(mode) == name {
+			return mode, true
+		}
+	}
+	return 0, false
+}
+
+// WorkModeName is a short label for a Read_mode value.
+func WorkModeName(mode byte) string {
+	switch mode {
+	case WorkModeAnswer:
+		return "answer"
+	case WorkModeActive:
+		return "active"
+	case WorkModeTriggerLow:
+		return "trigger-low"
+	case WorkModeTriggerHigh:
+		return "trigger-high"
+	default:
+		return fmt.Sprintf("0x%02X", mode)
+	}
+}
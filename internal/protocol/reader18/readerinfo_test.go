@@ -0,0 +1,42 @@
+package reader18
+
+import "testing"
+
+func TestParseReaderInfo(t *testing.T) {
+	frames, _ := ParseFrames(buildResponseFrame(0x00, CmdGetReaderInfo, StatusSuccess,
+		[]byte{0x02, 0x1E, 0x0C, 0x01, 0x31, 0x80, 0x1E, 0x0A, 0x0F, 0x01, 0x00, 0x00, 0x00}))
+	if len(frames) != 1 {
+		t.Fatalf("expected one frame, got %d", len(frames))
+	}
+	info, err := ParseReaderInfo(frames[0])
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if info.Version() != "2.30" || info.RegionHigh != 0x31 || info.RegionLow != 0x80 || info.OutputPower != 0x1E || info.ScanTime != 0x0A {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+	if !info.HasAntenna || info.AntennaMask != 0x0F || !info.BeepOn {
+		t.Fatalf("unexpected antenna/beep: %+v", info)
+	}
+
+	short := Frame{Command: CmdGetReaderInfo, Status: StatusSuccess, Data: []byte{0x02, 0x1E, 0x0C, 0x01, 0x31, 0x80, 0x1E, 0x0A}}
+	if info, err := ParseReaderInfo(short); err != nil || info.HasAntenna {
+		t.Fatalf("single-antenna info: %+v err=%v", info, err)
+	}
+}
+
+func TestParseWorkMode(t *testing.T) {
+	mode, err := ParseWorkMode(Frame{Command: CmdGetWorkMode, Status: StatusSuccess, Data: []byte{0, 0, 0, 0, WorkModeActive, 0x02, 0x01, 0x02, 0x06}})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if mode.ReadMode != WorkModeActive || mode.ModeState != 0x02 || mode.WordNum != 0x06 || mode.TagTime != 0 {
+		t.Fatalf("unexpected work mode: %+v", mode)
+	}
+	if _, err := ParseWorkMode(Frame{Command: CmdGetWorkMode, Status: StatusSuccess, Data: []byte{0, 0}}); err == nil {
+		t.Fatalf("expected short payload error")
+	}
+	if got, want := GetWorkModeCommand(0x00), []byte{0x04, 0x00, 0x36, 0xE7, 0x0E}; string(got) != string(want) {
+		t.Fatalf("get work-mode command mismatch: got %X want %X", got, want)
+	}
+}
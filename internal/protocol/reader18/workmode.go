@@ -0,0 +1,87 @@
+package reader18
+
+import "fmt"
+
+// CmdActiveData is the unsolicited tag frame sent in active and trigger modes.
+const CmdActiveData byte = 0xEE
+
+// Mode_state bits used for streaming modes: RS232/RS485 output, beeper on.
+const activeModeState byte = 0x02
+
+// Mem_Inven value for multi-tag EPC inventory. Streaming modes report EPC
+// only; TID is not read in them.
+const activeMemInven byte = 0x04
+
+// ActiveFrame selects the 0xEE payload layout. Firmware does not announce
+// it in the frame, so it is part of the work-mode configuration.
+type ActiveFrame byte
+
+const (
+	// ActiveFrameAntenna is Ant(1), EPCLen(1), EPC(n), RSSI(1), sent by
+	// multi-antenna firmware.
+	ActiveFrameAntenna ActiveFrame = iota
+	// ActiveFrameEPC is the bare EPC sent by older single-antenna firmware.
+	ActiveFrameEPC
+)
+
+// ActiveFrameByName parses "antenna" or "epc".
+func ActiveFrameByName(name string) (ActiveFrame, bool) {
+	switch name {
+	case "antenna":
+		return ActiveFrameAntenna, true
+	case "epc":
+		return ActiveFrameEPC, true
+	default:
+		return 0, false
+	}
+}
+
+// WorkModePayload builds the SetWorkMode (0x35) payload. Answer mode keeps
+// the one-byte form; streaming modes send
+// Read_mode, Mode_state, Mem_Inven, First_Adr, Word_Num, Tag_Time, where
+// tagTime is the firmware's same-tag suppression time in seconds.
+func WorkModePayload(mode, tagTime byte) []byte {
+	if mode == WorkModeAnswer {
+		return []byte{WorkModeAnswer}
+	}
+	return []byte{mode, activeModeState, activeMemInven, 0x00, 0x00, tagTime}
+}
+
+// IsStreamingMode reports whether the reader pushes tags without polling.
+func IsStreamingMode(mode byte) bool {
+	switch mode {
+	case WorkModeActive, WorkModeTriggerLow, WorkModeTriggerHigh:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseActiveData decodes one 0xEE frame in the configured layout.
+func ParseActiveData(frame Frame, layout ActiveFrame) (InventoryG2Tag, error) {
+	if frame.Command != CmdActiveData {
+		return InventoryG2Tag{}, fmt.Errorf("not active-data frame")
+	}
+	d := frame.Data
+	if len(d) == 0 {
+		return InventoryG2Tag{}, fmt.Errorf("active-data payload empty")
+	}
+	switch layout {
+	case ActiveFrameEPC:
+		return InventoryG2Tag{EPC: append([]byte(nil), d...)}, nil
+	case ActiveFrameAntenna:
+		if len(d) < 3 {
+			return InventoryG2Tag{}, fmt.Errorf("active-data payload too short: %d", len(d))
+		}
+		if d[1] == 0 || int(d[1])+3 != len(d) {
+			return InventoryG2Tag{}, fmt.Errorf("active-data length mismatch: epc_len=%d payload=%d", d[1], len(d))
+		}
+		return InventoryG2Tag{
+			Antenna: antennaIDFromMask(d[0]),
+			EPC:     append([]byte(nil), d[2:2+int(d[1])]...),
+			RSSI:    int(d[len(d)-1]),
+		}, nil
+	default:
+		return InventoryG2Tag{}, fmt.Errorf("unknown active-data layout %d", layout)
+	}
+}
@@ -0,0 +1,53 @@
+package reader18
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWorkModePayload(t *testing.T) {
+	if got := WorkModePayload(WorkModeAnswer, 3); !bytes.Equal(got, []byte{0x00}) {
+		t.Fatalf("answer payload: %X", got)
+	}
+	if got := WorkModePayload(WorkModeTriggerHigh, 3); !bytes.Equal(got, []byte{0x03, 0x02, 0x04, 0x00, 0x00, 0x03}) {
+		t.Fatalf("trigger payload: %X", got)
+	}
+	if IsStreamingMode(WorkModeAnswer) || !IsStreamingMode(WorkModeActive) {
+		t.Fatalf("unexpected streaming classification")
+	}
+}
+
+func TestParseActiveData(t *testing.T) {
+	epc := []byte{0xE2, 0x00, 0x00, 0x01}
+	tag, err := ParseActiveData(Frame{Command: CmdActiveData, Data: append(append([]byte{0x02, byte(len(epc))}, epc...), 0x48)}, ActiveFrameAntenna)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if tag.Antenna != 2 || tag.RSSI != 0x48 || !bytes.Equal(tag.EPC, epc) {
+		t.Fatalf("unexpected framed tag: %+v", tag)
+	}
+
+	bare, err := ParseActiveData(Frame{Command: CmdActiveData, Data: epc}, ActiveFrameEPC)
+	if err != nil || !bytes.Equal(bare.EPC, epc) || bare.Antenna != 0 {
+		t.Fatalf("unexpected bare tag: %+v err=%v", bare, err)
+	}
+	if _, err := ParseActiveData(Frame{Command: CmdActiveData}, ActiveFrameEPC); err == nil {
+		t.Fatalf("expected empty payload error")
+	}
+}
+
+func TestParseActiveDataFollowsConfiguredLayout(t *testing.T) {
+	// Byte 1 of this bare EPC happens to equal len-3, which the layout must
+	// not be guessed from.
+	epc := []byte{0x30, 0x02, 0xAA, 0xBB, 0xCC}
+	bare, err := ParseActiveData(Frame{Command: CmdActiveData, Data: epc}, ActiveFrameEPC)
+	if err != nil || !bytes.Equal(bare.EPC, epc) {
+		t.Fatalf("bare layout must keep the whole payload: %+v err=%v", bare, err)
+	}
+	if _, err := ParseActiveData(Frame{Command: CmdActiveData, Data: []byte{0xE2, 0x00, 0x11, 0x22}}, ActiveFrameAntenna); err == nil {
+		t.Fatalf("antenna layout must reject a length mismatch")
+	}
+	if mode, ok := ActiveFrameByName("epc"); !ok || mode != ActiveFrameEPC {
+		t.Fatalf("unexpected layout name lookup: %v %v", mode, ok)
+	}
+}
@@ -0,0 +1,326 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+// serialBus shares one serial port between the Clients attached to it. On
+// RS-485 several readers answer on the same line, so received bytes are
+// split into Reader18 frames and routed by their address byte.
+//
+// The line is half-duplex: a command sent while another reader is still
+// answering collides with that reply. A write therefore takes the bus
+// token, which stays held until the addressed reader sends the final frame
+// of its reply or the turn times out.
+type serialBus struct {
+	cfg  SerialConfig
+	port Transport
+
+	// token has room for one turn; a write blocks until it can put one in.
+	token chan struct{}
+
+	mu    sync.Mutex
+	turn  *busTurn
+	drops []*busDrop
+	err   error
+}
+
+// busTurn is the command whose reply currently owns the line.
+type busTurn struct {
+	drop *busDrop
+	cmd  byte
+	done chan struct{}
+}
+
+// Unparsed bytes kept between port reads. A backlog past maxPendingBytes
+// means the line carries no valid frames; it is cut to the last
+// keepPendingBytes, which still holds any frame in progress (a Reader18
+// frame is at most 256 bytes: Len is one byte and counts all but itself).
+const (
+	maxPendingBytes  = 4096
+	keepPendingBytes = 256
+)
+
+// A turn ends at the reply's final frame; an inventory reply with status
+// inventoryMoreFrames is followed by more. busTurnaround bounds a turn whose
+// reply never comes, on top of the scan time an inventory command asks for.
+const (
+	inventoryMoreFrames byte = 0x03
+	busTurnaround            = time.Second
+)
+
+// buses holds the open serial ports by device path. Lock order is buses
+// before serialBus.mu.
+var buses = struct {
+	sync.Mutex
+	m map[string]*serialBus
+}{m: make(map[string]*serialBus)}
+
+// attachSerial opens cfg.Device, or joins it when another Client already
+// has it open, and returns the transport for cfg.Address.
+func attachSerial(cfg SerialConfig) (*busDrop, error) {
+	cfg = cfg.withDefaults()
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	buses.Lock()
+	defer buses.Unlock()
+
+	bus := buses.m[cfg.Device]
+	if bus == nil {
+		port, err := openSerial(cfg)
+		if err != nil {
+			return nil, err
+		}
+		bus = &serialBus{cfg: cfg, port: port, token: make(chan struct{}, 1)}
+		buses.m[cfg.Device] = bus
+		go bus.readLoop()
+	} else if !bus.cfg.sameLine(cfg) {
+		return nil, fmt.Errorf("%s already open as %s", cfg.Device, bus.cfg)
+	}
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	if bus.err != nil {
+		return nil, bus.err
+	}
+	for _, d := range bus.drops {
+		if d.address == cfg.Address {
+			return nil, fmt.Errorf("%s: reader address 0x%02X already attached", cfg.Device, cfg.Address)
+		}
+	}
+	drop := &busDrop{
+		bus:     bus,
+		address: cfg.Address,
+		frames:  make(chan []byte, 256),
+		closed:  make(chan struct{}),
+	}
+	bus.drops = append(bus.drops, drop)
+	return drop, nil
+}
+
+func (b *serialBus) readLoop() {
+	buf := make([]byte, 4096)
+	var pending []byte
+	for {
+		n, err := b.port.Read(buf)
+		if err != nil {
+			b.fail(err)
+			return
+		}
+		pending = append(pending, buf[:n]...)
+		frames, remaining := reader18.ParseFrames(pending)
+		pending = append(pending[:0], remaining...)
+		if len(pending) > maxPendingBytes {
+			pending = pending[len(pending)-keepPendingBytes:]
+		}
+		b.mu.Lock()
+		for _, frame := range frames {
+			if b.turn != nil && b.turn.final(frame) {
+				b.endTurnLocked(b.turn)
+			}
+			for _, d := range b.drops {
+				if d.address != BroadcastAddress && d.address != frame.Address {
+					continue
+				}
+				select {
+				case d.frames <- frame.Raw:
+				default:
+				}
+			}
+		}
+		b.mu.Unlock()
+	}
+}
+
+// fail ends every attached Client with err and releases the device.
+func (b *serialBus) fail(err error) {
+	buses.Lock()
+	b.mu.Lock()
+	b.err = err
+	drops := b.drops
+	b.drops = nil
+	for _, d := range drops {
+		close(d.frames)
+	}
+	b.mu.Unlock()
+	b.unregisterLocked()
+	buses.Unlock()
+	_ = b.port.Close()
+}
+
+// unregisterLocked drops the bus from the registry; callers hold buses.
+func (b *serialBus) unregisterLocked() {
+	if buses.m[b.cfg.Device] == b {
+		delete(buses.m, b.cfg.Device)
+	}
+}
+
+// detach removes d. The last-user check and the registry removal happen
+// under the buses lock, so attachSerial either joins a live bus or opens a
+// new one, never a bus that is closing. The port is closed after the lock
+// is released because closing it ends readLoop, which may call fail.
+func (b *serialBus) detach(d *busDrop) {
+	buses.Lock()
+	b.mu.Lock()
+	for i, other := range b.drops {
+		if other == d {
+			b.drops = append(b.drops[:i], b.drops[i+1:]...)
+			break
+		}
+	}
+	last := len(b.drops) == 0 && b.err == nil
+	b.mu.Unlock()
+	if last {
+		b.unregisterLocked()
+	}
+	buses.Unlock()
+	if last {
+		_ = b.port.Close()
+	}
+}
+
+// write sends p for d once the bus token is free. The wait is bounded by
+// the turn in progress, so it does not count against the write deadline: a
+// peer's long inventory round must not fail this Client's send.
+func (b *serialBus) write(d *busDrop, p []byte, deadline time.Time) (int, error) {
+	start := time.Now()
+	select {
+	case b.token <- struct{}{}:
+	case <-d.closed:
+		return 0, io.ErrClosedPipe
+	}
+	if !deadline.IsZero() {
+		deadline = deadline.Add(time.Since(start))
+	}
+
+	turn := &busTurn{drop: d, done: make(chan struct{})}
+	if len(p) >= 3 {
+		turn.cmd = p[2]
+	}
+	b.mu.Lock()
+	b.turn = turn
+	b.mu.Unlock()
+
+	_ = b.port.SetWriteDeadline(deadline)
+	n, err := b.port.Write(p)
+	if err != nil {
+		b.endTurn(turn)
+		return n, err
+	}
+	go b.holdTurn(turn, turnTimeout(p))
+	return n, nil
+}
+
+// holdTurn gives the line back when the turn's reply did not end it in time
+// or its Client left.
+func (b *serialBus) holdTurn(turn *busTurn, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-turn.done:
+		return
+	case <-timer.C:
+	case <-turn.drop.closed:
+	}
+	b.endTurn(turn)
+}
+
+func (b *serialBus) endTurn(turn *busTurn) {
+	b.mu.Lock()
+	b.endTurnLocked(turn)
+	b.mu.Unlock()
+}
+
+// endTurnLocked releases the token if turn still holds it; callers hold mu.
+func (b *serialBus) endTurnLocked(turn *busTurn) {
+	if b.turn != turn {
+		return
+	}
+	b.turn = nil
+	close(turn.done)
+	<-b.token
+}
+
+// final reports whether frame is the last one of the turn's reply.
+func (t *busTurn) final(frame reader18.Frame) bool {
+	if t.drop.address != BroadcastAddress && t.drop.address != frame.Address {
+		return false
+	}
+	if frame.Command != t.cmd {
+		return false
+	}
+	return frame.Command != reader18.CmdInventory || frame.Status != inventoryMoreFrames
+}
+
+// turnTimeout is how long the line may wait for the reply to command p: an
+// inventory runs for its ScanTime (last payload byte, 100 ms units) first.
+func turnTimeout(p []byte) time.Duration {
+	if len(p) > 5 && p[2] == reader18.CmdInventory {
+		return busTurnaround + time.Duration(p[len(p)-3])*100*time.Millisecond
+	}
+	return busTurnaround
+}
+
+// busDrop is one reader address on a serialBus.
+type busDrop struct {
+	bus     *serialBus
+	address byte
+	frames  chan []byte
+	pending []byte
+	closed  chan struct{}
+	once    sync.Once
+
+	mu       sync.Mutex
+	deadline time.Time
+}
+
+func (d *busDrop) Read(p []byte) (int, error) {
+	if len(d.pending) == 0 {
+		select {
+		case data, ok := <-d.frames:
+			if !ok {
+				d.bus.mu.Lock()
+				err := d.bus.err
+				d.bus.mu.Unlock()
+				if err == nil {
+					err = io.EOF
+				}
+				return 0, err
+			}
+			d.pending = data
+		case <-d.closed:
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
+
+func (d *busDrop) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	deadline := d.deadline
+	d.mu.Unlock()
+	return d.bus.write(d, p, deadline)
+}
+
+func (d *busDrop) SetWriteDeadline(t time.Time) error {
+	d.mu.Lock()
+	d.deadline = t
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *busDrop) Close() error {
+	d.once.Do(func() {
+		close(d.closed)
+		d.bus.detach(d)
+	})
+	return nil
+}
@@ -0,0 +1,239 @@
+package reader
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Capture directions. DirOpen marks the start of a session and carries the
+// endpoint address instead of data.
+const (
+	DirTX   = "tx"
+	DirRX   = "rx"
+	DirOpen = "open"
+)
+
+// CaptureEntry is one chunk of a recorded session.
+type CaptureEntry struct {
+	Time     time.Time
+	Dir      string
+	Data     []byte
+	Endpoint string
+}
+
+// captureLine is the JSON Lines layout of a CaptureEntry.
+type captureLine struct {
+	Time     time.Time `json:"t"`
+	Dir      string    `json:"dir"`
+	Hex      string    `json:"hex,omitempty"`
+	Endpoint string    `json:"endpoint,omitempty"`
+}
+
+// Recorder appends TX/RX chunks with timestamps to a capture stream, one
+// JSON object per line. It is safe for concurrent use by several sessions.
+type Recorder struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// NewRecorder records to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// Err returns the first write error, after which recording stops.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(entry CaptureEntry) {
+	line := captureLine{Time: entry.Time, Dir: entry.Dir, Endpoint: entry.Endpoint}
+	if len(entry.Data) > 0 {
+		line.Hex = strings.ToUpper(hex.EncodeToString(entry.Data))
+	}
+	data, err := json.Marshal(line)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	_, r.err = r.w.Write(append(data, '\n'))
+}
+
+// Wrap returns t with every Read and Write recorded.
+func (r *Recorder) Wrap(t Transport, endpoint string) Transport {
+	r.record(CaptureEntry{Time: time.Now(), Dir: DirOpen, Endpoint: endpoint})
+	return &recordingTransport{Transport: t, rec: r}
+}
+
+type recordingTransport struct {
+	Transport
+	rec *Recorder
+}
+
+func (t *recordingTransport) Read(p []byte) (int, error) {
+	n, err := t.Transport.Read(p)
+	if n > 0 {
+		t.rec.record(CaptureEntry{Time: time.Now(), Dir: DirRX, Data: p[:n]})
+	}
+	return n, err
+}
+
+func (t *recordingTransport) Write(p []byte) (int, error) {
+	n, err := t.Transport.Write(p)
+	if n > 0 {
+		t.rec.record(CaptureEntry{Time: time.Now(), Dir: DirTX, Data: p[:n]})
+	}
+	return n, err
+}
+
+// ReadCapture parses a capture stream. Blank lines are skipped.
+func ReadCapture(r io.Reader) ([]CaptureEntry, error) {
+	var entries []CaptureEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+		var line captureLine
+		if err := json.Unmarshal([]byte(raw), &line); err != nil {
+			return nil, fmt.Errorf("capture line %d: %w", lineNo, err)
+		}
+		data, err := hex.DecodeString(line.Hex)
+		if err != nil {
+			return nil, fmt.Errorf("capture line %d: %w", lineNo, err)
+		}
+		entries = append(entries, CaptureEntry{Time: line.Time, Dir: line.Dir, Data: data, Endpoint: line.Endpoint})
+	}
+	return entries, scanner.Err()
+}
+
+// LoadCapture reads a capture file.
+func LoadCapture(path string) ([]CaptureEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCapture(f)
+}
+
+// ReplayConfig points a Client at a capture file instead of a reader.
+type ReplayConfig struct {
+	File string
+	// Speed scales playback: 1 keeps the recorded timing, 10 is ten times
+	// faster. Zero means 1.
+	Speed float64
+}
+
+// OpenReplay loads cfg.File and returns a transport that plays its RX
+// chunks back. See NewReplay.
+func OpenReplay(cfg ReplayConfig) (Transport, error) {
+	entries, err := LoadCapture(cfg.File)
+	if err != nil {
+		return nil, fmt.Errorf("replay %s: %w", cfg.File, err)
+	}
+	return NewReplay(entries, cfg.Speed), nil
+}
+
+// NewReplay returns a transport that delivers the RX chunks of entries
+// with their recorded spacing, scaled by speed, and then reports io.EOF.
+// Writes are accepted and discarded, so the client under test runs its
+// normal command loop against the recorded replies.
+func NewReplay(entries []CaptureEntry, speed float64) Transport {
+	if speed <= 0 {
+		speed = 1
+	}
+	t := &replayTransport{
+		chunks: make(chan []byte),
+		done:   make(chan struct{}),
+	}
+	go t.play(entries, speed)
+	return t
+}
+
+type replayTransport struct {
+	chunks  chan []byte
+	pending []byte
+	done    chan struct{}
+	once    sync.Once
+}
+
+func (t *replayTransport) play(entries []CaptureEntry, speed float64) {
+	defer close(t.chunks)
+	start := time.Now()
+	var base time.Time
+	for _, entry := range entries {
+		if entry.Dir != DirRX || len(entry.Data) == 0 {
+			continue
+		}
+		if base.IsZero() {
+			base = entry.Time
+		}
+		due := start.Add(time.Duration(float64(entry.Time.Sub(base)) / speed))
+		if wait := time.Until(due); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-t.done:
+				timer.Stop()
+				return
+			}
+		}
+		select {
+		case t.chunks <- entry.Data:
+		case <-t.done:
+			return
+		}
+	}
+}
+
+func (t *replayTransport) Read(p []byte) (int, error) {
+	if len(t.pending) == 0 {
+		select {
+		case data, ok := <-t.chunks:
+			if !ok {
+				return 0, io.EOF
+			}
+			t.pending = data
+		case <-t.done:
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
+}
+
+func (t *replayTransport) Write(p []byte) (int, error) {
+	select {
+	case <-t.done:
+		return 0, io.ErrClosedPipe
+	default:
+		return len(p), nil
+	}
+}
+
+func (t *replayTransport) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+func (t *replayTransport) Close() error {
+	t.once.Do(func() { close(t.done) })
+	return nil
+}
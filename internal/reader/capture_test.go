@@ -0,0 +1,142 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+func readPacket(t *testing.T, c *Client) []byte {
+	t.Helper()
+	select {
+	case p, ok := <-c.Packets():
+		if !ok {
+			t.Fatal("packets channel closed")
+		}
+		return p.Data
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for packet")
+	}
+	return nil
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	probe := reader18.GetReaderInfoCommand(0x00)
+	reply := reader18.BuildCommand(0x00, reader18.CmdGetReaderInfo, []byte{0x00, 0x02, 0x03})
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 64)
+		if _, err := conn.Read(buf); err != nil {
+			return
+		}
+		_, _ = conn.Write(reply)
+		time.Sleep(200 * time.Millisecond)
+	}()
+
+	path := filepath.Join(t.TempDir(), "capture.jsonl")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := NewRecorder(f)
+
+	addr := ln.Addr().(*net.TCPAddr)
+	c := NewClient()
+	c.SetRecorder(rec)
+	endpoint := Endpoint{Host: addr.IP.String(), Port: addr.Port}
+	if err := c.Connect(context.Background(), endpoint, time.Second); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if err := c.SendRaw(probe, time.Second); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if got := readPacket(t, c); !bytes.Equal(got, reply) {
+		t.Fatalf("live reply % X", got)
+	}
+	_ = c.Disconnect()
+	_ = f.Close()
+	if err := rec.Err(); err != nil {
+		t.Fatalf("recorder: %v", err)
+	}
+
+	entries, err := LoadCapture(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if entries[0].Dir != DirOpen || entries[0].Endpoint != net.JoinHostPort(addr.IP.String(), strconv.Itoa(addr.Port)) {
+		t.Fatalf("open entry = %+v", entries[0])
+	}
+	if entries[1].Dir != DirTX || !bytes.Equal(entries[1].Data, probe) {
+		t.Fatalf("tx entry = %+v", entries[1])
+	}
+	if entries[2].Dir != DirRX || !bytes.Equal(entries[2].Data, reply) || entries[2].Time.Before(entries[1].Time) {
+		t.Fatalf("rx entry = %+v", entries[2])
+	}
+
+	replay := NewClient()
+	if err := replay.Connect(context.Background(), Endpoint{Replay: ReplayConfig{File: path, Speed: 100}}, time.Second); err != nil {
+		t.Fatalf("replay connect: %v", err)
+	}
+	errs := replay.Errors()
+	if err := replay.SendRaw(probe, time.Second); err != nil {
+		t.Fatalf("replay send: %v", err)
+	}
+	if got := readPacket(t, replay); !bytes.Equal(got, reply) {
+		t.Fatalf("replayed % X, want % X", got, reply)
+	}
+	select {
+	case err := <-errs:
+		if err != io.EOF {
+			t.Fatalf("end of replay: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("replay did not end")
+	}
+}
+
+func TestReplayKeepsRecordedSpacing(t *testing.T) {
+	start := time.Now()
+	entries := []CaptureEntry{
+		{Time: start, Dir: DirRX, Data: []byte{0x01}},
+		{Time: start.Add(50 * time.Millisecond), Dir: DirTX, Data: []byte{0x09}},
+		{Time: start.Add(200 * time.Millisecond), Dir: DirRX, Data: []byte{0x02}},
+	}
+	tr := NewReplay(entries, 2)
+	defer tr.Close()
+
+	buf := make([]byte, 8)
+	begin := time.Now()
+	if n, err := tr.Read(buf); err != nil || !bytes.Equal(buf[:n], []byte{0x01}) {
+		t.Fatalf("first read % X, %v", buf[:n], err)
+	}
+	if n, err := tr.Read(buf); err != nil || !bytes.Equal(buf[:n], []byte{0x02}) {
+		t.Fatalf("second read % X, %v", buf[:n], err)
+	}
+	if elapsed := time.Since(begin); elapsed < 80*time.Millisecond {
+		t.Fatalf("second chunk after %s, want ~100ms at 2x", elapsed)
+	}
+	if _, err := tr.Read(buf); err != io.EOF {
+		t.Fatalf("want EOF, got %v", err)
+	}
+}
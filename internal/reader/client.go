@@ -6,16 +6,37 @@ import (
 	"net"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// Endpoint describes a reachable reader address.
+// Endpoint describes a reachable reader address: a TCP host/port, a
+// serial line when Serial.Device is set, or a recorded session when
+// Replay.File is set.
 type Endpoint struct {
-	Host string
-	Port int
+	Host   string
+	Port   int
+	Serial SerialConfig
+	Replay ReplayConfig
+}
+
+// IsReplay reports whether the endpoint plays back a capture file.
+func (e Endpoint) IsReplay() bool {
+	return e.Replay.File != ""
+}
+
+// IsSerial reports whether the endpoint is a serial line.
+func (e Endpoint) IsSerial() bool {
+	return e.Serial.Device != ""
 }
 
 func (e Endpoint) Address() string {
+	if e.IsReplay() {
+		return "replay:" + e.Replay.File
+	}
+	if e.IsSerial() {
+		return e.Serial.String()
+	}
 	return net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
 }
 
@@ -27,16 +48,22 @@ type Packet struct {
 
 type session struct {
 	endpoint Endpoint
-	conn     net.Conn
+	conn     Transport
 	packets  chan Packet
 	errs     chan error
 	done     chan struct{}
+	// closing releases a readLoop blocked on a full packet channel.
+	closing   chan struct{}
+	closeOnce sync.Once
 }
 
-// Client manages a single reader TCP session.
+// Client manages a single reader session over TCP or a serial line.
 type Client struct {
-	mu      sync.RWMutex
-	session *session
+	mu       sync.RWMutex
+	session  *session
+	recorder *Recorder
+	policy   OverflowPolicy
+	dropped  atomic.Uint64
 }
 
 func NewClient() *Client {
@@ -44,7 +71,7 @@ func NewClient() *Client {
 }
 
 func (c *Client) Connect(ctx context.Context, endpoint Endpoint, timeout time.Duration) error {
-	if endpoint.Host == "" || endpoint.Port <= 0 {
+	if !endpoint.IsSerial() && !endpoint.IsReplay() && (endpoint.Host == "" || endpoint.Port <= 0) {
 		return fmt.Errorf("invalid endpoint")
 	}
 
@@ -55,11 +82,15 @@ func (c *Client) Connect(ctx context.Context, endpoint Endpoint, timeout time.Du
 	}
 	c.mu.Unlock()
 
-	dialer := net.Dialer{Timeout: timeout}
-	conn, err := dialer.DialContext(ctx, "tcp", endpoint.Address())
+	conn, err := open(ctx, endpoint, timeout)
 	if err != nil {
 		return err
 	}
+	c.mu.RLock()
+	if c.recorder != nil {
+		conn = c.recorder.Wrap(conn, endpoint.Address())
+	}
+	c.mu.RUnlock()
 
 	s := &session{
 		endpoint: endpoint,
@@ -67,6 +98,7 @@ func (c *Client) Connect(ctx context.Context, endpoint Endpoint, timeout time.Du
 		packets:  make(chan Packet, 256),
 		errs:     make(chan error, 32),
 		done:     make(chan struct{}),
+		closing:  make(chan struct{}),
 	}
 
 	c.mu.Lock()
@@ -82,6 +114,37 @@ func (c *Client) Connect(ctx context.Context, endpoint Endpoint, timeout time.Du
 	return nil
 }
 
+// SetRecorder records the TX/RX traffic of every later session; nil stops
+// recording.
+func (c *Client) SetRecorder(r *Recorder) {
+	c.mu.Lock()
+	c.recorder = r
+	c.mu.Unlock()
+}
+
+// SetOverflowPolicy sets what the read loop does when Packets is full.
+func (c *Client) SetOverflowPolicy(p OverflowPolicy) {
+	c.mu.Lock()
+	c.policy = p
+	c.mu.Unlock()
+}
+
+// Dropped counts packets lost to a full Packets channel.
+func (c *Client) Dropped() uint64 {
+	return c.dropped.Load()
+}
+
+func open(ctx context.Context, endpoint Endpoint, timeout time.Duration) (Transport, error) {
+	if endpoint.IsReplay() {
+		return OpenReplay(endpoint.Replay)
+	}
+	if endpoint.IsSerial() {
+		return attachSerial(endpoint.Serial)
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	return dialer.DialContext(ctx, "tcp", endpoint.Address())
+}
+
 func (c *Client) readLoop(s *session) {
 	defer func() {
 		close(s.packets)
@@ -112,9 +175,11 @@ func (c *Client) readLoop(s *session) {
 		data := make([]byte, n)
 		copy(data, buf[:n])
 		packet := Packet{When: time.Now(), Data: data}
-		select {
-		case s.packets <- packet:
-		default:
+		c.mu.RLock()
+		policy := c.policy
+		c.mu.RUnlock()
+		if !Offer(s.packets, packet, policy, s.closing) {
+			c.dropped.Add(1)
 		}
 	}
 }
@@ -127,6 +192,7 @@ func (c *Client) Disconnect() error {
 		return nil
 	}
 
+	s.closeOnce.Do(func() { close(s.closing) })
 	if err := s.conn.Close(); err != nil {
 		return err
 	}
@@ -0,0 +1,77 @@
+package reader
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OverflowPolicy decides what happens when an event channel is full.
+type OverflowPolicy int
+
+const (
+	// OverflowDropNewest discards the event that does not fit. Default.
+	OverflowDropNewest OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest queued event to make room.
+	OverflowDropOldest
+	// OverflowBlock waits for the consumer, pushing back on the producer.
+	OverflowBlock
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowDropOldest:
+		return "drop-oldest"
+	case OverflowBlock:
+		return "block"
+	default:
+		return "drop-newest"
+	}
+}
+
+// ParseOverflowPolicy reads "block", "drop-oldest" or "drop-newest"; an
+// empty string is drop-newest.
+func ParseOverflowPolicy(raw string) (OverflowPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "drop-newest":
+		return OverflowDropNewest, nil
+	case "drop-oldest":
+		return OverflowDropOldest, nil
+	case "block":
+		return OverflowBlock, nil
+	}
+	return OverflowDropNewest, fmt.Errorf("unknown overflow policy %q (block|drop-oldest|drop-newest)", raw)
+}
+
+// Offer puts v on ch according to policy and reports whether v was queued
+// without losing anything. OverflowBlock gives up when stop is closed; an
+// event dropped to make room (drop-oldest) also counts as a loss.
+func Offer[T any](ch chan T, v T, policy OverflowPolicy, stop <-chan struct{}) bool {
+	select {
+	case ch <- v:
+		return true
+	default:
+	}
+	switch policy {
+	case OverflowBlock:
+		select {
+		case ch <- v:
+			return true
+		case <-stop:
+			return false
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- v:
+				return false
+			default:
+			}
+		}
+	default:
+		return false
+	}
+}
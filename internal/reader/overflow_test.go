@@ -0,0 +1,119 @@
+package reader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOfferPolicies(t *testing.T) {
+	ch := make(chan int, 2)
+	ch <- 1
+	ch <- 2
+	if Offer(ch, 3, OverflowDropNewest, nil) {
+		t.Fatal("drop-newest reported success on a full channel")
+	}
+	if got := <-ch; got != 1 {
+		t.Fatalf("drop-newest head = %d", got)
+	}
+	ch <- 3
+	if Offer(ch, 4, OverflowDropOldest, nil) {
+		t.Fatal("drop-oldest should report the lost event")
+	}
+	if a, b := <-ch, <-ch; a != 3 || b != 4 {
+		t.Fatalf("drop-oldest kept %d,%d", a, b)
+	}
+
+	ch <- 5
+	ch <- 6
+	stop := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		<-ch
+	}()
+	if !Offer(ch, 7, OverflowBlock, stop) {
+		t.Fatal("block should wait for room")
+	}
+	close(stop)
+	if Offer(ch, 8, OverflowBlock, stop) {
+		t.Fatal("block should give up once stopped")
+	}
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	for raw, want := range map[string]OverflowPolicy{"": OverflowDropNewest, "BLOCK": OverflowBlock, "drop-oldest": OverflowDropOldest} {
+		got, err := ParseOverflowPolicy(raw)
+		if err != nil || got != want {
+			t.Fatalf("ParseOverflowPolicy(%q) = %s, %v", raw, got, err)
+		}
+	}
+	if _, err := ParseOverflowPolicy("spill"); err == nil {
+		t.Fatal("expected error for unknown policy")
+	}
+}
+
+// writeBurst records n single-byte rx chunks with the same timestamp so the
+// replay delivers them faster than anyone reads.
+func writeBurst(t *testing.T, n int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "burst.jsonl")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := NewRecorder(f)
+	now := time.Now()
+	for i := 0; i < n; i++ {
+		rec.record(CaptureEntry{Time: now, Dir: DirRX, Data: []byte{byte(i)}})
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func connectReplay(t *testing.T, c *Client, path string) {
+	t.Helper()
+	endpoint := Endpoint{Replay: ReplayConfig{File: path}}
+	if err := c.Connect(context.Background(), endpoint, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Disconnect() })
+}
+
+func TestClientOverflowPolicies(t *testing.T) {
+	const n = 400
+	path := writeBurst(t, n)
+
+	lossy := NewClient()
+	connectReplay(t, lossy, path)
+	packets := lossy.Packets()
+	time.Sleep(100 * time.Millisecond)
+	if lossy.Dropped() == 0 {
+		t.Fatal("drop-newest should drop part of an unread burst")
+	}
+	if got := <-packets; got.Data[0] != 0 {
+		t.Fatalf("drop-newest should keep the first chunk, got %d", got.Data[0])
+	}
+
+	lossless := NewClient()
+	lossless.SetOverflowPolicy(OverflowBlock)
+	connectReplay(t, lossless, path)
+	packets = lossless.Packets()
+	time.Sleep(100 * time.Millisecond)
+	for i := 0; i < n; i++ {
+		select {
+		case p := <-packets:
+			if p.Data[0] != byte(i) {
+				t.Fatalf("chunk %d out of order: %d", i, p.Data[0])
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("only %d of %d chunks delivered", i, n)
+		}
+	}
+	if lossless.Dropped() != 0 {
+		t.Fatalf("block dropped %d packets", lossless.Dropped())
+	}
+}
@@ -0,0 +1,99 @@
+package reader
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultBaud is the factory UART speed of Reader18 modules.
+const DefaultBaud = 57600
+
+// BroadcastAddress makes a serial Client receive every frame on the line,
+// which is what a single reader on a point-to-point link needs.
+const BroadcastAddress byte = 0xFF
+
+// SerialConfig describes a UART, USB-serial or RS-485 line.
+type SerialConfig struct {
+	Device   string
+	Baud     int
+	DataBits int
+	// Parity is 'N', 'E' or 'O'.
+	Parity   byte
+	StopBits int
+	// Address routes replies on an RS-485 multidrop bus: a Client only
+	// receives frames from this reader address. BroadcastAddress receives all.
+	Address byte
+}
+
+// ParseSerial reads "/dev/ttyUSB0", "/dev/ttyUSB0@115200" or
+// "/dev/ttyUSB0@57600,8E1". The address defaults to BroadcastAddress.
+func ParseSerial(spec string) (SerialConfig, error) {
+	spec = strings.TrimSpace(spec)
+	cfg := SerialConfig{Address: BroadcastAddress}
+	device, line, _ := strings.Cut(spec, "@")
+	cfg.Device = strings.TrimSpace(device)
+	if cfg.Device == "" {
+		return SerialConfig{}, fmt.Errorf("serial device is required")
+	}
+	if line == "" {
+		return cfg.withDefaults(), nil
+	}
+	baud, frame, _ := strings.Cut(line, ",")
+	n, err := strconv.Atoi(strings.TrimSpace(baud))
+	if err != nil || n <= 0 {
+		return SerialConfig{}, fmt.Errorf("invalid baud rate %q", baud)
+	}
+	cfg.Baud = n
+	if frame = strings.ToUpper(strings.TrimSpace(frame)); frame != "" {
+		if len(frame) != 3 || frame[0] < '5' || frame[0] > '8' || frame[2] < '1' || frame[2] > '2' {
+			return SerialConfig{}, fmt.Errorf("invalid frame format %q (want e.g. 8N1)", frame)
+		}
+		cfg.DataBits = int(frame[0] - '0')
+		cfg.Parity = frame[1]
+		cfg.StopBits = int(frame[2] - '0')
+	}
+	cfg = cfg.withDefaults()
+	return cfg, cfg.validate()
+}
+
+func (s SerialConfig) withDefaults() SerialConfig {
+	if s.Baud == 0 {
+		s.Baud = DefaultBaud
+	}
+	if s.DataBits == 0 {
+		s.DataBits = 8
+	}
+	if s.Parity == 0 {
+		s.Parity = 'N'
+	}
+	if s.StopBits == 0 {
+		s.StopBits = 1
+	}
+	return s
+}
+
+func (s SerialConfig) validate() error {
+	if _, ok := baudRates[s.Baud]; !ok {
+		return fmt.Errorf("unsupported baud rate %d", s.Baud)
+	}
+	switch s.Parity {
+	case 'N', 'E', 'O':
+	default:
+		return fmt.Errorf("invalid parity %q", s.Parity)
+	}
+	if s.DataBits < 5 || s.DataBits > 8 || s.StopBits < 1 || s.StopBits > 2 {
+		return fmt.Errorf("invalid frame format %d%c%d", s.DataBits, s.Parity, s.StopBits)
+	}
+	return nil
+}
+
+// sameLine reports whether two configs can share one open port.
+func (s SerialConfig) sameLine(o SerialConfig) bool {
+	return s.Baud == o.Baud && s.DataBits == o.DataBits && s.Parity == o.Parity && s.StopBits == o.StopBits
+}
+
+func (s SerialConfig) String() string {
+	s = s.withDefaults()
+	return fmt.Sprintf("%s@%d,%d%c%d", s.Device, s.Baud, s.DataBits, s.Parity, s.StopBits)
+}
@@ -0,0 +1,232 @@
+//go:build linux
+
+package reader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+// openPTY returns the master side and the slave device path of a new
+// pseudo-terminal, which stands in for a USB-serial adapter. The master is
+// non-blocking so read deadlines on it work.
+func openPTY(t *testing.T) (*os.File, string) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY|unix.O_NONBLOCK, 0)
+	if err != nil {
+		t.Skipf("pty unavailable: %v", err)
+	}
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		master.Close()
+		t.Skipf("unlock pty: %v", err)
+	}
+	n, err := unix.IoctlGetUint32(fd, unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		t.Skipf("pty number: %v", err)
+	}
+	// Raw master so frame bytes are not translated on the way through.
+	if termios, err := unix.IoctlGetTermios(fd, unix.TCGETS); err == nil {
+		termios.Iflag &^= unix.ICRNL | unix.IXON
+		termios.Oflag &^= unix.OPOST
+		termios.Lflag &^= unix.ECHO | unix.ICANON | unix.ISIG | unix.IEXTEN
+		_ = unix.IoctlSetTermios(fd, unix.TCSETS, termios)
+	}
+	t.Cleanup(func() { master.Close() })
+	return master, fmt.Sprintf("/dev/pts/%d", n)
+}
+
+func TestSerialBusRoutesByAddress(t *testing.T) {
+	master, device := openPTY(t)
+
+	clients := make(map[byte]*Client)
+	for _, addr := range []byte{0x01, 0x02} {
+		c := NewClient()
+		endpoint := Endpoint{Serial: SerialConfig{Device: device, Baud: 115200, Address: addr}}
+		if err := c.Connect(context.Background(), endpoint, time.Second); err != nil {
+			t.Fatalf("connect 0x%02X: %v", addr, err)
+		}
+		defer c.Disconnect()
+		clients[addr] = c
+	}
+
+	dup := NewClient()
+	if err := dup.Connect(context.Background(), Endpoint{Serial: SerialConfig{Device: device, Baud: 115200, Address: 0x01}}, time.Second); err == nil {
+		dup.Disconnect()
+		t.Fatal("second client on the same address should be rejected")
+	}
+	if err := dup.Connect(context.Background(), Endpoint{Serial: SerialConfig{Device: device, Baud: 9600, Address: 0x03}}, time.Second); err == nil {
+		dup.Disconnect()
+		t.Fatal("different line settings on an open port should be rejected")
+	}
+
+	// Replies from both readers arrive interleaved on one line.
+	reply1 := reader18.BuildCommand(0x01, reader18.CmdGetReaderInfo, []byte{0x00, 0x11})
+	reply2 := reader18.BuildCommand(0x02, reader18.CmdGetReaderInfo, []byte{0x00, 0x22})
+	if _, err := master.Write(append(append([]byte{}, reply2...), reply1...)); err != nil {
+		t.Fatalf("write master: %v", err)
+	}
+	if got := readPacket(t, clients[0x01]); !bytes.Equal(got, reply1) {
+		t.Fatalf("client 0x01 got % X, want % X", got, reply1)
+	}
+	if got := readPacket(t, clients[0x02]); !bytes.Equal(got, reply2) {
+		t.Fatalf("client 0x02 got % X, want % X", got, reply2)
+	}
+
+	cmd := reader18.GetReaderInfoCommand(0x02)
+	if err := clients[0x02].SendRaw(cmd, time.Second); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	buf := make([]byte, 64)
+	_ = master.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := master.Read(buf)
+	if err != nil {
+		t.Fatalf("read master: %v", err)
+	}
+	if !bytes.Equal(buf[:n], cmd) {
+		t.Fatalf("master got % X, want % X", buf[:n], cmd)
+	}
+	// The reply ends the turn, so the next send does not wait it out.
+	if _, err := master.Write(reply2); err != nil {
+		t.Fatalf("write master: %v", err)
+	}
+	readPacket(t, clients[0x02])
+
+	// The port stays open until the last client leaves.
+	clients[0x01].Disconnect()
+	if err := clients[0x02].SendRaw(cmd, time.Second); err != nil {
+		t.Fatalf("send after peer disconnect: %v", err)
+	}
+}
+
+func TestSerialBusAttachDuringLastDetach(t *testing.T) {
+	_, device := openPTY(t)
+
+	// One client keeps leaving while another joins; a join must never land
+	// on the bus being closed by the leave.
+	errs := make(chan error, 2)
+	for _, addr := range []byte{0x01, 0x02} {
+		go func(addr byte) {
+			for i := 0; i < 50; i++ {
+				c := NewClient()
+				endpoint := Endpoint{Serial: SerialConfig{Device: device, Baud: 115200, Address: addr}}
+				if err := c.Connect(context.Background(), endpoint, time.Second); err != nil {
+					errs <- fmt.Errorf("connect 0x%02X: %w", addr, err)
+					return
+				}
+				err := c.SendRaw(reader18.GetReaderInfoCommand(addr), time.Second)
+				c.Disconnect()
+				if err != nil {
+					errs <- fmt.Errorf("send 0x%02X round %d: %w", addr, i, err)
+					return
+				}
+			}
+			errs <- nil
+		}(addr)
+	}
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buses.Lock()
+	defer buses.Unlock()
+	if bus := buses.m[device]; bus != nil {
+		t.Fatalf("bus still registered after every client left")
+	}
+}
+
+// TestSerialBusArbitratesConcurrentPolls polls two addresses at once. A
+// fake line answers each inventory with a "more frames" reply, then a final
+// one; a command arriving before the final reply would have collided on a
+// real RS-485 line.
+func TestSerialBusArbitratesConcurrentPolls(t *testing.T) {
+	master, device := openPTY(t)
+
+	const rounds = 10
+	lineErr := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 256)
+		for served := 0; served < 2*rounds; served++ {
+			_ = master.SetReadDeadline(time.Now().Add(5 * time.Second))
+			n, err := master.Read(buf)
+			if err != nil {
+				lineErr <- fmt.Errorf("read command %d: %w", served, err)
+				return
+			}
+			frames, _ := reader18.ParseFrames(buf[:n])
+			if len(frames) != 1 {
+				lineErr <- fmt.Errorf("commands collided on the line: % X", buf[:n])
+				return
+			}
+			addr := frames[0].Address
+			more := reader18.BuildCommand(addr, reader18.CmdInventory, []byte{inventoryMoreFrames, 0x01, 0x00})
+			if _, err := master.Write(more); err != nil {
+				lineErr <- err
+				return
+			}
+			// Nothing may be sent while the reader is still answering.
+			_ = master.SetReadDeadline(time.Now().Add(30 * time.Millisecond))
+			if n, err := master.Read(buf); err == nil {
+				lineErr <- fmt.Errorf("command sent during 0x%02X's reply: % X", addr, buf[:n])
+				return
+			}
+			final := reader18.BuildCommand(addr, reader18.CmdInventory, []byte{reader18.StatusNoTag, 0x01, 0x00})
+			if _, err := master.Write(final); err != nil {
+				lineErr <- err
+				return
+			}
+		}
+		lineErr <- nil
+	}()
+
+	errs := make(chan error, 2)
+	for _, addr := range []byte{0x01, 0x02} {
+		c := NewClient()
+		endpoint := Endpoint{Serial: SerialConfig{Device: device, Baud: 115200, Address: addr}}
+		if err := c.Connect(context.Background(), endpoint, time.Second); err != nil {
+			t.Fatalf("connect 0x%02X: %v", addr, err)
+		}
+		defer c.Disconnect()
+		go func(addr byte, c *Client) {
+			cmd := reader18.InventoryG2Command(addr, 4, 0, 0, 0, 0, 0x80, 1)
+			for i := 0; i < rounds; i++ {
+				if err := c.SendRaw(cmd, time.Second); err != nil {
+					errs <- fmt.Errorf("send 0x%02X round %d: %w", addr, i, err)
+					return
+				}
+				for final := false; !final; {
+					select {
+					case p := <-c.Packets():
+						frames, _ := reader18.ParseFrames(p.Data)
+						for _, f := range frames {
+							final = final || f.Status != inventoryMoreFrames
+						}
+					case <-time.After(3 * time.Second):
+						errs <- fmt.Errorf("0x%02X round %d: no final reply", addr, i)
+						return
+					}
+				}
+			}
+			errs <- nil
+		}(addr, c)
+	}
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := <-lineErr; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package reader
+
+import "fmt"
+
+var baudRates = map[int]uint32{
+	1200: 0, 2400: 0, 4800: 0, 9600: 0, 19200: 0, 38400: 0, 57600: 0, 115200: 0, 230400: 0,
+}
+
+func openSerial(cfg SerialConfig) (Transport, error) {
+	return nil, fmt.Errorf("serial transport is only supported on linux")
+}
@@ -0,0 +1,52 @@
+package reader
+
+import "testing"
+
+func TestParseSerial(t *testing.T) {
+	cases := []struct {
+		spec string
+		want string
+		err  bool
+	}{
+		{spec: "/dev/ttyUSB0", want: "/dev/ttyUSB0@57600,8N1"},
+		{spec: "/dev/ttyUSB0@115200", want: "/dev/ttyUSB0@115200,8N1"},
+		{spec: " /dev/ttyS1@9600,7e2 ", want: "/dev/ttyS1@9600,7E2"},
+		{spec: "", err: true},
+		{spec: "/dev/ttyUSB0@fast", err: true},
+		{spec: "/dev/ttyUSB0@12345", err: true},
+		{spec: "/dev/ttyUSB0@57600,8X1", err: true},
+		{spec: "/dev/ttyUSB0@57600,9N1", err: true},
+	}
+	for _, tc := range cases {
+		cfg, err := ParseSerial(tc.spec)
+		if tc.err {
+			if err == nil {
+				t.Errorf("ParseSerial(%q) = %s, want error", tc.spec, cfg)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSerial(%q): %v", tc.spec, err)
+			continue
+		}
+		if got := cfg.String(); got != tc.want {
+			t.Errorf("ParseSerial(%q) = %s, want %s", tc.spec, got, tc.want)
+		}
+		if cfg.Address != BroadcastAddress {
+			t.Errorf("ParseSerial(%q) address = 0x%02X, want broadcast", tc.spec, cfg.Address)
+		}
+	}
+}
+
+func TestSerialEndpointAddress(t *testing.T) {
+	endpoint := Endpoint{Host: "ignored", Port: 1, Serial: SerialConfig{Device: "/dev/ttyUSB1"}}
+	if !endpoint.IsSerial() {
+		t.Fatal("expected serial endpoint")
+	}
+	if got := endpoint.Address(); got != "/dev/ttyUSB1@57600,8N1" {
+		t.Fatalf("address = %s", got)
+	}
+	if got := (Endpoint{Host: "10.0.0.5", Port: 6000}).Address(); got != "10.0.0.5:6000" {
+		t.Fatalf("tcp address = %s", got)
+	}
+}
@@ -0,0 +1,13 @@
+package reader
+
+import (
+	"io"
+	"time"
+)
+
+// Transport is an open byte stream to a reader: a TCP socket, a serial line
+// or one address on a shared RS-485 bus. net.Conn satisfies it.
+type Transport interface {
+	io.ReadWriteCloser
+	SetWriteDeadline(t time.Time) error
+}
@@ -1,29 +1,48 @@
 package regions
 
+import (
+	"fmt"
+	"strings"
+)
+
+// Reader18 frequency band codes for command 0x22.
+const (
+	BandUser    = 0
+	BandCN2     = 1 // 920.125 MHz + N*0.25
+	BandUS      = 2 // 902.75 MHz + N*0.5
+	BandKR      = 3 // 917.1 MHz + N*0.2
+	BandEU      = 4 // 865.1 MHz + N*0.2
+	BandCN1     = 8 // 840.125 MHz + N*0.25
+	channelMask = 0x3F
+)
+
 // Region represents one UHF regulatory preset.
 type Region struct {
-	Code string
-	Name string
-	Band string
+	Code       string
+	Name       string
+	Band       string
+	BandCode   int
+	MinChannel int
+	MaxChannel int
 }
 
 var Catalog = []Region{
-	{Code: "US", Name: "United States", Band: "902-928 MHz"},
-	{Code: "EU", Name: "Europe", Band: "865-868 MHz"},
-	{Code: "CN840", Name: "China 840", Band: "840-845 MHz"},
-	{Code: "CN920", Name: "China 920", Band: "920-925 MHz"},
-	{Code: "JP", Name: "Japan", Band: "916.8-923.4 MHz"},
-	{Code: "KR", Name: "Korea", Band: "917-923.5 MHz"},
-	{Code: "IN", Name: "India", Band: "865-867 MHz"},
-	{Code: "AU", Name: "Australia", Band: "920-926 MHz"},
-	{Code: "NZ", Name: "New Zealand", Band: "922-928 MHz"},
-	{Code: "RU", Name: "Russia", Band: "866-868 MHz"},
-	{Code: "BR", Name: "Brazil", Band: "902-907.5 MHz"},
-	{Code: "ZA", Name: "South Africa", Band: "915-919 MHz"},
-	{Code: "SG", Name: "Singapore", Band: "920-925 MHz"},
-	{Code: "MY", Name: "Malaysia", Band: "919-923 MHz"},
-	{Code: "TH", Name: "Thailand", Band: "920-925 MHz"},
-	{Code: "VN", Name: "Vietnam", Band: "920-923 MHz"},
+	{Code: "US", Name: "United States", Band: "902-928 MHz", BandCode: BandUS, MinChannel: 0, MaxChannel: 49},
+	{Code: "EU", Name: "Europe", Band: "865-868 MHz", BandCode: BandEU, MinChannel: 0, MaxChannel: 14},
+	{Code: "CN840", Name: "China 840", Band: "840-845 MHz", BandCode: BandCN1, MinChannel: 0, MaxChannel: 19},
+	{Code: "CN920", Name: "China 920", Band: "920-925 MHz", BandCode: BandCN2, MinChannel: 0, MaxChannel: 19},
+	{Code: "JP", Name: "Japan", Band: "916.8-923.4 MHz", BandCode: BandKR, MinChannel: 0, MaxChannel: 31},
+	{Code: "KR", Name: "Korea", Band: "917-923.5 MHz", BandCode: BandKR, MinChannel: 0, MaxChannel: 31},
+	{Code: "IN", Name: "India", Band: "865-867 MHz", BandCode: BandEU, MinChannel: 0, MaxChannel: 9},
+	{Code: "AU", Name: "Australia", Band: "920-926 MHz", BandCode: BandUS, MinChannel: 35, MaxChannel: 46},
+	{Code: "NZ", Name: "New Zealand", Band: "922-928 MHz", BandCode: BandUS, MinChannel: 39, MaxChannel: 49},
+	{Code: "RU", Name: "Russia", Band: "866-868 MHz", BandCode: BandEU, MinChannel: 5, MaxChannel: 14},
+	{Code: "BR", Name: "Brazil", Band: "902-907.5 MHz", BandCode: BandUS, MinChannel: 0, MaxChannel: 9},
+	{Code: "ZA", Name: "South Africa", Band: "915-919 MHz", BandCode: BandUS, MinChannel: 25, MaxChannel: 32},
+	{Code: "SG", Name: "Singapore", Band: "920-925 MHz", BandCode: BandUS, MinChannel: 35, MaxChannel: 44},
+	{Code: "MY", Name: "Malaysia", Band: "919-923 MHz", BandCode: BandUS, MinChannel: 33, MaxChannel: 40},
+	{Code: "TH", Name: "Thailand", Band: "920-925 MHz", BandCode: BandUS, MinChannel: 35, MaxChannel: 44},
+	{Code: "VN", Name: "Vietnam", Band: "920-923 MHz", BandCode: BandUS, MinChannel: 35, MaxChannel: 40},
 }
 
 func DefaultIndex() int {
@@ -34,3 +53,119 @@ func DefaultIndex() int {
 	}
 	return 0
 }
+
+// Lookup finds a region by code, case-insensitively.
+func Lookup(code string) (Region, bool) {
+	code = strings.TrimSpace(code)
+	for _, region := range Catalog {
+		if strings.EqualFold(region.Code, code) {
+			return region, true
+		}
+	}
+	return Region{}, false
+}
+
+// Index returns the catalogue position of code, or -1.
+func Index(code string) int {
+	for i, region := range Catalog {
+		if strings.EqualFold(region.Code, strings.TrimSpace(code)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Encode returns the 0x22 SetRegion high/low bytes for a region code.
+func Encode(code string) (high, low byte, err error) {
+	region, ok := Lookup(code)
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown region: %q", code)
+	}
+	high, low = region.Encode()
+	return high, low, nil
+}
+
+// Encode packs band and channel range:
+// high = band[3:2]<<6 | max, low = band[1:0]<<6 | min.
+func (r Region) Encode() (high, low byte) {
+	return EncodeRange(r.BandCode, r.MinChannel, r.MaxChannel)
+}
+
+// EncodeRange packs an arbitrary band/channel range into 0x22 bytes.
+func EncodeRange(band, minChannel, maxChannel int) (high, low byte) {
+	high = byte(((band & 0x0C) << 4) | (maxChannel & channelMask))
+	low = byte(((band & 0x03) << 6) | (minChannel & channelMask))
+	return high, low
+}
+
+// DecodeRange unpacks 0x22 bytes into band code and channel range.
+func DecodeRange(high, low byte) (band, minChannel, maxChannel int) {
+	band = int(high>>6)<<2 | int(low>>6)
+	return band, int(low & channelMask), int(high & channelMask)
+}
+
+// Decode matches 0x22 bytes against the catalogue. ok is false when the
+// range is not a preset, and also when several presets share the encoding
+// (JP and KR, SG and TH): the bytes alone cannot name the region, so the
+// result then carries only band and channels. Matches lists the candidates.
+func Decode(high, low byte) (Region, bool) {
+	band, minChannel, maxChannel := DecodeRange(high, low)
+	if found := Matches(high, low); len(found) == 1 {
+		return found[0], true
+	}
+	return Region{BandCode: band, MinChannel: minChannel, MaxChannel: maxChannel}, false
+}
+
+// Matches returns every preset whose encoding is high/low, in Catalog order.
+func Matches(high, low byte) []Region {
+	band, minChannel, maxChannel := DecodeRange(high, low)
+	var out []Region
+	for _, region := range Catalog {
+		if region.BandCode == band && region.MinChannel == minChannel && region.MaxChannel == maxChannel {
+			out = append(out, region)
+		}
+	}
+	return out
+}
+
+// Label names 0x22 bytes for display: a preset code, "JP/KR" when presets
+// share the encoding, or "band3:0-10" for other ranges.
+func Label(high, low byte) string {
+	found := Matches(high, low)
+	if len(found) == 0 {
+		band, minChannel, maxChannel := DecodeRange(high, low)
+		return fmt.Sprintf("band%d:%d-%d", band, minChannel, maxChannel)
+	}
+	codes := make([]string, len(found))
+	for i, region := range found {
+		codes[i] = region.Code
+	}
+	return strings.Join(codes, "/")
+}
+
+// ChannelMHz returns the centre frequency of one channel, or 0 for unknown bands.
+func ChannelMHz(band, channel int) float64 {
+	switch band {
+	case BandCN2:
+		return 920.125 + float64(channel)*0.25
+	case BandUS:
+		return 902.75 + float64(channel)*0.5
+	case BandKR:
+		return 917.1 + float64(channel)*0.2
+	case BandEU:
+		return 865.1 + float64(channel)*0.2
+	case BandCN1:
+		return 840.125 + float64(channel)*0.25
+	default:
+		return 0
+	}
+}
+
+// Channels describes the encoded range, e.g. "ch 0-49 (902.75-927.25 MHz)".
+func (r Region) Channels() string {
+	lo, hi := ChannelMHz(r.BandCode, r.MinChannel), ChannelMHz(r.BandCode, r.MaxChannel)
+	if lo == 0 {
+		return fmt.Sprintf("band %d ch %d-%d", r.BandCode, r.MinChannel, r.MaxChannel)
+	}
+	return fmt.Sprintf("ch %d-%d (%.2f-%.2f MHz)", r.MinChannel, r.MaxChannel, lo, hi)
+}
@@ -0,0 +1,57 @@
+package regions
+
+import "testing"
+
+func TestEncodeDecodeCatalogue(t *testing.T) {
+	high, low, err := Encode("us")
+	if err != nil {
+		t.Fatalf("encode US: %v", err)
+	}
+	if high != 0x31 || low != 0x80 {
+		t.Fatalf("unexpected US bytes: 0x%02X/0x%02X", high, low)
+	}
+	high, low, _ = Encode("CN840")
+	if high != 0x93 || low != 0x00 {
+		t.Fatalf("unexpected CN840 bytes: 0x%02X/0x%02X", high, low)
+	}
+
+	for _, region := range Catalog {
+		high, low := region.Encode()
+		got, ok := Decode(high, low)
+		if got.BandCode != region.BandCode || got.MinChannel != region.MinChannel || got.MaxChannel != region.MaxChannel {
+			t.Fatalf("%s: decode mismatch: %+v ok=%v", region.Code, got, ok)
+		}
+		matches := Matches(high, low)
+		found := false
+		for _, m := range matches {
+			found = found || m.Code == region.Code
+		}
+		if !found {
+			t.Fatalf("%s: not among matches %+v", region.Code, matches)
+		}
+		// Shared encodings must not decode to one of the presets.
+		if ok != (len(matches) == 1) || (ok && got.Code != region.Code) {
+			t.Fatalf("%s: decode ok=%v code=%q with %d matches", region.Code, ok, got.Code, len(matches))
+		}
+	}
+	if got := Label(EncodeRange(BandKR, 0, 31)); got != "JP/KR" {
+		t.Fatalf("shared encoding label: %q", got)
+	}
+	if got := Label(EncodeRange(BandUS, 35, 44)); got != "SG/TH" {
+		t.Fatalf("shared encoding label: %q", got)
+	}
+
+	if _, _, err := Encode("XX"); err == nil {
+		t.Fatalf("expected unknown region error")
+	}
+	if got, ok := Decode(EncodeRange(BandUS, 10, 20)); ok || got.BandCode != BandUS || got.MinChannel != 10 || got.MaxChannel != 20 {
+		t.Fatalf("unexpected custom range decode: %+v ok=%v", got, ok)
+	}
+}
+
+func TestChannelsDescribesFrequencies(t *testing.T) {
+	region, _ := Lookup("EU")
+	if got := region.Channels(); got != "ch 0-14 (865.10-867.90 MHz)" {
+		t.Fatalf("unexpected EU channels: %q", got)
+	}
+}
@@ -0,0 +1,332 @@
+// Package tds decodes and encodes GS1 EPC Tag Data Standard 96-bit schemes.
+package tds
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Scheme is an EPC binary coding scheme name as used in tag URIs.
+type Scheme string
+
+const (
+	SchemeSGTIN96 Scheme = "sgtin-96"
+	SchemeSSCC96  Scheme = "sscc-96"
+	SchemeGRAI96  Scheme = "grai-96"
+	SchemeGIAI96  Scheme = "giai-96"
+)
+
+const (
+	headerSGTIN96 = 0x30
+	headerSSCC96  = 0x31
+	headerGRAI96  = 0x33
+	headerGIAI96  = 0x34
+)
+
+// Identity is a decoded EPC. Reference holds the SGTIN item reference
+// (with indicator digit), SSCC serial reference (with extension digit),
+// GRAI asset type or GIAI individual asset reference. Serial is set for
+// SGTIN and GRAI only.
+type Identity struct {
+	Scheme        Scheme
+	Filter        int
+	CompanyPrefix string
+	Reference     string
+	Serial        string
+}
+
+// partition is one row of a TDS partition table.
+type partition struct {
+	companyBits, companyDigits int
+	refBits, refDigits         int
+}
+
+type layout struct {
+	header     byte
+	name       string // pure identity URI scheme
+	partitions [7]partition
+	serialBits int
+}
+
+var layouts = map[Scheme]layout{
+	SchemeSGTIN96: {
+		header: headerSGTIN96, name: "sgtin", serialBits: 38,
+		partitions: [7]partition{{40, 12, 4, 1}, {37, 11, 7, 2}, {34, 10, 10, 3}, {30, 9, 14, 4}, {27, 8, 17, 5}, {24, 7, 20, 6}, {20, 6, 24, 7}},
+	},
+	SchemeSSCC96: {
+		header: headerSSCC96, name: "sscc",
+		partitions: [7]partition{{40, 12, 18, 5}, {37, 11, 21, 6}, {34, 10, 24, 7}, {30, 9, 28, 8}, {27, 8, 31, 9}, {24, 7, 34, 10}, {20, 6, 38, 11}},
+	},
+	SchemeGRAI96: {
+		header: headerGRAI96, name: "grai", serialBits: 38,
+		partitions: [7]partition{{40, 12, 4, 0}, {37, 11, 7, 1}, {34, 10, 10, 2}, {30, 9, 14, 3}, {27, 8, 17, 4}, {24, 7, 20, 5}, {20, 6, 24, 6}},
+	},
+	SchemeGIAI96: {
+		header: headerGIAI96, name: "giai",
+		partitions: [7]partition{{40, 12, 42, 13}, {37, 11, 45, 14}, {34, 10, 48, 15}, {30, 9, 52, 16}, {27, 8, 55, 17}, {24, 7, 58, 18}, {20, 6, 62, 19}},
+	},
+}
+
+func schemeForHeader(header byte) (Scheme, bool) {
+	for scheme, l := range layouts {
+		if l.header == header {
+			return scheme, true
+		}
+	}
+	return "", false
+}
+
+// Decode converts a 96-bit EPC into an Identity.
+func Decode(epc []byte) (Identity, error) {
+	if len(epc) != 12 {
+		return Identity{}, fmt.Errorf("unsupported EPC length: %d bits", len(epc)*8)
+	}
+	scheme, ok := schemeForHeader(epc[0])
+	if !ok {
+		return Identity{}, fmt.Errorf("unsupported EPC header: 0x%02X", epc[0])
+	}
+	l := layouts[scheme]
+
+	filter := int(readBits(epc, 8, 3))
+	p := int(readBits(epc, 11, 3))
+	if p >= len(l.partitions) {
+		return Identity{}, fmt.Errorf("invalid partition value: %d", p)
+	}
+	part := l.partitions[p]
+	off := 14
+
+	company := readBits(epc, off, part.companyBits)
+	off += part.companyBits
+	ref := readBits(epc, off, part.refBits)
+	off += part.refBits
+
+	id := Identity{Scheme: scheme, Filter: filter}
+	if id.CompanyPrefix, ok = formatDigits(company, part.companyDigits); !ok {
+		return Identity{}, fmt.Errorf("company prefix exceeds %d digits", part.companyDigits)
+	}
+	if scheme == SchemeGIAI96 {
+		id.Reference = strconv.FormatUint(ref, 10)
+		if len(id.Reference) > part.refDigits {
+			return Identity{}, fmt.Errorf("asset reference exceeds %d digits", part.refDigits)
+		}
+		return id, nil
+	}
+	if id.Reference, ok = formatDigits(ref, part.refDigits); !ok {
+		return Identity{}, fmt.Errorf("reference exceeds %d digits", part.refDigits)
+	}
+	if l.serialBits > 0 {
+		id.Serial = strconv.FormatUint(readBits(epc, off, l.serialBits), 10)
+	} else if readBits(epc, off, 96-off) != 0 {
+		return Identity{}, fmt.Errorf("reserved bits are not zero")
+	}
+	return id, nil
+}
+
+// DecodeHex decodes an EPC given as hex (spaces allowed).
+func DecodeHex(epcHex string) (Identity, error) {
+	raw, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(epcHex), " ", ""))
+	if err != nil {
+		return Identity{}, fmt.Errorf("invalid EPC hex: %w", err)
+	}
+	return Decode(raw)
+}
+
+// Encode converts an Identity back into its 96-bit EPC.
+func Encode(id Identity) ([]byte, error) {
+	l, ok := layouts[id.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported scheme: %q", id.Scheme)
+	}
+	if id.Filter < 0 || id.Filter > 7 {
+		return nil, fmt.Errorf("filter out of range: %d", id.Filter)
+	}
+	p := -1
+	for i, part := range l.partitions {
+		if part.companyDigits == len(id.CompanyPrefix) {
+			p = i
+			break
+		}
+	}
+	if p < 0 {
+		return nil, fmt.Errorf("company prefix must have 6..12 digits, got %d", len(id.CompanyPrefix))
+	}
+	part := l.partitions[p]
+
+	company, err := parseDigits(id.CompanyPrefix, "company prefix")
+	if err != nil {
+		return nil, err
+	}
+	var ref uint64
+	switch {
+	case id.Scheme == SchemeGIAI96:
+		if len(id.Reference) > 1 && id.Reference[0] == '0' {
+			return nil, fmt.Errorf("asset reference must not have leading zeros")
+		}
+		if len(id.Reference) > part.refDigits {
+			return nil, fmt.Errorf("asset reference exceeds %d digits", part.refDigits)
+		}
+		ref, err = parseDigits(id.Reference, "asset reference")
+	case len(id.Reference) != part.refDigits:
+		return nil, fmt.Errorf("reference must have %d digits for a %d-digit company prefix", part.refDigits, part.companyDigits)
+	case part.refDigits > 0:
+		ref, err = parseDigits(id.Reference, "reference")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ref >= 1<<part.refBits {
+		return nil, fmt.Errorf("reference does not fit %d bits", part.refBits)
+	}
+
+	out := make([]byte, 12)
+	off := writeBits(out, 0, 8, uint64(l.header))
+	off = writeBits(out, off, 3, uint64(id.Filter))
+	off = writeBits(out, off, 3, uint64(p))
+	off = writeBits(out, off, part.companyBits, company)
+	off = writeBits(out, off, part.refBits, ref)
+	if l.serialBits > 0 {
+		if len(id.Serial) > 1 && id.Serial[0] == '0' {
+			return nil, fmt.Errorf("serial must not have leading zeros")
+		}
+		serial, err := parseDigits(id.Serial, "serial")
+		if err != nil {
+			return nil, err
+		}
+		if serial >= 1<<l.serialBits {
+			return nil, fmt.Errorf("serial does not fit %d bits", l.serialBits)
+		}
+		writeBits(out, off, l.serialBits, serial)
+	}
+	return out, nil
+}
+
+// URI returns the pure identity URI, e.g. urn:epc:id:sgtin:0614141.812345.6789.
+func (id Identity) URI() string {
+	l := layouts[id.Scheme]
+	switch id.Scheme {
+	case SchemeSGTIN96, SchemeGRAI96:
+		return "urn:epc:id:" + l.name + ":" + id.CompanyPrefix + "." + id.Reference + "." + id.Serial
+	case SchemeSSCC96, SchemeGIAI96:
+		return "urn:epc:id:" + l.name + ":" + id.CompanyPrefix + "." + id.Reference
+	}
+	return ""
+}
+
+// TagURI returns the EPC tag URI including the filter value.
+func (id Identity) TagURI() string {
+	pure := id.URI()
+	if pure == "" {
+		return ""
+	}
+	body := strings.SplitN(pure, ":", 5)[4]
+	return fmt.Sprintf("urn:epc:tag:%s:%d.%s", id.Scheme, id.Filter, body)
+}
+
+// GTIN returns the 14-digit GTIN of an SGTIN.
+func (id Identity) GTIN() string {
+	if id.Scheme != SchemeSGTIN96 || id.Reference == "" {
+		return ""
+	}
+	digits := id.Reference[:1] + id.CompanyPrefix + id.Reference[1:]
+	return digits + checkDigit(digits)
+}
+
+// SSCC returns the 18-digit SSCC.
+func (id Identity) SSCC() string {
+	if id.Scheme != SchemeSSCC96 || id.Reference == "" {
+		return ""
+	}
+	digits := id.Reference[:1] + id.CompanyPrefix + id.Reference[1:]
+	return digits + checkDigit(digits)
+}
+
+// GRAI returns the GRAI element string: 0, company, asset type, check digit, serial.
+func (id Identity) GRAI() string {
+	if id.Scheme != SchemeGRAI96 {
+		return ""
+	}
+	digits := "0" + id.CompanyPrefix + id.Reference
+	return digits + checkDigit(digits) + id.Serial
+}
+
+// GIAI returns the GIAI element string: company prefix and asset reference.
+func (id Identity) GIAI() string {
+	if id.Scheme != SchemeGIAI96 {
+		return ""
+	}
+	return id.CompanyPrefix + id.Reference
+}
+
+// Label is a short human-readable form for logs and chat messages.
+func (id Identity) Label() string {
+	switch id.Scheme {
+	case SchemeSGTIN96:
+		return "GTIN " + id.GTIN() + " S/N " + id.Serial
+	case SchemeSSCC96:
+		return "SSCC " + id.SSCC()
+	case SchemeGRAI96:
+		return "GRAI " + id.GRAI()
+	case SchemeGIAI96:
+		return "GIAI " + id.GIAI()
+	}
+	return ""
+}
+
+func checkDigit(digits string) string {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return strconv.Itoa((10 - sum%10) % 10)
+}
+
+func formatDigits(v uint64, digits int) (string, bool) {
+	if digits == 0 {
+		return "", v == 0
+	}
+	s := fmt.Sprintf("%0*d", digits, v)
+	return s, len(s) == digits
+}
+
+func parseDigits(s, field string) (uint64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("%s is empty", field)
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, fmt.Errorf("%s must be numeric: %q", field, s)
+		}
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s out of range: %q", field, s)
+	}
+	return v, nil
+}
+
+// readBits reads n (<=64) bits starting at bit offset off, MSB first.
+func readBits(data []byte, off, n int) uint64 {
+	var v uint64
+	for i := 0; i < n; i++ {
+		bit := off + i
+		v = v<<1 | uint64(data[bit/8]>>(7-bit%8)&1)
+	}
+	return v
+}
+
+// writeBits writes the low n bits of v at bit offset off and returns the next offset.
+func writeBits(data []byte, off, n int, v uint64) int {
+	for i := 0; i < n; i++ {
+		bit := off + i
+		if v>>(n-1-i)&1 == 1 {
+			data[bit/8] |= 1 << (7 - bit%8)
+		}
+	}
+	return off + n
+}
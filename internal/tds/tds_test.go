@@ -0,0 +1,97 @@
+package tds
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDecodeKnownVectors(t *testing.T) {
+	cases := []struct {
+		hex, uri, tagURI, label string
+	}{
+		{
+			hex:    "3074257BF7194E4000001A85",
+			uri:    "urn:epc:id:sgtin:0614141.812345.6789",
+			tagURI: "urn:epc:tag:sgtin-96:3.0614141.812345.6789",
+			label:  "GTIN 80614141123458 S/N 6789",
+		},
+		{
+			hex:    "3114257BF4499602D2000000",
+			uri:    "urn:epc:id:sscc:0614141.1234567890",
+			tagURI: "urn:epc:tag:sscc-96:0.0614141.1234567890",
+			label:  "SSCC 106141412345678908",
+		},
+	}
+	for _, tc := range cases {
+		id, err := DecodeHex(tc.hex)
+		if err != nil {
+			t.Fatalf("%s: decode: %v", tc.hex, err)
+		}
+		if id.URI() != tc.uri || id.TagURI() != tc.tagURI || id.Label() != tc.label {
+			t.Fatalf("%s: got uri=%s tag=%s label=%q", tc.hex, id.URI(), id.TagURI(), id.Label())
+		}
+	}
+}
+
+func TestEncodeRoundTripAllSchemes(t *testing.T) {
+	uris := []string{
+		"urn:epc:tag:sgtin-96:3.0614141.812345.6789",
+		"urn:epc:tag:sscc-96:0.0614141.1234567890",
+		"urn:epc:tag:grai-96:1.0614141.12345.400",
+		"urn:epc:tag:giai-96:2.0614141.12345400",
+		"urn:epc:tag:sgtin-96:0.061414112345.0.1",
+		"urn:epc:tag:grai-96:0.061414112345..7",
+	}
+	for _, uri := range uris {
+		id, err := ParseURI(uri)
+		if err != nil {
+			t.Fatalf("%s: parse: %v", uri, err)
+		}
+		raw, err := Encode(id)
+		if err != nil {
+			t.Fatalf("%s: encode: %v", uri, err)
+		}
+		back, err := Decode(raw)
+		if err != nil {
+			t.Fatalf("%s: decode %s: %v", uri, hex.EncodeToString(raw), err)
+		}
+		if back != id || back.TagURI() != uri {
+			t.Fatalf("%s: round trip mismatch: %+v -> %s", uri, id, back.TagURI())
+		}
+	}
+}
+
+func TestEncodeSGTINMatchesStandardHex(t *testing.T) {
+	id, err := ParseURI("urn:epc:id:sgtin:0614141.812345.6789")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	id.Filter = 3
+	raw, err := Encode(id)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if got := strings.ToUpper(hex.EncodeToString(raw)); got != "3074257BF7194E4000001A85" {
+		t.Fatalf("unexpected SGTIN-96 hex: %s", got)
+	}
+}
+
+func TestDecodeRejectsForeignAndMalformedEPCs(t *testing.T) {
+	for _, in := range []string{
+		"E20000112233445566778899", // vendor EPC, not GS1
+		"3074257BF7194E40",         // 64 bits
+		"307C257BF7194E4000001A85", // partition 7
+		"3114257BF4499602D2000001", // SSCC reserved bits set
+	} {
+		if _, err := DecodeHex(in); err == nil {
+			t.Fatalf("%s: expected decode error", in)
+		}
+	}
+	if _, err := Encode(Identity{Scheme: SchemeSGTIN96, CompanyPrefix: "0614141", Reference: "812345", Serial: "06789"}); err == nil {
+		t.Fatalf("expected leading-zero serial to be rejected")
+	}
+	if _, err := ParseURI("urn:epc:id:sgtin:0614141.812345"); err == nil {
+		t.Fatalf("expected field count error")
+	}
+}
@@ -0,0 +1,70 @@
+package tds
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseURI parses a pure identity URI (urn:epc:id:sgtin:...) or an EPC tag
+// URI (urn:epc:tag:sgtin-96:F....). Pure identity URIs carry no filter, so
+// Filter is left at zero for the caller to set before Encode.
+func ParseURI(uri string) (Identity, error) {
+	uri = strings.TrimSpace(uri)
+	parts := strings.SplitN(uri, ":", 5)
+	if len(parts) != 5 || !strings.EqualFold(parts[0], "urn") || !strings.EqualFold(parts[1], "epc") {
+		return Identity{}, fmt.Errorf("not an EPC URI: %q", uri)
+	}
+
+	var id Identity
+	body := parts[4]
+	switch strings.ToLower(parts[2]) {
+	case "id":
+		scheme, ok := schemeForName(strings.ToLower(parts[3]))
+		if !ok {
+			return Identity{}, fmt.Errorf("unsupported EPC scheme: %q", parts[3])
+		}
+		id.Scheme = scheme
+	case "tag":
+		id.Scheme = Scheme(strings.ToLower(parts[3]))
+		if _, ok := layouts[id.Scheme]; !ok {
+			return Identity{}, fmt.Errorf("unsupported EPC scheme: %q", parts[3])
+		}
+		filter, rest, ok := strings.Cut(body, ".")
+		if !ok {
+			return Identity{}, fmt.Errorf("tag URI without filter: %q", uri)
+		}
+		f, err := strconv.Atoi(filter)
+		if err != nil {
+			return Identity{}, fmt.Errorf("invalid filter: %q", filter)
+		}
+		id.Filter = f
+		body = rest
+	default:
+		return Identity{}, fmt.Errorf("not an EPC URI: %q", uri)
+	}
+
+	fields := strings.Split(body, ".")
+	want := 2
+	if id.Scheme == SchemeSGTIN96 || id.Scheme == SchemeGRAI96 {
+		want = 3
+	}
+	if len(fields) != want {
+		return Identity{}, fmt.Errorf("%s URI needs %d fields, got %d", id.Scheme, want, len(fields))
+	}
+	id.CompanyPrefix = fields[0]
+	id.Reference = fields[1]
+	if want == 3 {
+		id.Serial = fields[2]
+	}
+	return id, nil
+}
+
+func schemeForName(name string) (Scheme, bool) {
+	for scheme, l := range layouts {
+		if l.name == name {
+			return scheme, true
+		}
+	}
+	return "", false
+}
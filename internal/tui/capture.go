@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+
+	"new_era_go/internal/reader"
+)
+
+// setupCapture applies BOT_TUI_CAPTURE_FILE and BOT_TUI_REPLAY_FILE. With a
+// replay file the startup scan is skipped and the TUI connects to the
+// recorded session instead of a reader.
+func (m Model) setupCapture() Model {
+	if path := envOr("BOT_TUI_CAPTURE_FILE", ""); path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			m.pushLog("[capture] " + err.Error())
+		} else if f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
+			m.pushLog("[capture] " + err.Error())
+		} else {
+			m.reader.SetRecorder(reader.NewRecorder(f))
+			m.pushLog("[capture] recording to " + path)
+		}
+	}
+	if path := envOr("BOT_TUI_REPLAY_FILE", ""); path != "" {
+		m.replay = reader.ReplayConfig{File: path}
+		m.scanning = false
+		m.pendingConnect = false
+		m.connecting = true
+		m.status = "Replaying " + path
+		m.pushLog("[startup] replay " + path)
+	}
+	return m
+}
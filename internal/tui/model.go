@@ -10,6 +10,7 @@ import (
 	reader18 "new_era_go/internal/protocol/reader18"
 	"new_era_go/internal/reader"
 	"new_era_go/internal/regions"
+	"new_era_go/sdk"
 )
 
 func NewModel() Model {
@@ -22,9 +23,22 @@ func NewModel() Model {
 	regionIdx := regions.DefaultIndex()
 
 	opts := discovery.DefaultOptions()
+	profiles, profileErr := loadProfileStore()
+	logs := []string{"[startup] scan requested"}
+	if profileErr != "" {
+		logs = append(logs, profileErr)
+	}
+	overflow, err := reader.ParseOverflowPolicy(envOr("BOT_READER_OVERFLOW", "drop-newest"))
+	if err != nil {
+		overflow = reader.OverflowDropNewest
+		logs = append(logs, "[overflow] "+err.Error())
+	}
+	client := reader.NewClient()
+	client.SetOverflowPolicy(overflow)
 
-	return Model{
-		reader:            reader.NewClient(),
+	m := Model{
+		reader:            client,
+		overflow:          overflow,
 		activeScreen:      screenHome,
 		homeIndex:         0,
 		deviceIndex:       0,
@@ -42,7 +56,7 @@ func NewModel() Model {
 		input:             in,
 		inputMode:         inputModeNone,
 		status:            "Startup scan running...",
-		logs:              []string{"[startup] scan requested"},
+		logs:              logs,
 		botSocket:         envOr("BOT_SYNC_SOCKET", envOr("BOT_IPC_SOCKET", "/tmp/rfid-go-bot.sock")),
 		rxBytes:           0,
 		txBytes:           0,
@@ -68,15 +82,24 @@ func NewModel() Model {
 		inventoryAntIdx:   0,
 		lastTagEPC:        "",
 		seenTagEPC:        make(map[string]struct{}),
+		telemetry:         sdk.NewTelemetry(sdk.DefaultTelemetryWindow),
+		profiles:          profiles,
 		protocolBuffer:    nil,
 		lastRawLogAt:      time.Time{},
 		awaitingProbe:     false,
 		width:             0,
 		height:            0,
 	}
+	return m.setupCapture()
 }
 
 func (m Model) Init() tea.Cmd {
+	if m.replay.File != "" {
+		return tea.Batch(
+			connectCmd(m.reader, reader.Endpoint{Replay: m.replay}),
+			botStatusTickCmd(300*time.Millisecond),
+		)
+	}
 	return tea.Batch(
 		runScanCmd(m.scanOptions),
 		botStatusTickCmd(300*time.Millisecond),
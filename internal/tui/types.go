@@ -7,6 +7,8 @@ import (
 
 	"new_era_go/internal/discovery"
 	"new_era_go/internal/reader"
+	"new_era_go/internal/regions"
+	"new_era_go/sdk"
 )
 
 type screen int
@@ -26,6 +28,7 @@ type inputMode int
 const (
 	inputModeNone inputMode = iota
 	inputModeRawHex
+	inputModeProfileName
 )
 
 type menuItem struct {
@@ -38,10 +41,21 @@ type frequencyWindow struct {
 	Low  byte
 }
 
-var inventoryFrequencyWindows = []frequencyWindow{
-	{High: 0x3E, Low: 0x28},
-	{High: 0x37, Low: 0x05},
-	{High: 0x3E, Low: 0x00},
+// regionFrequencyWindows splits a region into full, upper and lower channel
+// windows for the no-tag frequency cycle.
+func regionFrequencyWindows(region regions.Region) []frequencyWindow {
+	mid := (region.MinChannel + region.MaxChannel) / 2
+	ranges := [][2]int{
+		{region.MinChannel, region.MaxChannel},
+		{mid, region.MaxChannel},
+		{region.MinChannel, mid},
+	}
+	windows := make([]frequencyWindow, 0, len(ranges))
+	for _, r := range ranges {
+		high, low := regions.EncodeRange(region.BandCode, r[0], r[1])
+		windows = append(windows, frequencyWindow{High: high, Low: low})
+	}
+	return windows
 }
 
 var homeMenu = []menuItem{
@@ -136,6 +150,8 @@ type Model struct {
 	inventoryIndex int
 	regionIndex    int
 	regionCursor   int
+	regionPending  bool
+	regionSent     bool
 	logScroll      int
 	pendingConnect bool
 	pendingAction  int
@@ -144,6 +160,7 @@ type Model struct {
 	scanning     bool
 	candidates   []discovery.Candidate
 	lastScanTime time.Duration
+	replay       reader.ReplayConfig
 
 	input     textinput.Model
 	inputMode inputMode
@@ -162,9 +179,10 @@ type Model struct {
 	connectActionLabel string
 	connecting         bool
 
-	rxBytes int
-	txBytes int
-	lastRX  string
+	rxBytes  int
+	txBytes  int
+	overflow reader.OverflowPolicy
+	lastRX   string
 
 	inventoryRunning  bool
 	inventoryInterval time.Duration
@@ -178,6 +196,11 @@ type Model struct {
 	inventoryScanTime byte
 	inventoryNoTagAB  int
 	inventoryNoTagHit int
+	// inventoryAdaptive drives Q/session while the adaptive preset is on.
+	inventoryAdaptive *sdk.AdaptiveTuner
+	// profiles is the named profile file shared with the bot.
+	profiles          *sdk.ProfileStore
+	profileIndex      int
 	showPhaseFreq     bool
 	lastTagAntenna    int
 	lastTagRSSI       int
@@ -187,9 +210,14 @@ type Model struct {
 	inventoryAntIdx   int
 	lastTagEPC        string
 	seenTagEPC        map[string]struct{}
-	protocolBuffer    []byte
-	lastRawLogAt      time.Time
-	awaitingProbe     bool
+	telemetry         *sdk.Telemetry
+	// telemetrySnap is refreshed on inventory ticks at most every
+	// telemetryRefresh so rendering never copies the sample window.
+	telemetrySnap  sdk.TelemetrySnapshot
+	telemetryAt    time.Time
+	protocolBuffer []byte
+	lastRawLogAt   time.Time
+	awaitingProbe  bool
 
 	width  int
 	height int
@@ -8,6 +8,7 @@ import (
 
 	reader18 "new_era_go/internal/protocol/reader18"
 	tuiupdate "new_era_go/internal/tui/update"
+	"new_era_go/sdk"
 )
 
 func (m Model) updateControlKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -54,13 +55,19 @@ func (m Model) runControlAction(index int) (tea.Model, tea.Cmd) {
 		m.lastTagAntenna = 0
 		m.lastTagRSSI = 0
 		m.seenTagEPC = make(map[string]struct{})
+		m.telemetry.Reset()
+		m.telemetrySnap = sdk.TelemetrySnapshot{}
 		m.inventoryAutoAddr = true
 		m.protocolBuffer = nil
 		m.status = "Preparing reader + reading started"
 		m.pushLog(fmt.Sprintf("reading started (poll=%s effective=%s scan=%d)", m.inventoryInterval, m.effectiveInventoryInterval(), m.inventoryScanTime))
 		getBotSyncClient().onStartReading()
+		// takeRegionCmd updates m, so it must run before the return
+		// statement evaluates m.
+		regionCmd := m.takeRegionCmd()
 		return m, tea.Batch(
 			sendNamedCmdSilent(m.reader, "cfg-work-mode", reader18.SetWorkModeCommand(m.inventoryAddress, []byte{0x00})),
+			regionCmd,
 			sendNamedCmdSilent(m.reader, "cfg-scan-time", reader18.SetScanTimeCommand(m.inventoryAddress, m.inventoryScanTime)),
 			sendNamedCmdSilent(m.reader, "cfg-ant-mask", reader18.SetAntennaMuxCommand(m.inventoryAddress, m.inventoryAntMask)),
 			sendNamedCmdSilent(m.reader, "cfg-power", reader18.SetOutputPowerCommand(m.inventoryAddress, 0x1E)),
@@ -83,8 +90,11 @@ func (m Model) runControlAction(index int) (tea.Model, tea.Cmd) {
 			return m.requestConnectionForAction(2, "Probe Reader Info")
 		}
 		packet := reader18.GetReaderInfoCommand(m.inventoryAddress)
-		m.status = "Sending GetReaderInfo"
-		return m, sendNamedCmd(m.reader, "probe-info", packet)
+		m.status = "Sending GetReaderInfo + GetWorkMode"
+		return m, tea.Sequence(
+			sendNamedCmd(m.reader, "probe-info", packet),
+			sendNamedCmdSilent(m.reader, "probe-work-mode", reader18.GetWorkModeCommand(m.inventoryAddress)),
+		)
 
 	case 3:
 		if !m.reader.IsConnected() {
@@ -3,8 +3,10 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	reader18 "new_era_go/internal/protocol/reader18"
+	"new_era_go/sdk"
 )
 
 func (m *Model) handleProtocolFrame(frame reader18.Frame) {
@@ -26,6 +28,7 @@ func (m *Model) handleProtocolFrame(frame reader18.Frame) {
 			return
 		}
 		if len(tags) > 0 {
+			m.observeAdaptiveRound(len(tags), frame.Status)
 			if m.seenTagEPC == nil {
 				m.seenTagEPC = make(map[string]struct{})
 			}
@@ -39,6 +42,7 @@ func (m *Model) handleProtocolFrame(frame reader18.Frame) {
 				m.lastTagEPC = epcText
 				m.lastTagAntenna = tag.Antenna
 				m.lastTagRSSI = tag.RSSI
+				m.telemetry.Observe(sdk.TagEvent{EPC: epcText, Antenna: tag.Antenna, RSSI: tag.RSSI})
 				if _, exists := m.seenTagEPC[epcText]; exists {
 					continue
 				}
@@ -46,7 +50,7 @@ func (m *Model) handleProtocolFrame(frame reader18.Frame) {
 				m.inventoryTagTotal++
 				newCount++
 				getBotSyncClient().onNewEPC(epcText)
-				m.pushLog(fmt.Sprintf("new tag ant=%d epc=%s rssi=%d total=%d", tag.Antenna, epcText, tag.RSSI, m.inventoryTagTotal))
+				m.pushLog(fmt.Sprintf("new tag ant=%d epc=%s rssi=%d total=%d%s", tag.Antenna, epcText, tag.RSSI, m.inventoryTagTotal, tagIdentitySuffix(epcText)))
 			}
 			if newCount > 0 {
 				if m.activeScreen == screenControl {
@@ -60,7 +64,11 @@ func (m *Model) handleProtocolFrame(frame reader18.Frame) {
 			return
 		}
 		if frame.Status == reader18.StatusSuccess {
-			if count, err := reader18.InventoryTagCount(frame); err == nil && count > 0 {
+			count, err := reader18.InventoryTagCount(frame)
+			if err == nil {
+				m.observeAdaptiveRound(count, reader18.StatusNoTag)
+			}
+			if err == nil && count > 0 {
 				if m.activeScreen == screenControl && m.inventoryRounds%6 == 0 {
 					m.status = fmt.Sprintf("Reader reports %d tag(s), waiting EPC frame...", count)
 				}
@@ -73,6 +81,7 @@ func (m *Model) handleProtocolFrame(frame reader18.Frame) {
 
 		switch frame.Status {
 		case reader18.StatusNoTag, 0x02, 0x03, 0x04, reader18.StatusNoTagOrTimeout:
+			m.observeAdaptiveRound(0, frame.Status)
 			m.onNoTagObserved()
 			if m.activeScreen == screenControl && m.inventoryRunning && m.inventoryRounds%24 == 0 {
 				m.status = fmt.Sprintf("Reading... no tag (rounds=%d)", m.inventoryRounds)
@@ -117,6 +126,7 @@ func (m *Model) handleProtocolFrame(frame reader18.Frame) {
 				m.lastTagEPC = epcText
 				m.lastTagAntenna = int(result.Antenna)
 				m.lastTagRSSI = 0
+				m.telemetry.Observe(sdk.TagEvent{EPC: epcText, Antenna: int(result.Antenna)})
 				m.inventoryNoTagHit = 0
 				if m.seenTagEPC == nil {
 					m.seenTagEPC = make(map[string]struct{})
@@ -128,7 +138,7 @@ func (m *Model) handleProtocolFrame(frame reader18.Frame) {
 					if m.activeScreen == screenControl {
 						m.status = fmt.Sprintf("New tag: ant=%d epc=%s", result.Antenna, trimText(epcText, 28))
 					}
-					m.pushLog(fmt.Sprintf("new tag ant=%d epc=%s total=%d", result.Antenna, epcText, m.inventoryTagTotal))
+					m.pushLog(fmt.Sprintf("new tag ant=%d epc=%s total=%d%s", result.Antenna, epcText, m.inventoryTagTotal, tagIdentitySuffix(epcText)))
 				} else if m.activeScreen == screenControl && m.inventoryRunning && m.inventoryRounds%24 == 0 {
 					m.status = fmt.Sprintf("Tag seen again: %s", trimText(epcText, 28))
 				}
@@ -162,14 +172,19 @@ func (m *Model) handleProtocolFrame(frame reader18.Frame) {
 		}
 
 	case reader18.CmdGetReaderInfo:
+		// The automatic connect probe runs before our config is applied, so it is not diffed.
+		compare := !m.awaitingProbe
 		m.awaitingProbe = false
 		if frame.Status == reader18.StatusSuccess {
-			m.status = "Reader info received"
 			m.pushLog("reader info: " + formatHex(frame.Data, 48))
+			m.handleReaderInfoFrame(frame, compare)
 		} else {
 			m.pushLog(fmt.Sprintf("reader info status: 0x%02X", frame.Status))
 		}
 
+	case reader18.CmdGetWorkMode:
+		m.handleWorkModeFrame(frame)
+
 	default:
 		if !m.inventoryRunning {
 			m.pushLog(fmt.Sprintf("rx cmd=0x%02X status=0x%02X", frame.Command, frame.Status))
@@ -191,3 +206,30 @@ func (m *Model) onNoTagObserved() {
 		m.pushLog("no-tag threshold reached, target switched to " + targetLabel(m.inventoryTarget))
 	}
 }
+
+// observeAdaptiveRound feeds one 0x01 response to the adaptive preset and
+// takes over its Q/session decisions.
+func (m *Model) observeAdaptiveRound(tags int, status byte) {
+	if m.inventoryAdaptive == nil || !m.inventoryRunning {
+		return
+	}
+	for _, decision := range m.inventoryAdaptive.Observe(tags, status) {
+		m.pushLog(decision)
+	}
+	m.inventoryQValue = m.inventoryAdaptive.Q()
+	if session := m.inventoryAdaptive.Session(); session != m.inventorySession {
+		m.inventorySession = session
+		m.inventoryNoTagHit = 0
+	}
+}
+
+// telemetryRefresh bounds how often the Control page statistics are rebuilt.
+const telemetryRefresh = time.Second
+
+func (m *Model) refreshTelemetry(now time.Time) {
+	if now.Sub(m.telemetryAt) < telemetryRefresh {
+		return
+	}
+	m.telemetrySnap = m.telemetry.SnapshotAt(now)
+	m.telemetryAt = now
+}
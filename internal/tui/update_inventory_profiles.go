@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"new_era_go/sdk"
+)
+
+// loadProfileStore opens the profile file shared with the bot. A broken file
+// leaves the built-in profiles usable and saving disabled.
+func loadProfileStore() (*sdk.ProfileStore, string) {
+	path := envOr("BOT_PROFILE_FILE", "logs/reader_profiles.json")
+	store, err := sdk.LoadProfileStore(path)
+	if err != nil {
+		return sdk.NewProfileStore(""), "[profiles] " + err.Error()
+	}
+	return store, ""
+}
+
+func (m Model) selectedProfileName() string {
+	names := m.profiles.Names()
+	if len(names) == 0 {
+		return ""
+	}
+	return names[(m.profileIndex%len(names)+len(names))%len(names)]
+}
+
+func (m Model) cycleProfile(delta int) Model {
+	names := m.profiles.Names()
+	if len(names) == 0 {
+		m.status = "No profiles"
+		return m
+	}
+	m.profileIndex = ((m.profileIndex+delta)%len(names) + len(names)) % len(names)
+	m.status = "Profile selected: " + names[m.profileIndex]
+	return m
+}
+
+// loadInventoryProfile copies the profile fields the TUI edits. Output power
+// and region stay with the Regions screen and connect sequence.
+func (m Model) loadInventoryProfile(name string) Model {
+	p, ok := m.profiles.Get(name)
+	if !ok {
+		m.status = "Profile not found: " + name
+		return m
+	}
+	cfg := p.Apply(sdk.DefaultInventoryConfig())
+	m.inventoryQValue = cfg.QValue
+	m.inventorySession = cfg.Session
+	m.inventoryTarget = cfg.Target
+	m.inventoryScanTime = cfg.ScanTime
+	m.inventoryNoTagAB = cfg.NoTagABSwitch
+	m.inventoryInterval = cfg.PollInterval
+	m.inventoryAntMask = cfg.AntennaMask
+	m.inventoryAdaptive = nil
+	if cfg.AdaptiveQ || cfg.AdaptiveSession {
+		m.inventoryAdaptive = sdk.NewAdaptiveTuner(cfg)
+	}
+	m.status = "Profile loaded: " + p.Name
+	m.pushLog(fmt.Sprintf("profile %s: q=%d s=%d target=%d scan=%d poll=%s effective=%s mask=0x%02X", p.Name, m.inventoryQValue, m.inventorySession, m.inventoryTarget, m.inventoryScanTime, m.inventoryInterval, m.effectiveInventoryInterval(), m.inventoryAntMask))
+	return m
+}
+
+// inventoryProfile captures the current tune screen as a named profile.
+func (m Model) inventoryProfile(name string) sdk.Profile {
+	cfg := sdk.DefaultInventoryConfig()
+	cfg.QValue = m.inventoryQValue
+	cfg.Session = m.inventorySession
+	cfg.Target = m.inventoryTarget
+	cfg.ScanTime = m.inventoryScanTime
+	cfg.NoTagABSwitch = m.inventoryNoTagAB
+	cfg.PollInterval = m.inventoryInterval
+	cfg.AntennaMask = m.inventoryAntMask
+	cfg.AdaptiveQ = m.inventoryAdaptive != nil
+	cfg.AdaptiveSession = m.inventoryAdaptive != nil
+	if m.inventoryAdaptive != nil {
+		cfg.QValue = m.inventoryAdaptive.Q()
+		cfg.Session = m.inventoryAdaptive.Session()
+	}
+	return sdk.ProfileFromConfig(name, cfg)
+}
+
+func (m Model) enterProfileNameMode() (tea.Model, tea.Cmd) {
+	m.inputMode = inputModeProfileName
+	m.input.Prompt = "NAME> "
+	m.input.Placeholder = "dock-door"
+	m.input.SetValue("")
+	m.input.Focus()
+	m.status = "Profile name: letters, digits, '-' or '_'; Enter saves"
+	return m, nil
+}
+
+func (m Model) exitProfileNameMode() Model {
+	m.inputMode = inputModeNone
+	m.input.Blur()
+	m.input.SetValue("")
+	m.input.Prompt = "HEX> "
+	m.input.Placeholder = "04 00 21 D9 6A"
+	return m
+}
+
+func (m Model) updateProfileNameInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m = m.exitProfileNameMode()
+		m.status = "Profile save canceled"
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(m.input.Value())
+		if err := m.profiles.Put(m.inventoryProfile(name)); err != nil {
+			m.status = "Profile save failed: " + err.Error()
+			return m, nil
+		}
+		m = m.exitProfileNameMode()
+		for i, n := range m.profiles.Names() {
+			if n == name {
+				m.profileIndex = i
+			}
+		}
+		m.status = fmt.Sprintf("Profile saved: %s (%s)", name, m.profiles.Path())
+		m.pushLog("profile saved: " + name)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
@@ -9,6 +9,7 @@ import (
 
 	reader18 "new_era_go/internal/protocol/reader18"
 	tuiupdate "new_era_go/internal/tui/update"
+	"new_era_go/sdk"
 )
 
 const (
@@ -25,6 +26,9 @@ const (
 	invTunePresetFast
 	invTunePresetBalanced
 	invTunePresetLongRange
+	invTunePresetAdaptive
+	invTuneProfileLoad
+	invTuneProfileSave
 	invTuneCount
 )
 
@@ -60,9 +64,11 @@ func (m Model) updateInventoryKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) adjustInventorySetting(delta int) (tea.Model, tea.Cmd) {
 	switch m.inventoryIndex {
 	case invTuneQValue:
+		m.inventoryAdaptive = nil
 		m.inventoryQValue = byte(tuiupdate.ClampInt(int(m.inventoryQValue)+delta, 0, 15))
 		m.status = fmt.Sprintf("Q value set to %d", m.inventoryQValue)
 	case invTuneSession:
+		m.inventoryAdaptive = nil
 		m.inventorySession = byte(tuiupdate.ClampInt(int(m.inventorySession)+delta, 0, 3))
 		m.status = fmt.Sprintf("Session set to %d", m.inventorySession)
 	case invTuneTarget:
@@ -85,6 +91,8 @@ func (m Model) adjustInventorySetting(delta int) (tea.Model, tea.Cmd) {
 		nextMS := tuiupdate.ClampInt(int(m.inventoryInterval/time.Millisecond)+delta*10, 20, 1000)
 		m.inventoryInterval = time.Duration(nextMS) * time.Millisecond
 		m.status = fmt.Sprintf("Poll interval set to %s, effective cycle %s", m.inventoryInterval, m.effectiveInventoryInterval())
+	case invTuneProfileLoad:
+		m = m.cycleProfile(delta)
 	default:
 		m.status = "Select a parameter row to edit"
 	}
@@ -131,12 +139,21 @@ func (m Model) runInventoryAction() (tea.Model, tea.Cmd) {
 	case invTunePresetLongRange:
 		m = m.applyInventoryPreset("long-range")
 		return m, nil
+	case invTunePresetAdaptive:
+		m = m.applyInventoryPreset("adaptive")
+		return m, nil
+	case invTuneProfileLoad:
+		m = m.loadInventoryProfile(m.selectedProfileName())
+		return m, nil
+	case invTuneProfileSave:
+		return m.enterProfileNameMode()
 	}
 
 	return m.adjustInventorySetting(1)
 }
 
 func (m Model) applyInventoryPreset(name string) Model {
+	m.inventoryAdaptive = nil
 	switch name {
 	case "fast":
 		m.inventoryQValue = 4
@@ -165,6 +182,22 @@ func (m Model) applyInventoryPreset(name string) Model {
 		m.inventoryInterval = 120 * time.Millisecond
 		m.inventoryAntMask = 0x01
 		m.status = "Preset applied: long-range"
+	case "adaptive":
+		// Balanced timing; Q and session then follow the observed tag population.
+		m.inventoryQValue = 4
+		m.inventorySession = 1
+		m.inventoryTarget = 0
+		m.inventoryScanTime = 2
+		m.inventoryNoTagAB = 4
+		m.inventoryInterval = 70 * time.Millisecond
+		m.inventoryAntMask = 0x01
+		m.inventoryAdaptive = sdk.NewAdaptiveTuner(sdk.InventoryConfig{
+			QValue:          m.inventoryQValue,
+			Session:         m.inventorySession,
+			AdaptiveQ:       true,
+			AdaptiveSession: true,
+		})
+		m.status = "Preset applied: adaptive (Q/session auto)"
 	}
 	m.pushLog(fmt.Sprintf("preset %s: q=%d s=%d target=%d scan=%d poll=%s effective=%s mask=0x%02X", name, m.inventoryQValue, m.inventorySession, m.inventoryTarget, m.inventoryScanTime, m.inventoryInterval, m.effectiveInventoryInterval(), m.inventoryAntMask))
 	return m
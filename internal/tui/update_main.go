@@ -12,6 +12,7 @@ import (
 
 	reader18 "new_era_go/internal/protocol/reader18"
 	tuiupdate "new_era_go/internal/tui/update"
+	"new_era_go/sdk"
 )
 
 const autoFreqCycleEnabled = false
@@ -30,6 +31,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.inputMode == inputModeRawHex {
 			return m.updateRawInput(msg)
 		}
+		if m.inputMode == inputModeProfileName {
+			return m.updateProfileNameInput(msg)
+		}
 		return m.updateKey(msg)
 
 	case botStatusMsg:
@@ -87,9 +91,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		m.inventoryRounds++
+		m.refreshTelemetry(time.Now())
 		cmds := make([]tea.Cmd, 0, 5)
-		if autoFreqCycleEnabled && len(inventoryFrequencyWindows) > 0 && m.inventoryTagTotal == 0 && (m.inventoryRounds == 1 || m.inventoryRounds%80 == 0) {
-			window := inventoryFrequencyWindows[m.inventoryFreqIdx%len(inventoryFrequencyWindows)]
+		if autoFreqCycleEnabled && m.inventoryTagTotal == 0 && (m.inventoryRounds == 1 || m.inventoryRounds%80 == 0) {
+			windows := regionFrequencyWindows(m.selectedRegion())
+			window := windows[m.inventoryFreqIdx%len(windows)]
 			m.inventoryFreqIdx++
 			cmds = append(cmds, sendNamedCmdSilent(m.reader, "cfg-freq-cycle", reader18.SetFrequencyRangeCommand(m.inventoryAddress, window.High, window.Low)))
 		}
@@ -282,6 +288,8 @@ func (m Model) onConnectFinished(msg connectFinishedMsg) (tea.Model, tea.Cmd) {
 		m.lastTagAntenna = 0
 		m.lastTagRSSI = 0
 		m.seenTagEPC = make(map[string]struct{})
+		m.telemetry.Reset()
+		m.telemetrySnap = sdk.TelemetrySnapshot{}
 		m.inventoryAutoAddr = true
 		m.protocolBuffer = nil
 		m.status = "Connected. Preparing reader + reading started"
@@ -289,6 +297,7 @@ func (m Model) onConnectFinished(msg connectFinishedMsg) (tea.Model, tea.Cmd) {
 		getBotSyncClient().onStartReading()
 		base = append(base,
 			sendNamedCmdSilent(m.reader, "cfg-work-mode", reader18.SetWorkModeCommand(m.inventoryAddress, []byte{0x00})),
+			m.takeRegionCmd(),
 			sendNamedCmdSilent(m.reader, "cfg-scan-time", reader18.SetScanTimeCommand(m.inventoryAddress, m.inventoryScanTime)),
 			sendNamedCmdSilent(m.reader, "cfg-ant-mask", reader18.SetAntennaMuxCommand(m.inventoryAddress, m.inventoryAntMask)),
 			sendNamedCmdSilent(m.reader, "cfg-power", reader18.SetOutputPowerCommand(m.inventoryAddress, 0x1E)),
@@ -297,8 +306,11 @@ func (m Model) onConnectFinished(msg connectFinishedMsg) (tea.Model, tea.Cmd) {
 	case 2:
 		m.pendingAction = noPendingAction
 		packet := reader18.GetReaderInfoCommand(m.inventoryAddress)
-		m.status = "Connected. Sending GetReaderInfo"
-		base = append(base, sendNamedCmd(m.reader, "probe-info", packet))
+		m.status = "Connected. Sending GetReaderInfo + GetWorkMode"
+		base = append(base, tea.Sequence(
+			sendNamedCmd(m.reader, "probe-info", packet),
+			sendNamedCmdSilent(m.reader, "probe-work-mode", reader18.GetWorkModeCommand(m.inventoryAddress)),
+		))
 	case 3:
 		m.pendingAction = noPendingAction
 		m.inputMode = inputModeRawHex
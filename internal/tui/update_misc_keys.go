@@ -5,6 +5,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	reader18 "new_era_go/internal/protocol/reader18"
 	"new_era_go/internal/regions"
 )
 
@@ -22,23 +23,49 @@ func (m Model) updateRegionKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.regionCursor = (m.regionCursor + 1) % total
 		return m, nil
 	case "enter":
-		m.regionIndex = m.regionCursor
-		selected := regions.Catalog[m.regionIndex]
-		m.status = fmt.Sprintf("Region selected: %s (%s)", selected.Code, selected.Band)
-		m.pushLog("region selected: " + selected.Code)
-		return m, nil
+		return m.selectRegion(m.regionCursor)
 	}
 
 	if idx, ok := parseDigit(msg.String()); ok && idx < total {
 		m.regionCursor = idx
-		m.regionIndex = idx
-		selected := regions.Catalog[m.regionIndex]
-		m.status = fmt.Sprintf("Region selected: %s (%s)", selected.Code, selected.Band)
-		m.pushLog("region selected: " + selected.Code)
+		return m.selectRegion(idx)
 	}
 	return m, nil
 }
 
+// selectRegion applies the region now when connected, otherwise on the next Start Reading.
+func (m Model) selectRegion(idx int) (tea.Model, tea.Cmd) {
+	m.regionIndex = idx
+	m.regionPending = true
+	selected := m.selectedRegion()
+	m.pushLog(fmt.Sprintf("region selected: %s %s", selected.Code, selected.Channels()))
+	if !m.reader.IsConnected() {
+		m.status = fmt.Sprintf("Region selected: %s (%s), applied on next Start Reading", selected.Code, selected.Band)
+		return m, nil
+	}
+	m.status = fmt.Sprintf("Region set: %s (%s)", selected.Code, selected.Band)
+	regionCmd := m.takeRegionCmd()
+	return m, regionCmd
+}
+
+func (m Model) selectedRegion() regions.Region {
+	if m.regionIndex >= 0 && m.regionIndex < len(regions.Catalog) {
+		return regions.Catalog[m.regionIndex]
+	}
+	return regions.Catalog[regions.DefaultIndex()]
+}
+
+// takeRegionCmd returns the pending SetRegion command once, or nil.
+func (m *Model) takeRegionCmd() tea.Cmd {
+	if !m.regionPending {
+		return nil
+	}
+	m.regionPending = false
+	m.regionSent = true
+	high, low := m.selectedRegion().Encode()
+	return sendNamedCmd(m.reader, "cfg-region", reader18.SetFrequencyRangeCommand(m.inventoryAddress, high, low))
+}
+
 func (m Model) updateLogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	maxVisible := m.logViewSize()
 	maxScroll := len(m.logs) - maxVisible
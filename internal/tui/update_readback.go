@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+	"new_era_go/internal/regions"
+	"new_era_go/sdk"
+)
+
+// desiredReaderConfig is what Start Reading and the Regions page send.
+func (m Model) desiredReaderConfig() sdk.InventoryConfig {
+	cfg := sdk.InventoryConfig{
+		OutputPower: 0x1E,
+		ScanTime:    m.inventoryScanTime,
+		AntennaMask: m.inventoryAntMask,
+		WorkMode:    sdk.WorkModeAnswer,
+	}
+	if m.regionSent {
+		_ = cfg.SetRegion(m.selectedRegion().Code)
+	}
+	return cfg
+}
+
+func (m *Model) handleReaderInfoFrame(frame reader18.Frame, compare bool) {
+	info, err := reader18.ParseReaderInfo(frame)
+	if err != nil {
+		m.pushLog("reader info: " + err.Error())
+		return
+	}
+	got := sdk.InventoryConfig{
+		OutputPower: info.OutputPower,
+		ScanTime:    info.ScanTime,
+		RegionSet:   true,
+		RegionHigh:  info.RegionHigh,
+		RegionLow:   info.RegionLow,
+		WorkMode:    sdk.WorkModeUnknown,
+	}
+	ant := "-"
+	if info.HasAntenna {
+		got.AntennaMask = info.AntennaMask
+		ant = fmt.Sprintf("0x%02X", info.AntennaMask)
+	}
+	got.ReaderAddress = frame.Address
+	m.pushLog(fmt.Sprintf("reader info: fw=%s power=0x%02X scan=%d ant=%s region=%s",
+		info.Version(), info.OutputPower, info.ScanTime, ant, readbackRegion(got)))
+	if !compare {
+		m.status = "Reader info received"
+		return
+	}
+	m.reportDrift(sdk.DiffConfig(m.desiredReaderConfig(), got))
+}
+
+func (m *Model) handleWorkModeFrame(frame reader18.Frame) {
+	mode, err := reader18.ParseWorkMode(frame)
+	if err != nil {
+		m.pushLog("work mode: " + err.Error())
+		return
+	}
+	m.pushLog("work mode: " + reader18.WorkModeName(mode.ReadMode))
+	if mode.ReadMode != sdk.WorkModeAnswer {
+		m.reportDrift([]sdk.ConfigDrift{{Field: "work_mode", Want: reader18.WorkModeName(sdk.WorkModeAnswer), Got: reader18.WorkModeName(mode.ReadMode)}})
+	}
+}
+
+func (m *Model) reportDrift(drift []sdk.ConfigDrift) {
+	if len(drift) == 0 {
+		m.status = "Reader info received: config matches"
+		return
+	}
+	parts := make([]string, 0, len(drift))
+	for _, d := range drift {
+		parts = append(parts, d.String())
+		m.pushLog("config drift: " + d.String())
+	}
+	m.status = "Config drift: " + strings.Join(parts, "; ")
+}
+
+func readbackRegion(cfg sdk.InventoryConfig) string {
+	if code := cfg.RegionCode(); code != "custom" {
+		return code
+	}
+	if len(regions.Matches(cfg.RegionHigh, cfg.RegionLow)) > 1 {
+		return regions.Label(cfg.RegionHigh, cfg.RegionLow)
+	}
+	return fmt.Sprintf("custom[0x%02X/0x%02X]", cfg.RegionHigh, cfg.RegionLow)
+}
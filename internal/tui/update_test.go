@@ -1,11 +1,16 @@
 package tui
 
 import (
+	"path/filepath"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"new_era_go/internal/discovery"
 	reader18 "new_era_go/internal/protocol/reader18"
 	"new_era_go/internal/reader"
+	"new_era_go/internal/regions"
+	"new_era_go/sdk"
 )
 
 func TestStartReadingQueuesScanWhenDisconnectedAndNoCandidates(t *testing.T) {
@@ -133,3 +138,106 @@ func TestLegacyInventoryDoesNotInflateUniqueCounter(t *testing.T) {
 		t.Fatalf("expected unique tag total unchanged, got %d", m.inventoryTagTotal)
 	}
 }
+
+func TestSelectRegionOfflineIsAppliedOnNextStart(t *testing.T) {
+	m := NewModel()
+	next, cmd := m.selectRegion(1)
+	if cmd != nil {
+		t.Fatal("expected no command while disconnected")
+	}
+	nm := next.(Model)
+	if nm.selectedRegion().Code != "EU" || !nm.regionPending {
+		t.Fatalf("expected pending EU region, got %s pending=%v", nm.selectedRegion().Code, nm.regionPending)
+	}
+	if nm.takeRegionCmd() == nil {
+		t.Fatal("expected pending region command")
+	}
+	if nm.regionPending || nm.takeRegionCmd() != nil {
+		t.Fatal("region command must be sent once")
+	}
+}
+
+func TestRegionFrequencyWindowsStayInsideRegion(t *testing.T) {
+	m := NewModel()
+	m.regionIndex = 1 // EU
+	for _, w := range regionFrequencyWindows(m.selectedRegion()) {
+		band, lo, hi := regions.DecodeRange(w.High, w.Low)
+		if band != regions.BandEU || lo < 0 || hi > 14 || lo > hi {
+			t.Fatalf("window outside EU: band=%d ch %d-%d", band, lo, hi)
+		}
+	}
+}
+
+func TestReaderInfoFrameReportsDrift(t *testing.T) {
+	m := NewModel()
+	m.inventoryScanTime = 0x01
+	m.inventoryAntMask = 0x01
+	info := reader18.Frame{
+		Command: reader18.CmdGetReaderInfo,
+		Status:  reader18.StatusSuccess,
+		Data:    []byte{0x02, 0x1E, 0x0C, 0x01, 0x31, 0x80, 0x1E, 0x0A, 0x01},
+	}
+
+	m.awaitingProbe = true
+	m.handleProtocolFrame(info)
+	if m.status != "Reader info received" {
+		t.Fatalf("connect probe must not be diffed, status=%q", m.status)
+	}
+
+	m.handleProtocolFrame(info)
+	if m.status != "Config drift: scan_time want=0x01 got=0x0A" {
+		t.Fatalf("unexpected drift status: %q", m.status)
+	}
+}
+
+func TestAdaptivePresetRaisesQForCrowdedRounds(t *testing.T) {
+	m := NewModel()
+	m = m.applyInventoryPreset("adaptive")
+	m.inventoryRunning = true
+
+	data := []byte{0x01, 0x00}
+	for i := 0; i < 16; i++ {
+		data[1]++
+		data = append(data, 0x04, 0xE2, 0x00, 0x00, byte(i), 0x50)
+	}
+	for i := 0; i < 4; i++ {
+		m.handleProtocolFrame(reader18.Frame{Command: reader18.CmdInventory, Status: reader18.StatusNoTag, Data: data})
+	}
+	if m.inventoryQValue != 5 {
+		t.Fatalf("expected adaptive Q=5, got %d", m.inventoryQValue)
+	}
+
+	next, _ := m.adjustInventorySetting(1)
+	if next.(Model).inventoryAdaptive != nil {
+		t.Fatal("manual Q edit must turn adaptive tuning off")
+	}
+}
+
+func TestInventoryProfileSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	t.Setenv("BOT_PROFILE_FILE", path)
+	m := NewModel()
+	m.inventoryQValue = 7
+	m.inventoryScanTime = 5
+	m.inventoryAntMask = 0x03
+
+	next, _ := m.enterProfileNameMode()
+	m = next.(Model)
+	m.input.SetValue("dock")
+	next, _ = m.updateProfileNameInput(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.inputMode != inputModeNone || m.selectedProfileName() != "dock" {
+		t.Fatalf("profile not saved: mode=%d selected=%q status=%q", m.inputMode, m.selectedProfileName(), m.status)
+	}
+
+	m = m.loadInventoryProfile(sdk.ProfileBalanced)
+	if m.inventoryQValue != 4 || m.inventoryScanTime != 1 {
+		t.Fatalf("balanced profile not loaded: q=%d scan=%d", m.inventoryQValue, m.inventoryScanTime)
+	}
+
+	reloaded := NewModel()
+	reloaded = reloaded.loadInventoryProfile("dock")
+	if reloaded.inventoryQValue != 7 || reloaded.inventoryScanTime != 5 || reloaded.inventoryAntMask != 0x03 {
+		t.Fatalf("saved profile not shared: q=%d scan=%d mask=0x%02X", reloaded.inventoryQValue, reloaded.inventoryScanTime, reloaded.inventoryAntMask)
+	}
+}
@@ -56,6 +56,9 @@ func (m Model) footerLine() string {
 	if m.inputMode == inputModeRawHex {
 		return "[Enter] Send  [Esc] Cancel  [0/b] Back  [q] Exit"
 	}
+	if m.inputMode == inputModeProfileName {
+		return "[Enter] Save Profile  [Esc] Cancel"
+	}
 
 	switch m.activeScreen {
 	case screenHome:
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"new_era_go/internal/tds"
 )
 
 func statusTag(status string) string {
@@ -38,6 +40,15 @@ func targetLabel(target byte) string {
 	return "B"
 }
 
+// tagIdentitySuffix appends the GS1 pure identity URI for TDS-encoded EPCs.
+func tagIdentitySuffix(epcHex string) string {
+	id, err := tds.DecodeHex(epcHex)
+	if err != nil {
+		return ""
+	}
+	return " id=" + id.URI()
+}
+
 func onOff(value bool) string {
 	if value {
 		return "ON"
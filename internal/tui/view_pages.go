@@ -111,12 +111,23 @@ func (m Model) controlPageLines() []string {
 	}
 	lines = append(lines, fmt.Sprintf("Inventory: %s | rounds:%d | unique-tags:%d", invState, m.inventoryRounds, m.inventoryTagTotal))
 	lines = append(lines, fmt.Sprintf("Protocol: Reader18 | addr:%s | poll:%s | cycle:%s", addr, m.inventoryInterval, m.effectiveInventoryInterval()))
+	lines = append(lines, fmt.Sprintf("RX queue: %s | dropped packets:%d", m.overflow, m.reader.Dropped()))
 	if m.lastTagEPC != "" {
 		lines = append(lines, fmt.Sprintf("Last Tag: %s | Ant:%d | RSSI:%d", trimText(m.lastTagEPC, 28), m.lastTagAntenna, m.lastTagRSSI))
 		if m.showPhaseFreq {
 			lines = append(lines, "Phase/Freq: n/a (not present in cmd 0x01 frame)")
 		}
 	}
+	if snap := m.telemetrySnap; snap.Reads > 0 {
+		lines = append(lines, fmt.Sprintf("Rate (%s): %.1f reads/s | %.2f unique/s | tags:%d", snap.Window, snap.ReadsPerSec, snap.UniquePerSec, snap.UniqueTags))
+		for _, ant := range snap.Antennas {
+			rssi := "-"
+			if ant.RSSI.Samples > 0 {
+				rssi = fmt.Sprintf("%d/%.0f/%d", ant.RSSI.Min, ant.RSSI.Avg, ant.RSSI.Max)
+			}
+			lines = append(lines, fmt.Sprintf("  Ant%d: %.1f reads/s | uniq:%d | RSSI min/avg/max:%s", ant.Antenna, ant.ReadsPerSec, ant.UniqueTags, rssi))
+		}
+	}
 	if m.lastRX != "" {
 		lines = append(lines, "Last RX: "+trimText(m.lastRX, 64))
 	}
@@ -147,8 +158,8 @@ func (m Model) inventoryPageLines() []string {
 	lines = append(lines, "")
 
 	rows := []string{
-		fmt.Sprintf("Q Value: %d", m.inventoryQValue),
-		fmt.Sprintf("Session: %d", m.inventorySession),
+		fmt.Sprintf("Q Value: %d%s", m.inventoryQValue, m.adaptiveSuffix()),
+		fmt.Sprintf("Session: %d%s", m.inventorySession, m.adaptiveSuffix()),
 		fmt.Sprintf("Target: %s", targetLabel(m.inventoryTarget)),
 		fmt.Sprintf("Scan Time (x100ms): %d", m.inventoryScanTime),
 		fmt.Sprintf("No-tag A/B Switch Count: %d", m.inventoryNoTagAB),
@@ -160,6 +171,9 @@ func (m Model) inventoryPageLines() []string {
 		"Fast Preset",
 		"Balanced Preset",
 		"Long Range Preset",
+		"Adaptive Preset (auto Q/session)",
+		fmt.Sprintf("Load Profile: %s (h/l choose)", m.selectedProfileName()),
+		"Save Profile As...",
 	}
 
 	start, end := listWindow(m.inventoryIndex, len(rows), m.inventoryViewSize())
@@ -173,12 +187,25 @@ func (m Model) inventoryPageLines() []string {
 	}
 
 	lines = append(lines, "")
+	if m.inputMode == inputModeProfileName {
+		lines = append(lines, "", "Save Profile")
+		lines = append(lines, m.input.View())
+		lines = append(lines, "Enter=save Esc=cancel")
+	}
+
 	lines = append(lines, fmt.Sprintf("Rows: %d-%d of %d", start+1, end, len(rows)))
 	lines = append(lines, "Tip: Session 2/3 + A/B switch helps for far tags")
 	lines = append(lines, "Speed tip: keep Scan Time low (1-3) for realtime reads")
 	return lines
 }
 
+func (m Model) adaptiveSuffix() string {
+	if m.inventoryAdaptive == nil {
+		return ""
+	}
+	return " (auto)"
+}
+
 func (m Model) regionsPageLines() []string {
 	lines := []string{"Regions"}
 	if len(regions.Catalog) == 0 {
@@ -203,8 +230,13 @@ func (m Model) regionsPageLines() []string {
 
 	selected := regions.Catalog[m.regionCursor]
 	lines = append(lines, "")
+	high, low := selected.Encode()
 	lines = append(lines, "Selected: "+selected.Name)
 	lines = append(lines, "Band: "+selected.Band)
+	lines = append(lines, fmt.Sprintf("Reader band %d %s [0x%02X/0x%02X]", selected.BandCode, selected.Channels(), high, low))
+	if m.regionPending {
+		lines = append(lines, "Pending: "+m.selectedRegion().Code+" is sent on next Start Reading")
+	}
 	return lines
 }
 
@@ -0,0 +1,160 @@
+package sdk
+
+import (
+	"fmt"
+	"math"
+)
+
+// Reader18 0x01 round status codes.
+const (
+	roundDone       byte = 0x01 // round finished, last frame
+	roundTimeout    byte = 0x02 // scan time ran out before all slots were read
+	roundMoreData   byte = 0x03 // more frames follow for this round
+	roundBufferFull byte = 0x04 // reader buffer full, tags were dropped
+)
+
+// Adaptive tuning bounds and steps. Q never drops to 0 so a pallet entering
+// an empty field does not start in a single slot.
+const (
+	adaptiveMinQ         = 1
+	adaptiveMaxQ         = 15
+	adaptiveStepUp       = 0.5
+	adaptiveStepDown     = 0.35
+	adaptiveStepEmpty    = 0.25
+	adaptiveStepTruncate = 1.0
+	// Yield averages that move to session 2 and back to the configured session.
+	adaptiveCrowdYield  = 16.0
+	adaptiveSparseYield = 4.0
+)
+
+// AdaptiveTuner tunes Q from round outcomes, after the Gen2
+// Q-algorithm: empty or sparse rounds lower Q, crowded or truncated rounds
+// raise it. Reader18 does not report collisions, so a round that fills most
+// of its 2^Q slots or runs out of scan time stands in for one. The Client
+// runs one when InventoryConfig.AdaptiveQ is set; raw-protocol callers such
+// as the TUI can drive their own.
+type AdaptiveTuner struct {
+	qfp         float64
+	q           byte
+	session     byte
+	baseSession byte
+	sessions    bool
+	roundTags   int
+	avgYield    float64
+}
+
+// NewAdaptiveTuner starts from cfg.QValue and cfg.Session; cfg.AdaptiveSession
+// enables session switching.
+func NewAdaptiveTuner(cfg InventoryConfig) *AdaptiveTuner {
+	q := cfg.QValue
+	if q < adaptiveMinQ {
+		q = adaptiveMinQ
+	}
+	if q > adaptiveMaxQ {
+		q = adaptiveMaxQ
+	}
+	return &AdaptiveTuner{
+		qfp:         float64(q),
+		q:           q,
+		session:     cfg.Session,
+		baseSession: cfg.Session,
+		sessions:    cfg.AdaptiveSession,
+	}
+}
+
+// Observe adds one 0x01 response frame carrying tags reads and returns the
+// decisions made when the frame closes a round.
+func (a *AdaptiveTuner) Observe(tags int, status byte) []string {
+	a.roundTags += tags
+	if status == roundMoreData {
+		return nil
+	}
+	n := a.roundTags
+	a.roundTags = 0
+	truncated := n > 0 && (status == roundTimeout || status == roundBufferFull)
+
+	slots := float64(int(1) << a.q)
+	switch {
+	case truncated:
+		a.qfp += adaptiveStepTruncate
+	case n == 0:
+		a.qfp -= adaptiveStepEmpty
+	case float64(n) >= slots*0.75:
+		a.qfp += adaptiveStepUp
+	case float64(n)*4 < slots:
+		a.qfp -= adaptiveStepDown
+	}
+	a.qfp = math.Max(adaptiveMinQ, math.Min(adaptiveMaxQ, a.qfp))
+	a.avgYield = a.avgYield*0.8 + float64(n)*0.2
+
+	var decisions []string
+	if q := byte(math.Round(a.qfp)); q != a.q {
+		reason := fmt.Sprintf("yield %d/%d", n, int(slots))
+		if truncated {
+			reason += ", truncated"
+		}
+		decisions = append(decisions, fmt.Sprintf("adaptive Q %d->%d (%s)", a.q, q, reason))
+		a.q = q
+	}
+	if a.sessions {
+		session := a.session
+		if a.avgYield >= adaptiveCrowdYield && a.session < 2 {
+			session = 2
+		} else if a.avgYield <= adaptiveSparseYield && a.session != a.baseSession {
+			session = a.baseSession
+		}
+		if session != a.session {
+			decisions = append(decisions, fmt.Sprintf("adaptive session S%d->S%d (avg yield %.1f)", a.session, session, a.avgYield))
+			a.session = session
+		}
+	}
+	return decisions
+}
+
+// Q is the Q value for the next round.
+func (a *AdaptiveTuner) Q() byte {
+	return a.q
+}
+
+// Session is the session for the next round.
+func (a *AdaptiveTuner) Session() byte {
+	return a.session
+}
+
+// observeInventoryRound feeds the adaptive controller and applies its decisions.
+func (c *Client) observeInventoryRound(tags int, status byte) {
+	c.mu.Lock()
+	if c.adaptive == nil {
+		c.mu.Unlock()
+		return
+	}
+	session := c.adaptive.session
+	decisions := c.adaptive.Observe(tags, status)
+	if c.adaptive.session != session {
+		// A new session starts from the configured target.
+		c.targetValue = c.cfg.Target
+		c.noTagHit = 0
+	}
+	c.mu.Unlock()
+
+	for _, decision := range decisions {
+		c.emitStatus(decision)
+	}
+}
+
+// roundParams returns Q and session for the next round; callers hold c.mu.
+func (c *Client) roundParams(cfg InventoryConfig) (q, session byte) {
+	if c.adaptive == nil {
+		return cfg.QValue, cfg.Session
+	}
+	return c.adaptive.q, c.adaptive.session
+}
+
+// abSwitchRounds is the no-tag streak before an A/B target flip. Adaptive
+// session switching needs flips in S2, so it falls back to 4 when unset.
+func (c *Client) abSwitchRounds() int {
+	if c.cfg.NoTagABSwitch == 0 && c.adaptive != nil && c.adaptive.sessions {
+		return 4
+	}
+	return c.cfg.NoTagABSwitch
+}
@@ -0,0 +1,90 @@
+package sdk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAdaptiveQFollowsPopulation(t *testing.T) {
+	cfg := DefaultInventoryConfig() // Q=4, 16 slots
+	cfg.AdaptiveQ = true
+	a := NewAdaptiveTuner(cfg)
+
+	// A crowded field pushes Q up until 2^Q comfortably exceeds the yield.
+	for i := 0; i < 20; i++ {
+		a.Observe(40, roundDone)
+	}
+	if a.q != 6 {
+		t.Fatalf("expected Q=6 for 40 tags, got %d", a.q)
+	}
+
+	// Rounds split over several frames count once.
+	a.Observe(30, roundMoreData)
+	if decisions := a.Observe(30, roundTimeout); len(decisions) != 1 || !strings.Contains(decisions[0], "truncated") {
+		t.Fatalf("expected truncated round to raise Q: %v", decisions)
+	}
+
+	// An empty field lowers Q but never below the floor.
+	for i := 0; i < 100; i++ {
+		a.Observe(0, roundDone)
+	}
+	if a.q != adaptiveMinQ {
+		t.Fatalf("expected Q floor %d, got %d", adaptiveMinQ, a.q)
+	}
+	if a.session != cfg.Session {
+		t.Fatalf("session must not change without AdaptiveSession")
+	}
+}
+
+func TestAdaptiveSessionSwitchesForLargePopulations(t *testing.T) {
+	cfg := DefaultInventoryConfig()
+	cfg.Session = 0
+	cfg.AdaptiveQ = true
+	cfg.AdaptiveSession = true
+	a := NewAdaptiveTuner(cfg)
+
+	var log []string
+	for i := 0; i < 30; i++ {
+		log = append(log, a.Observe(60, roundDone)...)
+	}
+	if a.session != 2 {
+		t.Fatalf("expected session 2 for a large population, got %d (%v)", a.session, log)
+	}
+	for i := 0; i < 30; i++ {
+		a.Observe(1, roundDone)
+	}
+	if a.session != 0 {
+		t.Fatalf("expected configured session back, got %d", a.session)
+	}
+}
+
+func TestAdaptiveQDrivesInventoryCommand(t *testing.T) {
+	c := NewClient()
+	cfg := DefaultInventoryConfig()
+	cfg.AdaptiveQ = true
+	c.SetInventoryConfig(cfg)
+	c.inventoryOn = true
+	c.adaptive = NewAdaptiveTuner(c.cfg)
+
+	for i := 0; i < 4; i++ {
+		c.observeInventoryRound(16, roundDone)
+	}
+	if c.Stats().QValue != 5 {
+		t.Fatalf("expected Q=5, got %d", c.Stats().QValue)
+	}
+	inventory, _, _, ok := c.nextInventoryCommand()
+	if !ok || inventory[3] != 5 {
+		t.Fatalf("inventory must carry adapted Q: % X", inventory)
+	}
+	select {
+	case ev := <-c.Statuses():
+		for ev.Message == "inventory config updated" {
+			ev = <-c.Statuses()
+		}
+		if !strings.HasPrefix(ev.Message, "adaptive Q 4->5") {
+			t.Fatalf("unexpected status: %q", ev.Message)
+		}
+	default:
+		t.Fatalf("expected adaptive status event")
+	}
+}
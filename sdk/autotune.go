@@ -0,0 +1,253 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AutoTuneOptions controls an auto-tune sweep. Zero values use defaults.
+type AutoTuneOptions struct {
+	// Reference is the set of EPCs placed in the read zone for the sweep.
+	Reference []string
+	Powers    []byte
+	ScanTimes []byte
+	// Dwell is how long each candidate reads; capped by the telemetry window.
+	Dwell time.Duration
+	// Apply keeps the recommended config instead of restoring the old one.
+	Apply bool
+}
+
+// Defaults used by AutoTune for empty options.
+var (
+	DefaultTunePowers    = []byte{10, 15, 20, 25, 30}
+	DefaultTuneScanTimes = []byte{1, 3, 8}
+)
+
+// DefaultTuneDwell is the per-candidate read time.
+const DefaultTuneDwell = 3 * time.Second
+
+// TuneResult is what one power/scan-time candidate achieved on the reference set.
+type TuneResult struct {
+	OutputPower byte
+	ScanTime    byte
+	Expected    int
+	Found       int
+	// Stray counts distinct non-reference EPCs read; fewer is better.
+	Stray       int
+	ReadsPerSec float64
+	AvgRSSI     float64
+	// AntennaFound is the number of reference tags each antenna saw.
+	AntennaFound map[int]int
+}
+
+func (r TuneResult) String() string {
+	return fmt.Sprintf("power=%d scan=%d found=%d/%d stray=%d reads/s=%.1f rssi=%.0f",
+		r.OutputPower, r.ScanTime, r.Found, r.Expected, r.Stray, r.ReadsPerSec, r.AvgRSSI)
+}
+
+// better ranks by reference coverage, then fewer stray tags, then read
+// rate, then lower power.
+func (r TuneResult) better(o TuneResult) bool {
+	if r.Found != o.Found {
+		return r.Found > o.Found
+	}
+	if r.Stray != o.Stray {
+		return r.Stray < o.Stray
+	}
+	if r.ReadsPerSec != o.ReadsPerSec {
+		return r.ReadsPerSec > o.ReadsPerSec
+	}
+	return r.OutputPower < o.OutputPower
+}
+
+// AutoTuneReport holds every measured candidate, best first.
+type AutoTuneReport struct {
+	Results     []TuneResult
+	Best        TuneResult
+	Recommended InventoryConfig
+	Applied     bool
+}
+
+// ScoreTune measures one telemetry snapshot against the reference EPCs.
+func ScoreTune(snap TelemetrySnapshot, reference []string) TuneResult {
+	want := make(map[string]struct{}, len(reference))
+	for _, epc := range reference {
+		want[strings.ToUpper(strings.TrimSpace(epc))] = struct{}{}
+	}
+	res := TuneResult{Expected: len(want), AntennaFound: make(map[int]int)}
+	var rssiSum float64
+	var rssiTags int
+	for _, tag := range snap.Tags {
+		if _, ok := want[tag.EPC]; !ok {
+			res.Stray++
+			continue
+		}
+		res.Found++
+		res.ReadsPerSec += tag.ReadsPerSec
+		if tag.RSSI.Samples > 0 {
+			rssiSum += tag.RSSI.Avg
+			rssiTags++
+		}
+		for _, antenna := range tag.Antennas {
+			res.AntennaFound[antenna]++
+		}
+	}
+	if rssiTags > 0 {
+		res.AvgRSSI = rssiSum / float64(rssiTags)
+	}
+	return res
+}
+
+// RecommendTune picks the best result and derives per-antenna power: each
+// antenna gets the lowest swept power that reached its best coverage at
+// the winning scan time. base supplies every other setting.
+func RecommendTune(results []TuneResult, base InventoryConfig) (TuneResult, InventoryConfig) {
+	if len(results) == 0 {
+		return TuneResult{}, base
+	}
+	best := results[0]
+	for _, r := range results[1:] {
+		if r.better(best) {
+			best = r
+		}
+	}
+
+	cfg := cloneInventoryConfig(base)
+	cfg.OutputPower = best.OutputPower
+	cfg.ScanTime = best.ScanTime
+	cfg.PerAntennaPower = nil
+
+	antennas := antennaCount(cfg.AntennaMask)
+	if antennas < 2 {
+		return best, cfg
+	}
+	powers := make([]byte, antennas)
+	for i := range powers {
+		antenna := i + 1
+		top, power := 0, best.OutputPower
+		for _, r := range results {
+			if r.ScanTime != best.ScanTime {
+				continue
+			}
+			found := r.AntennaFound[antenna]
+			if found > top || (found == top && found > 0 && r.OutputPower < power) {
+				top, power = found, r.OutputPower
+			}
+		}
+		powers[i] = power
+	}
+	cfg.PerAntennaPower = powers
+	return best, cfg
+}
+
+// antennaCount is the highest enabled port in mask (ports are 1-based).
+func antennaCount(mask byte) int {
+	n := 0
+	for i := 0; i < 8; i++ {
+		if mask&(1<<i) != 0 {
+			n = i + 1
+		}
+	}
+	return n
+}
+
+// AutoTune sweeps output power and scan time against opts.Reference and
+// reports the best profile. Each candidate restarts inventory, reads for
+// the dwell time and is scored from telemetry. Afterwards the previous
+// config is restored, or the recommended one kept when opts.Apply is set;
+// inventory is left running if it was running before.
+func (c *Client) AutoTune(ctx context.Context, opts AutoTuneOptions) (AutoTuneReport, error) {
+	if len(opts.Reference) == 0 {
+		return AutoTuneReport{}, fmt.Errorf("auto-tune needs reference EPCs")
+	}
+	if !c.transport.IsConnected() {
+		return AutoTuneReport{}, fmt.Errorf("not connected")
+	}
+	powers := opts.Powers
+	if len(powers) == 0 {
+		powers = DefaultTunePowers
+	}
+	scanTimes := opts.ScanTimes
+	if len(scanTimes) == 0 {
+		scanTimes = DefaultTuneScanTimes
+	}
+	dwell := opts.Dwell
+	if dwell <= 0 {
+		dwell = DefaultTuneDwell
+	}
+	if dwell > DefaultTelemetryWindow {
+		dwell = DefaultTelemetryWindow
+	}
+
+	base := c.InventoryConfig()
+	c.mu.RLock()
+	wasRunning := c.inventoryOn
+	c.mu.RUnlock()
+	if err := c.StopInventory(); err != nil {
+		return AutoTuneReport{}, err
+	}
+
+	var results []TuneResult
+	var runErr error
+sweep:
+	for _, scanTime := range scanTimes {
+		for _, power := range powers {
+			cfg := cloneInventoryConfig(base)
+			cfg.OutputPower = power
+			cfg.ScanTime = scanTime
+			cfg.PerAntennaPower = nil
+			cfg.AdaptiveQ = false
+			cfg.AdaptiveSession = false
+			result, err := c.measureTune(ctx, cfg, dwell, opts.Reference)
+			if err != nil {
+				runErr = err
+				break sweep
+			}
+			results = append(results, result)
+			c.emitStatus("auto-tune " + result.String())
+		}
+	}
+
+	report := AutoTuneReport{Results: results}
+	report.Best, report.Recommended = RecommendTune(results, base)
+	sort.SliceStable(report.Results, func(i, j int) bool { return report.Results[i].better(report.Results[j]) })
+
+	final := base
+	if opts.Apply && runErr == nil && len(results) > 0 {
+		final = report.Recommended
+		report.Applied = true
+	}
+	c.SetInventoryConfig(final)
+	if wasRunning && ctx.Err() == nil {
+		if err := c.StartInventory(ctx); err != nil && runErr == nil {
+			runErr = fmt.Errorf("restart inventory: %w", err)
+		}
+	}
+	return report, runErr
+}
+
+func (c *Client) measureTune(ctx context.Context, cfg InventoryConfig, dwell time.Duration, reference []string) (TuneResult, error) {
+	c.SetInventoryConfig(cfg)
+	if err := c.StartInventory(ctx); err != nil {
+		return TuneResult{}, err
+	}
+	timer := time.NewTimer(dwell)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		_ = c.StopInventory()
+		return TuneResult{}, ctx.Err()
+	case <-timer.C:
+	}
+	snap := c.telemetry.Snapshot()
+	if err := c.StopInventory(); err != nil {
+		return TuneResult{}, err
+	}
+	result := ScoreTune(snap, reference)
+	result.OutputPower = cfg.OutputPower
+	result.ScanTime = cfg.ScanTime
+	return result, nil
+}
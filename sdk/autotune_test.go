@@ -0,0 +1,39 @@
+package sdk
+
+import "testing"
+
+func TestScoreTuneCountsReferenceAndStrayTags(t *testing.T) {
+	snap := TelemetrySnapshot{Tags: []TagStats{
+		{EPC: "E2000001", ReadsPerSec: 4, Antennas: []int{1, 2}, RSSI: RSSIStats{Samples: 4, Avg: 60}},
+		{EPC: "E2000002", ReadsPerSec: 2, Antennas: []int{2}, RSSI: RSSIStats{Samples: 2, Avg: 40}},
+		{EPC: "E2FFFFFF", ReadsPerSec: 9, Antennas: []int{1}},
+	}}
+	res := ScoreTune(snap, []string{"e2000001", "E2000002", "E2000003"})
+	if res.Expected != 3 || res.Found != 2 || res.Stray != 1 || res.ReadsPerSec != 6 || res.AvgRSSI != 50 {
+		t.Fatalf("unexpected score: %+v", res)
+	}
+	if res.AntennaFound[1] != 1 || res.AntennaFound[2] != 2 {
+		t.Fatalf("unexpected antenna coverage: %v", res.AntennaFound)
+	}
+}
+
+func TestRecommendTunePrefersCoverageThenLowPower(t *testing.T) {
+	results := []TuneResult{
+		{OutputPower: 10, ScanTime: 1, Found: 2, AntennaFound: map[int]int{1: 2, 2: 0}},
+		{OutputPower: 20, ScanTime: 1, Found: 4, ReadsPerSec: 8, AntennaFound: map[int]int{1: 2, 2: 3}},
+		{OutputPower: 30, ScanTime: 1, Found: 4, Stray: 3, ReadsPerSec: 12, AntennaFound: map[int]int{1: 2, 2: 3}},
+		{OutputPower: 30, ScanTime: 3, Found: 3, AntennaFound: map[int]int{1: 2, 2: 2}},
+	}
+	base := DefaultInventoryConfig()
+	base.AntennaMask = 0x03
+	best, cfg := RecommendTune(results, base)
+	if best.OutputPower != 20 || best.ScanTime != 1 {
+		t.Fatalf("expected power 20 without stray reads, got %s", best)
+	}
+	if cfg.OutputPower != 20 || cfg.ScanTime != 1 || len(cfg.PerAntennaPower) != 2 {
+		t.Fatalf("unexpected recommended config: %+v", cfg)
+	}
+	if cfg.PerAntennaPower[0] != 10 || cfg.PerAntennaPower[1] != 20 {
+		t.Fatalf("expected per-antenna power [10 20], got %v", cfg.PerAntennaPower)
+	}
+}
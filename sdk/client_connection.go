@@ -13,7 +13,17 @@ func (c *Client) Connect(ctx context.Context, endpoint Endpoint, timeout time.Du
 	if timeout <= 0 {
 		timeout = 3 * time.Second
 	}
-	internalEndpoint := reader.Endpoint{Host: endpoint.Host, Port: endpoint.Port}
+	internalEndpoint := reader.Endpoint{Host: endpoint.Host, Port: endpoint.Port, Serial: endpoint.Serial, Replay: endpoint.Replay}
+	if internalEndpoint.IsSerial() {
+		// On an RS-485 bus only frames from the configured reader address
+		// belong to this client; auto-address mode takes every frame.
+		c.mu.RLock()
+		internalEndpoint.Serial.Address = reader.BroadcastAddress
+		if !c.cfg.AutoAddress {
+			internalEndpoint.Serial.Address = c.cfg.ReaderAddress
+		}
+		c.mu.RUnlock()
+	}
 	if err := c.transport.Connect(ctx, internalEndpoint, timeout); err != nil {
 		return err
 	}
@@ -21,6 +31,12 @@ func (c *Client) Connect(ctx context.Context, endpoint Endpoint, timeout time.Du
 	return nil
 }
 
+// SetRecorder records the raw traffic of every later connection; nil stops
+// recording.
+func (c *Client) SetRecorder(r *Recorder) {
+	c.transport.SetRecorder(r)
+}
+
 func (c *Client) Reconnect(ctx context.Context, endpoint Endpoint, timeout time.Duration) error {
 	_ = c.StopInventory()
 	_ = c.transport.Disconnect()
@@ -3,7 +3,10 @@ package sdk
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	reader18 "new_era_go/internal/protocol/reader18"
 	"new_era_go/internal/reader"
 )
 
@@ -16,8 +19,7 @@ type Client struct {
 	inventoryOn   bool
 	inventoryDone chan struct{}
 	cancelInv     context.CancelFunc
-	seen          map[string]struct{}
-	parserBuffer  []byte
+	dedup         *Deduper
 	rounds        int
 	uniqueTags    int
 	noTagHit      int
@@ -25,27 +27,98 @@ type Client struct {
 	readerAddr    byte
 	targetValue   byte
 	lastTagEPC    string
+	telemetry     *Telemetry
+	filter        *TagFilter
+	waiters       map[byte]chan reader18.Frame
+	adaptive      *AdaptiveTuner
+	presence      *PresenceTracker
+	// txMu keeps inventory rounds and live config updates from interleaving.
+	txMu sync.Mutex
+	// roundOpen counts the final replies the round in flight still owes;
+	// roundEnd is closed when it reaches zero. Both are guarded by mu.
+	roundOpen int
+	roundEnd  chan struct{}
+	// rxMu guards decoder only; frames are handled after it is released so
+	// a blocked tag send never holds it. The parse counters mirror the
+	// decoder's for Stats.
+	rxMu        sync.Mutex
+	decoder     *reader18.Decoder
+	crcErrors   atomic.Int64
+	resyncBytes atomic.Int64
+	truncations atomic.Int64
+	// consumeMu serializes consumePacket, which reuses rxArena (the raw
+	// bytes of one packet's frames) and rxFrames between calls.
+	consumeMu sync.Mutex
+	rxArena   []byte
+	rxFrames  []reader18.Frame
+	rxEnds    []int
 
-	tags     chan TagEvent
-	statuses chan StatusEvent
-	errs     chan error
+	policy OverflowPolicy
+	// invStop is closed when the running inventory stops; it releases a
+	// blocked tag send. It starts closed, so nothing blocks before the
+	// first StartInventory.
+	invStop <-chan struct{}
+
+	subMu   sync.Mutex
+	onTag   map[int]func(TagEvent)
+	nextSub int
+	fanout  TagFanout
+
+	tags chan TagEvent
+	// tagsClaimed is set by the first Tags call. Before that, tags is only
+	// fed while no Subscribe channel exists, so a Subscribe-only consumer
+	// is never stalled by, or charged drops for, a channel nobody reads.
+	tagsClaimed atomic.Bool
+	statuses    chan StatusEvent
+	errs        chan error
+	// presenceCh carries PresenceTracker output; see SetPresence.
+	presenceCh chan PresenceEvent
+
+	droppedTags     atomic.Uint64
+	droppedStatuses atomic.Uint64
+	droppedErrors   atomic.Uint64
+	droppedPresence atomic.Uint64
+	// emptyEPC counts reads without an EPC.
+	emptyEPC atomic.Uint64
+
+	// tids maps EPC to its TID state for the session and tidQueue lists the
+	// EPCs whose reads wait for a TID read; both guarded by mu.
+	tids          map[string]*tidEntry
+	tidQueue      []string
+	tidReadErrors atomic.Uint64
 }
 
 func NewClient() *Client {
 	cfg := normalizeConfig(DefaultInventoryConfig())
 	return &Client{
 		transport:   reader.NewClient(),
+		decoder:     reader18.NewDecoder(),
 		cfg:         cfg,
-		seen:        make(map[string]struct{}),
+		dedup:       NewDeduper(0, 0),
+		telemetry:   NewTelemetry(DefaultTelemetryWindow),
 		readerAddr:  cfg.ReaderAddress,
 		targetValue: cfg.Target,
 		tags:        make(chan TagEvent, 256),
 		statuses:    make(chan StatusEvent, 256),
 		errs:        make(chan error, 64),
+		presenceCh:  make(chan PresenceEvent, 256),
+		invStop:     stoppedInventory,
 	}
 }
 
+// stoppedInventory is a closed channel standing in for invStop while no
+// inventory has run.
+var stoppedInventory = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// Tags returns the client's default tag channel. It is fed once Tags has
+// been called, or as long as there is no Subscribe channel; a consumer
+// that only uses Subscribe never has reads queued (or dropped) here.
 func (c *Client) Tags() <-chan TagEvent {
+	c.tagsClaimed.Store(true)
 	return c.tags
 }
 
@@ -66,7 +139,7 @@ func (c *Client) Endpoint() (Endpoint, bool) {
 	if !ok {
 		return Endpoint{}, false
 	}
-	return Endpoint{Host: endpoint.Host, Port: endpoint.Port}, true
+	return Endpoint{Host: endpoint.Host, Port: endpoint.Port, Serial: endpoint.Serial, Replay: endpoint.Replay}, true
 }
 
 func (c *Client) InventoryConfig() InventoryConfig {
@@ -85,3 +158,45 @@ func (c *Client) SetInventoryConfig(cfg InventoryConfig) {
 	c.mu.Unlock()
 	c.emitStatus("inventory config updated")
 }
+
+// SetRegion stores a region preset and, when connected, sends it to the
+// reader right away; otherwise it is applied by the next StartInventory.
+func (c *Client) SetRegion(code string) error {
+	c.mu.Lock()
+	cfg := c.cfg
+	if err := cfg.SetRegion(code); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.cfg = cfg
+	addr := c.readerAddr
+	c.mu.Unlock()
+
+	if !cfg.RegionSet || !c.transport.IsConnected() {
+		return nil
+	}
+	return c.transport.SendRaw(reader18.SetFrequencyRangeCommand(addr, cfg.RegionHigh, cfg.RegionLow), 2*time.Second)
+}
+
+// SetDedup sets the window in which repeat reads of an EPC are not
+// IsNew and bounds how many EPCs are remembered. A zero window keeps the
+// old behaviour: once per inventory session. It takes effect immediately
+// and forgets the reads seen so far.
+func (c *Client) SetDedup(window time.Duration, maxEntries int) {
+	c.mu.Lock()
+	c.dedup = NewDeduper(window, maxEntries)
+	c.mu.Unlock()
+}
+
+// SetTagFilter installs read filtering rules; rejected reads are not emitted
+// and do not count as unique tags. Nil disables filtering.
+func (c *Client) SetTagFilter(filter *TagFilter) {
+	c.mu.Lock()
+	c.filter = filter
+	c.mu.Unlock()
+}
+
+// Telemetry returns rolling read-rate and RSSI statistics for the current inventory.
+func (c *Client) Telemetry() TelemetrySnapshot {
+	return c.telemetry.Snapshot()
+}
@@ -1,11 +1,88 @@
 package sdk
 
-import "time"
+import (
+	"time"
+
+	"new_era_go/internal/reader"
+)
+
+// OverflowPolicy decides what happens when the Tags channel is full.
+type OverflowPolicy = reader.OverflowPolicy
+
+// Overflow policies for SetOverflowPolicy.
+const (
+	OverflowDropNewest = reader.OverflowDropNewest
+	OverflowDropOldest = reader.OverflowDropOldest
+	OverflowBlock      = reader.OverflowBlock
+)
+
+// ParseOverflowPolicy reads "block", "drop-oldest" or "drop-newest".
+func ParseOverflowPolicy(raw string) (OverflowPolicy, error) {
+	return reader.ParseOverflowPolicy(raw)
+}
+
+// SetOverflowPolicy applies p to the Tags channel and to the raw packet
+// queue below it. With OverflowBlock a slow Tags consumer slows decoding
+// and, through the transport, the reader link; a blocked send gives up
+// when inventory stops. Statuses and Errors always drop the newest event.
+func (c *Client) SetOverflowPolicy(p OverflowPolicy) {
+	c.mu.Lock()
+	c.policy = p
+	c.mu.Unlock()
+	c.transport.SetOverflowPolicy(p)
+}
+
+// OnTag registers fn to be called for every tag event, before it is
+// queued on Tags. Callbacks run on the receive path and are never dropped,
+// so a slow callback slows reading instead of losing events. The returned
+// func removes the callback.
+func (c *Client) OnTag(fn func(TagEvent)) (cancel func()) {
+	c.subMu.Lock()
+	c.nextSub++
+	id := c.nextSub
+	if c.onTag == nil {
+		c.onTag = make(map[int]func(TagEvent))
+	}
+	c.onTag[id] = fn
+	c.subMu.Unlock()
+	return func() {
+		c.subMu.Lock()
+		delete(c.onTag, id)
+		c.subMu.Unlock()
+	}
+}
+
+// Subscribe returns an independent channel of the tag events matching
+// filter, alongside Tags and other subscribers; every subscriber sees every
+// matching read. Its buffer follows the client's overflow policy and losses
+// count in Stats.DroppedTags. cancel closes the channel. Unless Tags has
+// been called, Tags stops being fed while subscribers exist.
+func (c *Client) Subscribe(filter SubscribeFilter) (<-chan TagEvent, func()) {
+	return c.fanout.Subscribe(filter)
+}
 
 func (c *Client) emitTag(event TagEvent) {
-	select {
-	case c.tags <- event:
-	default:
+	var callbacks []func(TagEvent)
+	c.subMu.Lock()
+	for _, fn := range c.onTag {
+		callbacks = append(callbacks, fn)
+	}
+	c.subMu.Unlock()
+	for _, fn := range callbacks {
+		fn(event)
+	}
+
+	c.mu.RLock()
+	policy, stop := c.policy, c.invStop
+	c.mu.RUnlock()
+	if lost := c.fanout.Publish(event, policy, stop); lost > 0 {
+		c.droppedTags.Add(uint64(lost))
+	}
+	if !c.tagsClaimed.Load() && c.fanout.Len() > 0 {
+		return
+	}
+	if !reader.Offer(c.tags, event, policy, stop) {
+		c.droppedTags.Add(1)
 	}
 }
 
@@ -13,6 +90,7 @@ func (c *Client) emitStatus(message string) {
 	select {
 	case c.statuses <- StatusEvent{When: time.Now(), Message: message}:
 	default:
+		c.droppedStatuses.Add(1)
 	}
 }
 
@@ -23,5 +101,6 @@ func (c *Client) emitErr(err error) {
 	select {
 	case c.errs <- err:
 	default:
+		c.droppedErrors.Add(1)
 	}
 }
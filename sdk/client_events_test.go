@@ -0,0 +1,184 @@
+package sdk
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+func TestOnTagSeesEveryEventWhenTagsIsFull(t *testing.T) {
+	c := NewClient()
+	seen := 0
+	cancel := c.OnTag(func(TagEvent) { seen++ })
+
+	const events = 300
+	for i := 0; i < events; i++ {
+		c.emitTag(TagEvent{EPC: fmt.Sprintf("E2%04X", i)})
+	}
+	if seen != events {
+		t.Fatalf("callback saw %d of %d events", seen, events)
+	}
+	if got, want := c.Stats().DroppedTags, uint64(events-cap(c.tags)); got != want {
+		t.Fatalf("dropped tags = %d, want %d", got, want)
+	}
+	if ev := <-c.Tags(); ev.EPC != "E20000" {
+		t.Fatalf("drop-newest should keep the oldest event, got %s", ev.EPC)
+	}
+
+	cancel()
+	c.emitTag(TagEvent{EPC: "E2FFFF"})
+	if seen != events {
+		t.Fatal("cancelled callback still called")
+	}
+}
+
+func TestDropOldestKeepsLatestTags(t *testing.T) {
+	c := NewClient()
+	c.SetOverflowPolicy(OverflowDropOldest)
+	for i := 0; i <= cap(c.tags); i++ {
+		c.emitTag(TagEvent{EPC: fmt.Sprintf("E2%04X", i)})
+	}
+	if ev := <-c.Tags(); ev.EPC != "E20001" {
+		t.Fatalf("head after drop-oldest = %s", ev.EPC)
+	}
+	if c.Stats().DroppedTags != 1 {
+		t.Fatalf("dropped tags = %d", c.Stats().DroppedTags)
+	}
+}
+
+func TestBlockPolicyReleasesWhenInventoryStops(t *testing.T) {
+	c := NewClient()
+	c.SetOverflowPolicy(OverflowBlock)
+	stop := make(chan struct{})
+	c.invStop = stop
+	for i := 0; i < cap(c.tags); i++ {
+		c.emitTag(TagEvent{EPC: "E2"})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.emitTag(TagEvent{EPC: "E3"})
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("block policy returned while Tags was full")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("blocked emit not released by stop")
+	}
+	if c.Stats().DroppedTags != 1 {
+		t.Fatalf("dropped tags = %d", c.Stats().DroppedTags)
+	}
+}
+
+func TestBlockedTagSendDoesNotHoldStats(t *testing.T) {
+	c := NewClient()
+	c.SetOverflowPolicy(OverflowBlock)
+	stop := make(chan struct{})
+	c.invStop = stop
+	for i := 0; i < cap(c.tags); i++ {
+		c.emitTag(TagEvent{EPC: "E2"})
+	}
+
+	data := []byte{reader18.StatusNoTag, 0x01, 0x01, 0x02, 0xE3, 0x01, 0x50}
+	done := make(chan struct{})
+	go func() {
+		c.consumePacket(reader18.BuildCommand(0x00, reader18.CmdInventory, data))
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("block policy returned while Tags was full")
+	case <-time.After(20 * time.Millisecond):
+	}
+	stats := make(chan Stats)
+	go func() { stats <- c.Stats() }()
+	select {
+	case <-stats:
+	case <-time.After(time.Second):
+		t.Fatal("Stats blocked behind a blocked tag send")
+	}
+	close(stop)
+	<-done
+}
+
+func TestBlockPolicyDoesNotBlockBeforeInventory(t *testing.T) {
+	c := NewClient()
+	c.SetOverflowPolicy(OverflowBlock)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i <= cap(c.tags); i++ {
+			c.emitTag(TagEvent{EPC: "E2"})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("tag send blocked with no inventory running")
+	}
+	if c.Stats().DroppedTags != 1 {
+		t.Fatalf("dropped tags = %d", c.Stats().DroppedTags)
+	}
+}
+
+func TestSubscribersAndTagsEachSeeEveryRead(t *testing.T) {
+	c := NewClient()
+	a, cancelA := c.Subscribe(SubscribeFilter{})
+	b, cancelB := c.Subscribe(SubscribeFilter{EPCPrefixes: []string{"e2"}})
+	defer cancelA()
+	defer cancelB()
+	// Tags is fed next to subscribers only once it has been asked for.
+	tags := c.Tags()
+
+	c.emitTag(TagEvent{EPC: "E2000001"})
+	c.emitTag(TagEvent{EPC: "30000002"})
+
+	for _, ch := range []<-chan TagEvent{tags, a} {
+		if first, second := <-ch, <-ch; first.EPC != "E2000001" || second.EPC != "30000002" {
+			t.Fatalf("got %s,%s", first.EPC, second.EPC)
+		}
+	}
+	if ev := <-b; ev.EPC != "E2000001" || len(b) != 0 {
+		t.Fatalf("prefix subscriber got %s (+%d)", ev.EPC, len(b))
+	}
+}
+
+func TestSubscribeOnlyConsumerDoesNotFeedTags(t *testing.T) {
+	c := NewClient()
+	c.SetOverflowPolicy(OverflowBlock)
+	c.invStop = make(chan struct{})
+	ch, cancel := c.Subscribe(SubscribeFilter{Buffer: 1024})
+	defer cancel()
+
+	// Nobody reads Tags: under block it must neither stall nor drop.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 2*cap(c.tags); i++ {
+			c.emitTag(TagEvent{EPC: fmt.Sprintf("E2%04X", i)})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("unread Tags stalled a Subscribe-only consumer")
+	}
+	if len(ch) != 2*cap(c.tags) || len(c.tags) != 0 || c.Stats().DroppedTags != 0 {
+		t.Fatalf("subscriber=%d tags=%d dropped=%d", len(ch), len(c.tags), c.Stats().DroppedTags)
+	}
+
+	// Once Tags is asked for it is fed next to the subscribers.
+	tags := c.Tags()
+	c.emitTag(TagEvent{EPC: "E2FFFF"})
+	if ev := <-tags; ev.EPC != "E2FFFF" {
+		t.Fatalf("claimed Tags got %+v", ev)
+	}
+}
@@ -14,8 +14,12 @@ func (c *Client) ApplyInventoryConfig(ctx context.Context) error {
 	}
 
 	cfg, addr := c.snapshotConfig()
+	workMode := reader18.SetWorkModeCommand(addr, reader18.WorkModePayload(cfg.WorkMode, cfg.StreamTagTime))
+	streaming := reader18.IsStreamingMode(cfg.WorkMode)
 	commands := make([][]byte, 0, 7)
-	commands = append(commands, reader18.SetWorkModeCommand(addr, []byte{0x00}))
+	if !streaming {
+		commands = append(commands, workMode)
+	}
 	if cfg.RegionSet {
 		commands = append(commands, reader18.SetFrequencyRangeCommand(addr, cfg.RegionHigh, cfg.RegionLow))
 	}
@@ -28,6 +32,10 @@ func (c *Client) ApplyInventoryConfig(ctx context.Context) error {
 	}
 	// Always send global power as fallback after optional per-antenna settings.
 	commands = append(commands, reader18.SetOutputPowerCommand(addr, cfg.OutputPower))
+	// A streaming reader starts sending tags right away, so switch it last.
+	if streaming {
+		commands = append(commands, workMode)
+	}
 
 	for _, command := range commands {
 		select {
@@ -48,6 +56,11 @@ func (c *Client) StartInventory(ctx context.Context) error {
 		return fmt.Errorf("not connected")
 	}
 
+	c.rxMu.Lock()
+	c.decoder.Discard()
+	c.storeParseStatsLocked()
+	c.rxMu.Unlock()
+
 	c.mu.Lock()
 	if c.inventoryOn {
 		c.mu.Unlock()
@@ -55,19 +68,29 @@ func (c *Client) StartInventory(ctx context.Context) error {
 	}
 	c.inventoryOn = true
 	c.inventoryDone = make(chan struct{})
-	c.seen = make(map[string]struct{})
-	c.parserBuffer = nil
+	c.dedup.Reset()
+	c.resetTIDsLocked()
 	c.rounds = 0
 	c.uniqueTags = 0
 	c.noTagHit = 0
 	c.antIdx = 0
 	c.lastTagEPC = ""
+	c.telemetry.Reset()
+	presence := c.presence
+	if presence != nil {
+		presence.Reset()
+	}
 	c.targetValue = c.cfg.Target
+	c.adaptive = nil
+	if c.cfg.AdaptiveQ {
+		c.adaptive = NewAdaptiveTuner(c.cfg)
+	}
 	if c.readerAddr == 0 {
 		c.readerAddr = c.cfg.ReaderAddress
 	}
 	invCtx, cancel := context.WithCancel(ctx)
 	c.cancelInv = cancel
+	c.invStop = invCtx.Done()
 	done := c.inventoryDone
 	c.mu.Unlock()
 
@@ -85,7 +108,17 @@ func (c *Client) StartInventory(ctx context.Context) error {
 
 	c.emitStatus("inventory started")
 
-	go c.inventoryRun(invCtx)
+	c.mu.RLock()
+	streaming := reader18.IsStreamingMode(c.cfg.WorkMode)
+	c.mu.RUnlock()
+	if presence != nil {
+		go c.presenceRun(invCtx, presence)
+	}
+	if streaming {
+		go c.streamRun(invCtx)
+	} else {
+		go c.inventoryRun(invCtx)
+	}
 	return nil
 }
 
@@ -114,13 +147,28 @@ func (c *Client) StopInventory() error {
 func (c *Client) Stats() Stats {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	q, session := c.roundParams(c.cfg)
 	return Stats{
+		QValue:      q,
+		Session:     session,
 		Running:     c.inventoryOn,
 		Rounds:      c.rounds,
 		UniqueTags:  c.uniqueTags,
 		LastTagEPC:  c.lastTagEPC,
 		ReaderAddr:  c.readerAddr,
 		TargetValue: c.targetValue,
+
+		CRCErrors:         int(c.crcErrors.Load()),
+		ResyncBytes:       int(c.resyncBytes.Load()),
+		BufferTruncations: int(c.truncations.Load()),
+
+		DroppedPackets:  c.transport.Dropped(),
+		DroppedTags:     c.droppedTags.Load(),
+		DroppedStatuses: c.droppedStatuses.Load(),
+		DroppedErrors:   c.droppedErrors.Load(),
+		DroppedPresence: c.droppedPresence.Load(),
+		EmptyEPCReads:   c.emptyEPC.Load(),
+		TIDReadErrors:   c.tidReadErrors.Load(),
 	}
 }
 
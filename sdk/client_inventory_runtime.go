@@ -12,7 +12,12 @@ import (
 
 func (c *Client) inventoryRun(ctx context.Context) {
 	defer c.finishInventoryRun()
+	go c.inventoryTxLoop(ctx)
+	c.receiveLoop(ctx)
+}
 
+// receiveLoop parses reader packets until ctx ends or the transport fails.
+func (c *Client) receiveLoop(ctx context.Context) {
 	packets := c.transport.Packets()
 	errorsCh := c.transport.Errors()
 	if packets == nil {
@@ -24,8 +29,6 @@ func (c *Client) inventoryRun(ctx context.Context) {
 		return
 	}
 
-	go c.inventoryTxLoop(ctx)
-
 	for {
 		select {
 		case <-ctx.Done():
@@ -51,22 +54,10 @@ func (c *Client) inventoryRun(ctx context.Context) {
 
 func (c *Client) inventoryTxLoop(ctx context.Context) {
 	for {
-		command, single, interval, ok := c.nextInventoryCommand()
+		interval, ok := c.sendInventoryRound()
 		if !ok {
 			return
 		}
-		if err := c.transport.SendRaw(command, 2*time.Second); err != nil {
-			c.emitErr(err)
-			c.stopInventoryAsync()
-			return
-		}
-		if single != nil {
-			if err := c.transport.SendRaw(single, 2*time.Second); err != nil {
-				c.emitErr(err)
-				c.stopInventoryAsync()
-				return
-			}
-		}
 
 		timer := time.NewTimer(interval)
 		select {
@@ -75,24 +66,134 @@ func (c *Client) inventoryTxLoop(ctx context.Context) {
 			return
 		case <-timer.C:
 		}
+		c.readHeldTIDs(ctx)
 	}
 }
 
-func (c *Client) consumePacket(data []byte) {
+// sendInventoryRound sends the next round and returns the wait before the
+// following one; false means inventory is over.
+func (c *Client) sendInventoryRound() (time.Duration, bool) {
+	c.txMu.Lock()
+	defer c.txMu.Unlock()
+	command, single, interval, ok := c.nextInventoryCommand()
+	if !ok {
+		return 0, false
+	}
+	replies := 1
+	if single != nil {
+		replies++
+	}
+	c.beginRound(replies)
+	for _, packet := range [][]byte{command, single} {
+		if packet == nil {
+			continue
+		}
+		if err := c.transport.SendRaw(packet, 2*time.Second); err != nil {
+			c.emitErr(err)
+			c.stopInventoryAsync()
+			return 0, false
+		}
+	}
+	return interval, true
+}
+
+// roundReplyMargin is how long past its scan time a round's last frame may
+// still arrive.
+const roundReplyMargin = time.Second
+
+// beginRound opens a round that ends after replies final frames.
+func (c *Client) beginRound(replies int) {
 	c.mu.Lock()
-	c.parserBuffer = append(c.parserBuffer, data...)
-	if len(c.parserBuffer) > 8192 {
-		c.parserBuffer = append([]byte{}, c.parserBuffer[len(c.parserBuffer)-4096:]...)
+	c.closeRoundLocked()
+	c.roundOpen = replies
+	c.roundEnd = make(chan struct{})
+	c.mu.Unlock()
+}
+
+// roundReply notes one final frame of the round in flight.
+func (c *Client) roundReply() {
+	c.mu.Lock()
+	if c.roundOpen > 0 {
+		c.roundOpen--
+		if c.roundOpen == 0 {
+			c.closeRoundLocked()
+		}
 	}
-	frames, remaining := reader18.ParseFrames(c.parserBuffer)
-	c.parserBuffer = remaining
 	c.mu.Unlock()
+}
+
+func (c *Client) closeRoundLocked() {
+	if c.roundEnd != nil {
+		close(c.roundEnd)
+		c.roundEnd = nil
+	}
+	c.roundOpen = 0
+}
+
+// waitRoundEnd waits until the round in flight has sent its last frame, at
+// most limit; after that the reader is idle even if a reply was lost.
+// Callers hold txMu so no new round starts meanwhile.
+func (c *Client) waitRoundEnd(ctx context.Context, limit time.Duration) error {
+	c.mu.RLock()
+	end := c.roundEnd
+	c.mu.RUnlock()
+	if end == nil {
+		return nil
+	}
+	timer := time.NewTimer(limit)
+	defer timer.Stop()
+	select {
+	case <-end:
+	case <-timer.C:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
 
+// consumePacket decodes reader bytes under rxMu and handles the frames in
+// order once it is released, so a tag send blocked by OverflowBlock cannot
+// stall Stats or StartInventory. The frames are copied out of the zero-copy
+// decoder into one arena reused across packets, not cloned one by one;
+// nothing keeps a frame past consumeFrame except deliverReply, which
+// clones it.
+func (c *Client) consumePacket(data []byte) {
+	c.consumeMu.Lock()
+	defer c.consumeMu.Unlock()
+	arena, frames, ends := c.rxArena[:0], c.rxFrames[:0], c.rxEnds[:0]
+	c.rxMu.Lock()
+	c.decoder.Feed(data, func(frame reader18.Frame) {
+		arena = append(arena, frame.Raw...)
+		frames = append(frames, frame)
+		ends = append(ends, len(arena))
+	})
+	c.storeParseStatsLocked()
+	c.rxMu.Unlock()
+	c.rxArena, c.rxFrames, c.rxEnds = arena, frames, ends
+
+	// Point the frames into the arena only now that it has stopped growing.
+	start := 0
+	for i := range frames {
+		end := ends[i]
+		raw := arena[start:end:end]
+		frames[i].Raw = raw
+		frames[i].Data = raw[4 : len(raw)-2 : len(raw)-2]
+		start = end
+	}
 	for _, frame := range frames {
 		c.consumeFrame(frame)
 	}
 }
 
+// storeParseStatsLocked copies the decoder counters for Stats; callers
+// hold rxMu.
+func (c *Client) storeParseStatsLocked() {
+	parse := c.decoder.Stats()
+	c.crcErrors.Store(int64(parse.CRCErrors))
+	c.resyncBytes.Store(int64(parse.SkippedBytes))
+	c.truncations.Store(int64(parse.Truncations))
+}
+
 func (c *Client) consumeFrame(frame reader18.Frame) {
 	c.mu.Lock()
 	if c.cfg.AutoAddress {
@@ -100,11 +201,20 @@ func (c *Client) consumeFrame(frame reader18.Frame) {
 	}
 	c.mu.Unlock()
 
+	if c.deliverReply(frame) {
+		return
+	}
 	switch frame.Command {
 	case reader18.CmdInventory:
 		c.handleInventoryFrame(frame)
+		if frame.Status != roundMoreData {
+			c.roundReply()
+		}
 	case reader18.CmdInventorySingle:
 		c.handleInventorySingleFrame(frame)
+		c.roundReply()
+	case reader18.CmdActiveData:
+		c.handleActiveDataFrame(frame)
 	case reader18.CmdGetReaderInfo:
 		c.emitStatus("reader info received")
 	}
@@ -119,6 +229,7 @@ func (c *Client) handleInventoryFrame(frame reader18.Frame) {
 		return
 	}
 	if len(tags) > 0 {
+		c.observeInventoryRound(len(tags), frame.Status)
 		for _, tag := range tags {
 			c.recordTag("inventory-g2", tag.Antenna, tag.RSSI, tag.EPC)
 		}
@@ -130,8 +241,10 @@ func (c *Client) handleInventoryFrame(frame reader18.Frame) {
 		if err == nil && count > 0 {
 			c.emitStatus(fmt.Sprintf("count-only inventory response: %d", count))
 		}
+		c.observeInventoryRound(count, roundDone)
 		return
 	}
+	c.observeInventoryRound(0, frame.Status)
 	c.observeNoTag(frame.Status)
 }
 
@@ -158,7 +271,9 @@ func (c *Client) observeNoTag(status byte) {
 	var target byte
 	c.mu.Lock()
 	c.noTagHit++
-	if c.cfg.Session > 1 && c.cfg.NoTagABSwitch > 0 && c.noTagHit >= c.cfg.NoTagABSwitch {
+	_, session := c.roundParams(c.cfg)
+	abSwitch := c.abSwitchRounds()
+	if session > 1 && abSwitch > 0 && c.noTagHit >= abSwitch {
 		c.targetValue ^= 0x01
 		c.noTagHit = 0
 		switched = true
@@ -174,31 +289,60 @@ func (c *Client) observeNoTag(status byte) {
 func (c *Client) recordTag(source string, antenna int, rssi int, epc []byte) {
 	epcText := strings.ToUpper(hex.EncodeToString(epc))
 	if epcText == "" {
+		// The EPC is the tag's identity everywhere downstream; a read
+		// without one cannot be keyed, so it is counted and rejected.
+		c.emptyEPC.Add(1)
 		return
 	}
+	event := TagEvent{
+		When:    time.Now(),
+		Source:  source,
+		EPC:     epcText,
+		Antenna: antenna,
+		RSSI:    rssi,
+	}
+	// Telemetry sees raw reads so antenna tuning is not skewed by filtering.
+	c.telemetry.Observe(event)
 
 	c.mu.Lock()
 	c.noTagHit = 0
-	_, exists := c.seen[epcText]
-	if !exists {
-		c.seen[epcText] = struct{}{}
+	filter := c.filter
+	mask := c.cfg.Mask
+	c.mu.Unlock()
+	// Single-tag fallback rounds are not selected by the reader's mask.
+	if match, ok := mask.MatchEPC(epc); ok && !match {
+		return
+	}
+	if ok, _ := filter.Check(event); !ok {
+		return
+	}
+	if c.holdForTID(&event) {
+		return
+	}
+	c.acceptTag(event)
+}
+
+// acceptTag runs a read that passed the mask and filter through dedup and
+// presence and emits it.
+func (c *Client) acceptTag(event TagEvent) {
+	c.mu.Lock()
+	presence := c.presence
+	isNew := c.dedup.Check(event.EPC, event.When)
+	if isNew {
 		c.uniqueTags++
 	}
-	c.lastTagEPC = epcText
+	c.lastTagEPC = event.EPC
 	rounds := c.rounds
 	unique := c.uniqueTags
 	c.mu.Unlock()
 
-	c.emitTag(TagEvent{
-		When:       time.Now(),
-		Source:     source,
-		EPC:        epcText,
-		Antenna:    antenna,
-		RSSI:       rssi,
-		IsNew:      !exists,
-		Rounds:     rounds,
-		UniqueTags: unique,
-	})
+	event.IsNew = isNew
+	event.Rounds = rounds
+	event.UniqueTags = unique
+	if presence != nil {
+		c.emitPresence(presence.Observe(event))
+	}
+	c.emitTag(event)
 }
 
 func (c *Client) nextInventoryCommand() (inventory []byte, single []byte, interval time.Duration, ok bool) {
@@ -214,13 +358,15 @@ func (c *Client) nextInventoryCommand() (inventory []byte, single []byte, interv
 
 	antenna, nextIdx := nextInventoryAntenna(cfg.AntennaMask, c.antIdx)
 	c.antIdx = nextIdx
+	q, session := c.roundParams(cfg)
 
-	inventory = reader18.InventoryG2Command(
+	inventory = reader18.InventoryG2MaskedCommand(
 		c.readerAddr,
-		cfg.QValue,
-		cfg.Session,
-		0x00,
-		0x00,
+		q,
+		session,
+		cfg.Mask,
+		0,
+		0,
 		c.targetValue,
 		antenna,
 		cfg.ScanTime,
@@ -249,7 +395,12 @@ func (c *Client) finishInventoryRun() {
 	c.inventoryDone = nil
 	c.cancelInv = nil
 	c.inventoryOn = false
+	presence := c.presence
+	c.closeRoundLocked()
 	c.mu.Unlock()
+	if presence != nil {
+		c.emitPresence(presence.DepartAll(time.Now()))
+	}
 	if done != nil {
 		close(done)
 	}
@@ -0,0 +1,365 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+// tidReader is a fake TCP reader that answers ReadData (0x02) on the TID
+// bank with a fixed TID, or a tag error for failEPC.
+type tidReader struct {
+	mu      sync.Mutex
+	tid     []byte
+	failEPC []byte
+	reads   [][]byte
+}
+
+func (r *tidReader) serve(t *testing.T, ln net.Listener) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	for {
+		head := make([]byte, 1)
+		if _, err := io.ReadFull(conn, head); err != nil {
+			return
+		}
+		rest := make([]byte, head[0])
+		if _, err := io.ReadFull(conn, rest); err != nil {
+			return
+		}
+		addr, cmd, payload := rest[0], rest[1], rest[2:len(rest)-2]
+		if cmd != reader18.CmdReadData {
+			continue
+		}
+		epc := append([]byte(nil), payload[1:1+int(payload[0])*2]...)
+		r.mu.Lock()
+		r.reads = append(r.reads, epc)
+		reply := append([]byte{reader18.StatusSuccess}, r.tid...)
+		if bytes.Equal(epc, r.failEPC) {
+			reply = []byte{reader18.StatusTagError, 0x04}
+		}
+		r.mu.Unlock()
+		if _, err := conn.Write(reader18.BuildCommand(addr, cmd, reply)); err != nil {
+			t.Errorf("write reply: %v", err)
+			return
+		}
+	}
+}
+
+func TestInventoryWithTIDReadsTIDPerNewEPC(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	good := []byte{0xE2, 0x00, 0x00, 0x01}
+	bad := []byte{0xE2, 0x00, 0x00, 0x02}
+	fake := &tidReader{
+		tid:     []byte{0xE2, 0x80, 0x11, 0x60, 0x20, 0x00, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF},
+		failEPC: bad,
+	}
+	go fake.serve(t, ln)
+
+	c := NewClient()
+	port := ln.Addr().(*net.TCPAddr).Port
+	if err := c.Connect(context.Background(), Endpoint{Host: "127.0.0.1", Port: port}, time.Second); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer c.Close()
+	cfg := DefaultInventoryConfig()
+	cfg.TIDWords = 6
+	c.SetInventoryConfig(cfg)
+
+	// The TID is never requested from inventory: with TIDLen > 0 the reader
+	// reports the TID in place of the EPC.
+	c.inventoryOn = true
+	inventory, _, _, ok := c.nextInventoryCommand()
+	c.inventoryOn = false
+	if !ok {
+		t.Fatalf("expected inventory command")
+	}
+	want := reader18.InventoryG2Command(c.readerAddr, cfg.QValue, cfg.Session, 0x00, 0x00, cfg.Target, 0x80, cfg.ScanTime)
+	if !bytes.Equal(inventory, want) {
+		t.Fatalf("inventory command mismatch: got %X want %X", inventory, want)
+	}
+
+	// Reply layout from the manual: Ant Num [EPCLen EPC RSSI]*.
+	round := []byte{0x01, 0x02, 0x04}
+	round = append(round, good...)
+	round = append(round, 0x50, 0x04)
+	round = append(round, bad...)
+	round = append(round, 0x48)
+	c.handleInventoryFrame(reader18.Frame{Command: reader18.CmdInventory, Status: reader18.StatusNoTag, Data: round})
+	select {
+	case ev := <-c.Tags():
+		t.Fatalf("reads must wait for the TID: %+v", ev)
+	default:
+	}
+
+	c.readHeldTIDs(context.Background())
+	got := map[string]TagEvent{}
+	for range 2 {
+		select {
+		case ev := <-c.Tags():
+			got[ev.EPC] = ev
+		case <-time.After(time.Second):
+			t.Fatalf("expected released reads, got %+v", got)
+		}
+	}
+	if ev := got["E2000001"]; ev.TID != "E28011602000AABBCCDDEEFF" || ev.RSSI != 0x50 {
+		t.Fatalf("unexpected read with TID: %+v", ev)
+	}
+	if ev, ok := got["E2000002"]; !ok || ev.TID != "" {
+		t.Fatalf("failed TID read must release the read without a TID: %+v", ev)
+	}
+	if n := c.Stats().TIDReadErrors; n != 1 {
+		t.Fatalf("TIDReadErrors = %d, want 1", n)
+	}
+
+	// A known EPC carries its TID at once without another ReadData.
+	again := append([]byte{0x01, 0x01, 0x04}, good...)
+	again = append(again, 0x52)
+	c.handleInventoryFrame(reader18.Frame{Command: reader18.CmdInventory, Status: reader18.StatusNoTag, Data: again})
+	select {
+	case ev := <-c.Tags():
+		if ev.TID != "E28011602000AABBCCDDEEFF" {
+			t.Fatalf("known EPC must carry its TID: %+v", ev)
+		}
+	default:
+		t.Fatalf("expected tag event")
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.reads) != 2 {
+		t.Fatalf("ReadData sent %d times, want 2", len(fake.reads))
+	}
+}
+
+func TestInventoryMaskSelectsAndDropsForeignReads(t *testing.T) {
+	c := NewClient()
+	cfg := DefaultInventoryConfig()
+	mask, err := EPCPrefixMask("E2")
+	if err != nil {
+		t.Fatalf("prefix mask: %v", err)
+	}
+	cfg.Mask = mask
+	c.SetInventoryConfig(cfg)
+	c.inventoryOn = true
+
+	inventory, _, _, _ := c.nextInventoryCommand()
+	want := reader18.InventoryG2MaskedCommand(c.readerAddr, cfg.QValue, cfg.Session, mask, 0, 0, cfg.Target, 0x80, cfg.ScanTime)
+	if !bytes.Equal(inventory, want) {
+		t.Fatalf("masked inventory mismatch: got %X want %X", inventory, want)
+	}
+
+	// The single-tag fallback is not masked by the reader, so the SDK drops foreign EPCs.
+	c.recordTag("inventory-single", 1, 0, []byte{0x30, 0x00, 0x01})
+	c.recordTag("inventory-single", 1, 0, []byte{0xE2, 0x00, 0x01})
+	select {
+	case ev := <-c.Tags():
+		if ev.EPC != "E20001" {
+			t.Fatalf("expected only masked EPC, got %s", ev.EPC)
+		}
+	default:
+		t.Fatalf("expected masked tag event")
+	}
+	select {
+	case ev := <-c.Tags():
+		t.Fatalf("unexpected extra tag event: %+v", ev)
+	default:
+	}
+}
+
+func TestActiveModeFramesBecomeTagEvents(t *testing.T) {
+	c := NewClient()
+	epc := []byte{0xE2, 0x00, 0x00, 0x09}
+	c.consumeFrame(reader18.Frame{
+		Command: reader18.CmdActiveData,
+		Data:    append(append([]byte{0x01, byte(len(epc))}, epc...), 0x40),
+	})
+	select {
+	case ev := <-c.Tags():
+		if ev.Source != "active" || ev.EPC != "E2000009" || ev.Antenna != 1 || !ev.IsNew {
+			t.Fatalf("unexpected active tag event: %+v", ev)
+		}
+	default:
+		t.Fatalf("expected tag event from active frame")
+	}
+}
+
+func TestReplayCaptureFeedsInventory(t *testing.T) {
+	epc := []byte{0xE2, 0x00, 0x00, 0x42}
+	data := append([]byte{reader18.StatusNoTag, 0x01, 0x01, byte(len(epc))}, epc...)
+	frame := reader18.BuildCommand(0x00, reader18.CmdInventory, append(data, 0x55))
+	// The late second reply keeps the replay open until inventory starts;
+	// at EOF the session and its queued packets are gone.
+	capture := fmt.Sprintf(`{"t":"2026-03-01T10:00:00Z","dir":"open","endpoint":"192.168.1.190:6000"}
+{"t":"2026-03-01T10:00:00.05Z","dir":"tx","hex":"%X"}
+{"t":"2026-03-01T10:00:00.10Z","dir":"rx","hex":"%X"}
+{"t":"2026-03-01T10:00:05Z","dir":"rx","hex":"%X"}
+`, reader18.GetReaderInfoCommand(0x00), frame, frame)
+	path := filepath.Join(t.TempDir(), "site.jsonl")
+	if err := os.WriteFile(path, []byte(capture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	endpoint := Endpoint{Replay: ReplayConfig{File: path, Speed: 10}}
+	if err := c.Connect(ctx, endpoint, time.Second); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer c.Close()
+	if got, _ := c.Endpoint(); got.Address() != "replay:"+path {
+		t.Fatalf("endpoint = %s", got.Address())
+	}
+	if err := c.StartInventory(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	select {
+	case ev := <-c.Tags():
+		if ev.EPC != "E2000042" || ev.RSSI != 0x55 {
+			t.Fatalf("unexpected tag event: %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no tag from replayed capture")
+	}
+}
+
+func TestConsumePacketCountsResyncAndKeepsLargeChunks(t *testing.T) {
+	c := NewClient()
+	info := reader18.BuildCommand(0x00, reader18.CmdGetReaderInfo, []byte{0x00, 0x02, 0x03})
+	corrupt := append([]byte{}, info...)
+	corrupt[len(corrupt)-1] ^= 0xFF
+
+	c.consumePacket(append(append([]byte{0x00, 0x01}, corrupt...), info...))
+
+	// A chunk far larger than the decoder ring must not lose complete
+	// frames; it also flushes whatever the corrupt frame left pending.
+	epc := []byte{0xE2, 0x00, 0x00, 0x00}
+	var chunk []byte
+	const frames = 1000
+	for i := 0; i < frames; i++ {
+		epc[3] = byte(i)
+		epc[2] = byte(i >> 8)
+		data := append([]byte{reader18.StatusNoTag, 0x01, 0x01, byte(len(epc))}, epc...)
+		chunk = append(chunk, reader18.BuildCommand(0x00, reader18.CmdInventory, append(data, 0x50))...)
+	}
+	c.consumePacket(chunk)
+	stats := c.Stats()
+	if stats.UniqueTags != frames {
+		t.Fatalf("unique tags = %d, want %d", stats.UniqueTags, frames)
+	}
+	if stats.CRCErrors < 1 || stats.ResyncBytes != 2+len(corrupt) {
+		t.Fatalf("unexpected parse stats: %+v", stats)
+	}
+}
+
+func TestStartInventoryCountsDiscardedPartialFrame(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idle.jsonl")
+	open := `{"t":"2026-03-01T10:00:00Z","dir":"open","endpoint":"192.168.1.190:6000"}` + "\n"
+	if err := os.WriteFile(path, []byte(open), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Connect(ctx, Endpoint{Replay: ReplayConfig{File: path}}, time.Second); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer c.Close()
+
+	// The head of a reply left over from before the session is cut.
+	info := reader18.BuildCommand(0x00, reader18.CmdGetReaderInfo, []byte{0x00, 0x02, 0x03})
+	c.consumePacket(info[:len(info)-2])
+	if err := c.StartInventory(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer c.StopInventory()
+	if got := c.Stats().BufferTruncations; got != 1 {
+		t.Fatalf("buffer truncations = %d, want 1", got)
+	}
+}
+
+func BenchmarkConsumePacket(b *testing.B) {
+	const tags = 5_000
+	var stream []byte
+	epc := []byte{0x30, 0x34, 0x25, 0x7B, 0xF7, 0x19, 0x4E, 0x40, 0x00, 0x00, 0x00, 0x00}
+	for i := 0; i < tags; i++ {
+		epc[10], epc[11] = byte(i>>8), byte(i)
+		data := append([]byte{reader18.StatusNoTag, 0x01, 0x01, byte(len(epc))}, epc...)
+		stream = append(stream, reader18.BuildCommand(0x00, reader18.CmdInventory, append(data, 0x50))...)
+	}
+	c := NewClient()
+	go func() {
+		for range c.Tags() {
+		}
+	}()
+	b.SetBytes(int64(len(stream)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for start := 0; start < len(stream); start += 4096 {
+			c.consumePacket(stream[start:min(start+4096, len(stream))])
+		}
+	}
+	b.ReportMetric(float64(tags*b.N)/b.Elapsed().Seconds(), "tags/s")
+}
+
+func TestEmptyEPCReadIsCountedNotEmitted(t *testing.T) {
+	c := NewClient()
+	c.recordTag("active", 1, 0, nil)
+	select {
+	case ev := <-c.Tags():
+		t.Fatalf("EPC-less read must not be emitted: %+v", ev)
+	default:
+	}
+	if got := c.Stats().EmptyEPCReads; got != 1 {
+		t.Fatalf("EmptyEPCReads = %d, want 1", got)
+	}
+}
+
+func TestActiveModeFramesUseConfiguredLayout(t *testing.T) {
+	c := NewClient()
+	cfg := DefaultInventoryConfig()
+	cfg.WorkMode = WorkModeActive
+	cfg.StreamFrame = ActiveFrameEPC
+	c.SetInventoryConfig(cfg)
+	// Looks like Ant, EPCLen=2, EPC, RSSI but this firmware sends bare EPCs.
+	c.consumeFrame(reader18.Frame{Command: reader18.CmdActiveData, Data: []byte{0x30, 0x02, 0xAA, 0xBB, 0xCC}})
+	select {
+	case ev := <-c.Tags():
+		if ev.EPC != "3002AABBCC" || ev.Antenna != 0 {
+			t.Fatalf("unexpected bare active tag: %+v", ev)
+		}
+	default:
+		t.Fatalf("expected tag event from bare active frame")
+	}
+}
+
+func TestConsumePacketReusesFrameArena(t *testing.T) {
+	// Empty rounds parse no tags, so whatever allocates here is the frame
+	// handling itself.
+	var packet []byte
+	for range 16 {
+		packet = append(packet, reader18.BuildCommand(0x00, reader18.CmdInventory, []byte{reader18.StatusNoTag, 0x01, 0x00})...)
+	}
+	c := NewClient()
+	c.consumePacket(packet)
+	if allocs := testing.AllocsPerRun(100, func() { c.consumePacket(packet) }); allocs != 0 {
+		t.Fatalf("consumePacket allocated %.1f times per packet, want 0", allocs)
+	}
+}
@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"context"
+	"time"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+// streamRun is the inventory loop for active and trigger work modes: the
+// reader pushes 0xEE tag frames by itself, so nothing is polled. On exit
+// the reader is switched back to answer mode so it stops streaming.
+func (c *Client) streamRun(ctx context.Context) {
+	defer c.finishInventoryRun()
+	defer c.restoreAnswerMode()
+	c.receiveLoop(ctx)
+}
+
+func (c *Client) restoreAnswerMode() {
+	if !c.transport.IsConnected() {
+		return
+	}
+	addr := c.currentReaderAddress()
+	payload := reader18.WorkModePayload(reader18.WorkModeAnswer, 0)
+	if err := c.transport.SendRaw(reader18.SetWorkModeCommand(addr, payload), 2*time.Second); err != nil {
+		c.emitErr(err)
+	}
+}
+
+func (c *Client) handleActiveDataFrame(frame reader18.Frame) {
+	c.mu.Lock()
+	layout := c.cfg.StreamFrame
+	c.mu.Unlock()
+	tag, err := reader18.ParseActiveData(frame, layout)
+	if err != nil {
+		c.emitErr(err)
+		return
+	}
+	c.mu.Lock()
+	c.rounds++
+	c.mu.Unlock()
+	c.recordTag("active", tag.Antenna, tag.RSSI, tag.EPC)
+}
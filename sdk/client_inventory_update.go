@@ -0,0 +1,123 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+// ErrRestartRequired is returned by UpdateInventoryConfig for changes that
+// cannot be applied to a running inventory (work mode and stream tag time).
+var ErrRestartRequired = errors.New("config change needs an inventory restart")
+
+// SettingResult is the outcome of one reader setting sent live.
+type SettingResult struct {
+	// Setting uses DiffConfig names: region, scan_time, antenna_mask,
+	// per_antenna_power, power.
+	Setting string
+	Err     error
+}
+
+func (r SettingResult) String() string {
+	if r.Err != nil {
+		return r.Setting + "=" + r.Err.Error()
+	}
+	return r.Setting + "=ok"
+}
+
+type settingCommand struct {
+	setting string
+	cmd     byte
+	packet  []byte
+}
+
+// liveSettingCommands lists the reader commands that move the reader from
+// old to next, in the same order ApplyInventoryConfig uses.
+func liveSettingCommands(old, next InventoryConfig, addr byte) []settingCommand {
+	var out []settingCommand
+	if next.RegionSet && (!old.RegionSet || old.RegionHigh != next.RegionHigh || old.RegionLow != next.RegionLow) {
+		out = append(out, settingCommand{"region", reader18.CmdSetRegion, reader18.SetFrequencyRangeCommand(addr, next.RegionHigh, next.RegionLow)})
+	}
+	if old.ScanTime != next.ScanTime {
+		out = append(out, settingCommand{"scan_time", reader18.CmdSetScanTime, reader18.SetScanTimeCommand(addr, next.ScanTime)})
+	}
+	if old.AntennaMask != next.AntennaMask {
+		out = append(out, settingCommand{"antenna_mask", reader18.CmdSetAntennaMux, reader18.SetAntennaMuxCommand(addr, next.AntennaMask)})
+	}
+	perAntenna := len(next.PerAntennaPower) > 0 && !bytes.Equal(old.PerAntennaPower, next.PerAntennaPower)
+	if perAntenna {
+		out = append(out, settingCommand{"per_antenna_power", reader18.CmdSetOutputPower, reader18.SetOutputPowerByAntCommand(addr, next.PerAntennaPower)})
+	}
+	// Global power follows per-antenna power as the fallback, as on start.
+	if perAntenna || old.OutputPower != next.OutputPower || (len(old.PerAntennaPower) > 0 && len(next.PerAntennaPower) == 0) {
+		out = append(out, settingCommand{"power", reader18.CmdSetOutputPower, reader18.SetOutputPowerCommand(addr, next.OutputPower)})
+	}
+	return out
+}
+
+// UpdateInventoryConfig switches to cfg without stopping a running
+// inventory. Changed reader settings (0x22 region, 0x25 scan time, 0x3F
+// antenna mux, 0x2F power) are sent after the current round's last frame
+// and before the next round, and each is reported; Q, session, target, poll and filters apply from the next round.
+// Without a running inventory it behaves like SetInventoryConfig.
+func (c *Client) UpdateInventoryConfig(ctx context.Context, cfg InventoryConfig) ([]SettingResult, error) {
+	cfg = normalizeConfig(cfg)
+	c.mu.RLock()
+	running := c.inventoryOn
+	old := cloneInventoryConfig(c.cfg)
+	addr := c.readerAddr
+	c.mu.RUnlock()
+
+	if !running {
+		c.SetInventoryConfig(cfg)
+		return nil, nil
+	}
+	if old.WorkMode != cfg.WorkMode || (reader18.IsStreamingMode(cfg.WorkMode) && old.StreamTagTime != cfg.StreamTagTime) {
+		return nil, ErrRestartRequired
+	}
+
+	// Holding the tx lock keeps the next round back until the settings are
+	// acknowledged; the round already sent must finish first, or its frames
+	// would race the settings replies.
+	c.txMu.Lock()
+	defer c.txMu.Unlock()
+	if err := c.waitRoundEnd(ctx, time.Duration(old.ScanTime)*100*time.Millisecond+roundReplyMargin); err != nil {
+		return nil, err
+	}
+
+	var results []SettingResult
+	failed := 0
+	for _, sc := range liveSettingCommands(old, cfg, addr) {
+		err := c.ioCommand(ctx, sc.packet, sc.cmd)
+		if err != nil {
+			failed++
+		}
+		results = append(results, SettingResult{Setting: sc.setting, Err: err})
+	}
+
+	c.mu.Lock()
+	c.cfg = cloneInventoryConfig(cfg)
+	if old.Target != cfg.Target {
+		c.targetValue = cfg.Target
+	}
+	if old.AntennaMask != cfg.AntennaMask {
+		c.antIdx = 0
+	}
+	if old.QValue != cfg.QValue || old.Session != cfg.Session || old.AdaptiveQ != cfg.AdaptiveQ || old.AdaptiveSession != cfg.AdaptiveSession {
+		c.adaptive = nil
+		if cfg.AdaptiveQ {
+			c.adaptive = NewAdaptiveTuner(cfg)
+		}
+	}
+	c.mu.Unlock()
+
+	c.emitStatus(fmt.Sprintf("inventory config updated live (%d settings, %d failed)", len(results), failed))
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d reader settings failed", failed, len(results))
+	}
+	return results, nil
+}
@@ -0,0 +1,106 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+func settingNames(cmds []settingCommand) []string {
+	names := make([]string, 0, len(cmds))
+	for _, sc := range cmds {
+		names = append(names, sc.setting)
+	}
+	return names
+}
+
+func TestLiveSettingCommandsOnlySendsChanges(t *testing.T) {
+	base := DefaultInventoryConfig()
+	if cmds := liveSettingCommands(base, base, 0xFF); len(cmds) != 0 {
+		t.Fatalf("unchanged config sent %v", settingNames(cmds))
+	}
+
+	next := base
+	next.QValue = 7
+	next.PollInterval *= 2
+	if cmds := liveSettingCommands(base, next, 0xFF); len(cmds) != 0 {
+		t.Fatalf("host-side settings must not hit the reader: %v", settingNames(cmds))
+	}
+
+	long := LongRangeInventoryConfig()
+	cmds := liveSettingCommands(base, long, 0xFF)
+	want := []string{"region", "scan_time", "antenna_mask", "per_antenna_power", "power"}
+	if got := settingNames(cmds); len(got) != len(want) {
+		t.Fatalf("unexpected settings: %v", got)
+	}
+	for i, name := range want {
+		if cmds[i].setting != name || cmds[i].packet[2] != cmds[i].cmd {
+			t.Fatalf("setting %d: got %s cmd=0x%02X packet cmd=0x%02X", i, cmds[i].setting, cmds[i].cmd, cmds[i].packet[2])
+		}
+	}
+
+	// Dropping per-antenna power falls back to one global power command.
+	back := liveSettingCommands(long, base, 0xFF)
+	if got := settingNames(back); got[len(got)-1] != "power" {
+		t.Fatalf("expected global power when per-antenna power is cleared: %v", got)
+	}
+}
+
+func TestUpdateInventoryConfigWithoutInventoryStoresConfig(t *testing.T) {
+	c := NewClient()
+	cfg := LongRangeInventoryConfig()
+	results, err := c.UpdateInventoryConfig(context.Background(), cfg)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("idle update: results=%v err=%v", results, err)
+	}
+	if got := c.InventoryConfig(); got.ScanTime != cfg.ScanTime || got.RegionCode() != "US" {
+		t.Fatalf("config not stored: %+v", got)
+	}
+
+	c.mu.Lock()
+	c.inventoryOn = true
+	c.mu.Unlock()
+	cfg.WorkMode = WorkModeActive
+	if _, err := c.UpdateInventoryConfig(context.Background(), cfg); !errors.Is(err, ErrRestartRequired) {
+		t.Fatalf("work mode change must need a restart, got %v", err)
+	}
+}
+
+func TestUpdateInventoryConfigWaitsForRoundEnd(t *testing.T) {
+	c := NewClient()
+	c.mu.Lock()
+	c.inventoryOn = true
+	c.mu.Unlock()
+	c.beginRound(1)
+
+	cfg := DefaultInventoryConfig()
+	cfg.QValue = 7
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.UpdateInventoryConfig(context.Background(), cfg)
+		done <- err
+	}()
+
+	c.consumeFrame(reader18.Frame{Command: reader18.CmdInventory, Status: roundMoreData})
+	select {
+	case err := <-done:
+		t.Fatalf("update ran before the round ended: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	c.consumeFrame(reader18.Frame{Command: reader18.CmdInventory, Status: roundDone})
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("update: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("update still waiting after the round's last frame")
+	}
+	if got := c.InventoryConfig(); got.QValue != 7 {
+		t.Fatalf("config not updated: q=%d", got.QValue)
+	}
+}
@@ -0,0 +1,154 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+// GPIOState is the decoded GetGPIO (0x47) response.
+type GPIOState = reader18.GPIOState
+
+// Signal is one operator feedback pattern: buzzer/LED beeps plus output
+// pins held high for Pulse.
+type Signal struct {
+	Beeps   int
+	Outputs byte
+	Pulse   time.Duration
+}
+
+// Enabled reports whether the signal does anything.
+func (s Signal) Enabled() bool {
+	return s.Beeps > 0 || s.Outputs != 0
+}
+
+// ParseSignal parses a comma-separated spec such as "beep", "beep:3,out1"
+// or "out2". Only the documented Out1-Out2 pins are accepted. Empty or
+// "off" yields a disabled signal.
+func ParseSignal(spec string) (Signal, error) {
+	var sig Signal
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "" || spec == "off" {
+		return sig, nil
+	}
+	for _, token := range strings.Split(spec, ",") {
+		token = strings.TrimSpace(token)
+		switch {
+		case token == "beep":
+			sig.Beeps = 1
+		case strings.HasPrefix(token, "beep:"):
+			n, err := strconv.Atoi(strings.TrimPrefix(token, "beep:"))
+			if err != nil || n < 1 || n > 0xFF {
+				return Signal{}, fmt.Errorf("invalid beep count in %q", token)
+			}
+			sig.Beeps = n
+		case strings.HasPrefix(token, "out"):
+			pin, err := signalPin(token, "out", reader18.GPIOOutputs)
+			if err != nil {
+				return Signal{}, err
+			}
+			sig.Outputs |= pin
+		default:
+			return Signal{}, fmt.Errorf("unknown signal %q", token)
+		}
+	}
+	return sig, nil
+}
+
+func signalPin(token, prefix string, max int) (byte, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(token, prefix))
+	if err != nil || n < 1 || n > max {
+		return 0, fmt.Errorf("invalid %s number in %q (1..%d)", prefix, token, max)
+	}
+	return 1 << (n - 1), nil
+}
+
+// Beep sounds the buzzer and flashes the LED times times (100ms on, 100ms off).
+func (c *Client) Beep(ctx context.Context, times int) error {
+	if times < 1 {
+		return nil
+	}
+	if times > 0xFF {
+		times = 0xFF
+	}
+	return c.ioCommand(ctx, reader18.AcoustoOpticCommand(c.currentReaderAddress(), 2, 2, byte(times)), reader18.CmdAcoustoOptic)
+}
+
+// SetOutputs drives the GPIO output pins; bits 0-1 are Out1-Out2.
+func (c *Client) SetOutputs(ctx context.Context, outputs byte) error {
+	return c.ioCommand(ctx, reader18.SetGPIOCommand(c.currentReaderAddress(), outputs), reader18.CmdSetGPIO)
+}
+
+// GPIO reads the input and output pin levels.
+func (c *Client) GPIO(ctx context.Context) (GPIOState, error) {
+	frame, err := c.exchange(ctx, reader18.GetGPIOCommand(c.currentReaderAddress()), reader18.CmdGetGPIO)
+	if err != nil {
+		return GPIOState{}, err
+	}
+	return reader18.ParseGPIO(frame)
+}
+
+// signalRestoreTimeout bounds putting the outputs back after a
+// signal, even when the signal's own ctx is already done.
+const signalRestoreTimeout = 2 * time.Second
+
+// Signal plays sig: beeps first, then its outputs raised for Pulse (1s when unset) on top of the current ones, which are restored
+// afterwards. It blocks for the whole pulse; a failed restore is returned.
+func (c *Client) Signal(ctx context.Context, sig Signal) (err error) {
+	if sig.Beeps > 0 {
+		if err := c.Beep(ctx, sig.Beeps); err != nil {
+			return fmt.Errorf("beep: %w", err)
+		}
+	}
+	if sig.Outputs == 0 {
+		return nil
+	}
+	pulse := sig.Pulse
+	if pulse <= 0 {
+		pulse = time.Second
+	}
+	state, err := c.GPIO(ctx)
+	if err != nil {
+		return fmt.Errorf("gpio read: %w", err)
+	}
+	if err := c.SetOutputs(ctx, state.Outputs|sig.Outputs); err != nil {
+		return fmt.Errorf("gpio: %w", err)
+	}
+	defer func() {
+		if rerr := restoreIO(ctx, func(ctx context.Context) error { return c.SetOutputs(ctx, state.Outputs) }); rerr != nil {
+			err = errors.Join(err, fmt.Errorf("gpio restore: %w", rerr))
+		}
+	}()
+	timer := time.NewTimer(pulse)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	return nil
+}
+
+// restoreIO runs restore with signalRestoreTimeout, keeping ctx's values
+// but not its cancellation.
+func restoreIO(ctx context.Context, restore func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), signalRestoreTimeout)
+	defer cancel()
+	return restore(ctx)
+}
+
+// ioCommand sends an I/O command and checks the reader's status byte.
+func (c *Client) ioCommand(ctx context.Context, command []byte, cmd byte) error {
+	frame, err := c.exchange(ctx, command, cmd)
+	if err != nil {
+		return err
+	}
+	if frame.Status != reader18.StatusSuccess {
+		return fmt.Errorf("command 0x%02X status 0x%02X", cmd, frame.Status)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+func TestParseSignal(t *testing.T) {
+	sig, err := ParseSignal(" beep:2, out1,out2 ")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if sig.Beeps != 2 || sig.Outputs != 0x03 || !sig.Enabled() {
+		t.Fatalf("unexpected signal: %+v", sig)
+	}
+	if sig, err := ParseSignal("off"); err != nil || sig.Enabled() {
+		t.Fatalf("off should disable: %+v err=%v", sig, err)
+	}
+	for _, bad := range []string{"beep:0", "out3", "relay1", "siren"} {
+		if _, err := ParseSignal(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+// ioReader is a fake TCP reader that answers the GPIO commands.
+type ioReader struct {
+	mu      sync.Mutex
+	outputs byte
+	sets    []byte
+}
+
+func (r *ioReader) serve(t *testing.T, ln net.Listener) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	for {
+		head := make([]byte, 1)
+		if _, err := io.ReadFull(conn, head); err != nil {
+			return
+		}
+		rest := make([]byte, head[0])
+		if _, err := io.ReadFull(conn, rest); err != nil {
+			return
+		}
+		addr, cmd, payload := rest[0], rest[1], rest[2:len(rest)-2]
+		r.mu.Lock()
+		reply := []byte{reader18.StatusSuccess}
+		switch cmd {
+		case reader18.CmdGetGPIO:
+			reply = append(reply, r.outputs<<4)
+		case reader18.CmdSetGPIO:
+			r.outputs = payload[0]
+			r.sets = append(r.sets, payload[0])
+		}
+		r.mu.Unlock()
+		if _, err := conn.Write(reader18.BuildCommand(addr, cmd, reply)); err != nil {
+			t.Errorf("write reply: %v", err)
+			return
+		}
+	}
+}
+
+func TestSignalRestoresPreviousOutputsAfterCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	fake := &ioReader{outputs: 0x02}
+	go fake.serve(t, ln)
+
+	c := NewClient()
+	port := ln.Addr().(*net.TCPAddr).Port
+	if err := c.Connect(context.Background(), Endpoint{Host: "127.0.0.1", Port: port}, time.Second); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	if err := c.Signal(ctx, Signal{Outputs: 0x01, Pulse: time.Hour}); err != nil {
+		t.Fatalf("signal: %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if !bytes.Equal(fake.sets, []byte{0x03, 0x02}) {
+		t.Fatalf("outputs must be raised over and restored to 0x02: % X", fake.sets)
+	}
+}
@@ -0,0 +1,61 @@
+package sdk
+
+import (
+	"context"
+	"time"
+)
+
+// SetPresence installs a presence tracker fed with every accepted read; nil
+// disables tracking. The tracker is reset when inventory starts and every
+// tag still in the field departs when it stops.
+func (c *Client) SetPresence(tracker *PresenceTracker) {
+	c.mu.Lock()
+	c.presence = tracker
+	c.mu.Unlock()
+}
+
+// PresenceEvents streams arrivals, dwell reminders and departures. Events
+// beyond the buffer are dropped and counted in Stats.DroppedPresence.
+func (c *Client) PresenceEvents() <-chan PresenceEvent {
+	return c.presenceCh
+}
+
+// PresentTags returns the current in-field set; nil without a tracker.
+func (c *Client) PresentTags() []PresentTag {
+	c.mu.RLock()
+	tracker := c.presence
+	c.mu.RUnlock()
+	if tracker == nil {
+		return nil
+	}
+	return tracker.Present()
+}
+
+// presenceRun departs absent tags while inventory runs; reads alone cannot
+// notice a tag that is no longer answering.
+func (c *Client) presenceRun(ctx context.Context, tracker *PresenceTracker) {
+	tick := tracker.Config().AbsenceTimeout / 4
+	if dwell := tracker.Config().DwellInterval; dwell > 0 && dwell/4 < tick {
+		tick = dwell / 4
+	}
+	ticker := time.NewTicker(max(tick, 50*time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			c.emitPresence(tracker.Flush(now))
+		}
+	}
+}
+
+func (c *Client) emitPresence(events []PresenceEvent) {
+	for _, event := range events {
+		select {
+		case c.presenceCh <- event:
+		default:
+			c.droppedPresence.Add(1)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+	"new_era_go/internal/reader"
+	"new_era_go/internal/regions"
+)
+
+// ReaderInfo is the decoded GetReaderInfo (0x21) response.
+type ReaderInfo = reader18.ReaderInfo
+
+// WorkMode is the decoded GetWorkMode (0x36) response.
+type WorkMode = reader18.WorkMode
+
+// Work modes accepted by InventoryConfig.WorkMode.
+const (
+	WorkModeAnswer      = reader18.WorkModeAnswer
+	WorkModeActive      = reader18.WorkModeActive
+	WorkModeTriggerLow  = reader18.WorkModeTriggerLow
+	WorkModeTriggerHigh = reader18.WorkModeTriggerHigh
+)
+
+// ActiveFrame is the 0xEE tag payload layout used in streaming work modes.
+type ActiveFrame = reader18.ActiveFrame
+
+// Layouts accepted by InventoryConfig.StreamFrame.
+const (
+	ActiveFrameAntenna = reader18.ActiveFrameAntenna
+	ActiveFrameEPC     = reader18.ActiveFrameEPC
+)
+
+// ActiveFrameByName parses "antenna" or "epc".
+func ActiveFrameByName(name string) (ActiveFrame, bool) {
+	return reader18.ActiveFrameByName(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// WorkModeByName parses "answer", "active", "trigger-low" or "trigger-high".
+func WorkModeByName(name string) (byte, bool) {
+	return reader18.WorkModeByName(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// ReaderInfo asks the reader for firmware, region, power, scan time and antennas.
+func (c *Client) ReaderInfo(ctx context.Context) (ReaderInfo, error) {
+	frame, err := c.exchange(ctx, reader18.GetReaderInfoCommand(c.currentReaderAddress()), reader18.CmdGetReaderInfo)
+	if err != nil {
+		return ReaderInfo{}, err
+	}
+	return reader18.ParseReaderInfo(frame)
+}
+
+// WorkMode asks the reader for its work mode parameters.
+func (c *Client) WorkMode(ctx context.Context) (WorkMode, error) {
+	frame, err := c.exchange(ctx, reader18.GetWorkModeCommand(c.currentReaderAddress()), reader18.CmdGetWorkMode)
+	if err != nil {
+		return WorkMode{}, err
+	}
+	return reader18.ParseWorkMode(frame)
+}
+
+// ReadConfig returns what the reader is actually using. AntennaMask stays 0
+// on single-port firmware and WorkMode is WorkModeUnknown when 0x36 is not
+// supported; DiffConfig skips both.
+func (c *Client) ReadConfig(ctx context.Context) (InventoryConfig, error) {
+	info, err := c.ReaderInfo(ctx)
+	if err != nil {
+		return InventoryConfig{}, fmt.Errorf("reader info: %w", err)
+	}
+	cfg := InventoryConfig{
+		ReaderAddress: c.currentReaderAddress(),
+		OutputPower:   info.OutputPower,
+		ScanTime:      info.ScanTime,
+		RegionSet:     true,
+		RegionHigh:    info.RegionHigh,
+		RegionLow:     info.RegionLow,
+		WorkMode:      WorkModeUnknown,
+	}
+	if info.HasAntenna {
+		cfg.AntennaMask = info.AntennaMask
+	}
+	// Older firmware rejects or ignores 0x36, so it gets a short timeout of its own.
+	modeCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if mode, err := c.WorkMode(modeCtx); err == nil {
+		cfg.WorkMode = mode.ReadMode
+	} else if ctx.Err() != nil {
+		return InventoryConfig{}, ctx.Err()
+	}
+	return cfg, nil
+}
+
+// WorkModeUnknown marks a ReadConfig result whose work mode was not reported.
+const WorkModeUnknown byte = 0xFF
+
+// ConfigDrift is one setting where the reader disagrees with the desired config.
+type ConfigDrift struct {
+	Field string
+	Want  string
+	Got   string
+}
+
+func (d ConfigDrift) String() string {
+	return fmt.Sprintf("%s want=%s got=%s", d.Field, d.Want, d.Got)
+}
+
+// DiffConfig compares the desired config with one returned by ReadConfig.
+// Per-antenna power is not compared: 0x21 reports one power value.
+func DiffConfig(want, got InventoryConfig) []ConfigDrift {
+	var out []ConfigDrift
+	hex := func(v byte) string { return fmt.Sprintf("0x%02X", v) }
+	if len(want.PerAntennaPower) == 0 && want.OutputPower != got.OutputPower {
+		out = append(out, ConfigDrift{"power", hex(want.OutputPower), hex(got.OutputPower)})
+	}
+	if want.ScanTime != got.ScanTime {
+		out = append(out, ConfigDrift{"scan_time", hex(want.ScanTime), hex(got.ScanTime)})
+	}
+	if got.AntennaMask != 0 && want.AntennaMask != got.AntennaMask {
+		out = append(out, ConfigDrift{"antenna_mask", hex(want.AntennaMask), hex(got.AntennaMask)})
+	}
+	if want.RegionSet && got.RegionSet && (want.RegionHigh != got.RegionHigh || want.RegionLow != got.RegionLow) {
+		out = append(out, ConfigDrift{"region", regions.Label(want.RegionHigh, want.RegionLow), regions.Label(got.RegionHigh, got.RegionLow)})
+	}
+	if got.WorkMode != WorkModeUnknown && want.WorkMode != got.WorkMode {
+		out = append(out, ConfigDrift{"work_mode", reader18.WorkModeName(want.WorkMode), reader18.WorkModeName(got.WorkMode)})
+	}
+	return out
+}
+
+// exchange sends a command and waits for the first response with cmd.
+// While inventory runs, the response is routed from the inventory loop;
+// otherwise packets are read here.
+func (c *Client) exchange(ctx context.Context, command []byte, cmd byte) (reader18.Frame, error) {
+	if !c.transport.IsConnected() {
+		return reader18.Frame{}, fmt.Errorf("not connected")
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 3*time.Second)
+		defer cancel()
+	}
+
+	waiter := make(chan reader18.Frame, 1)
+	c.mu.Lock()
+	if c.waiters == nil {
+		c.waiters = make(map[byte]chan reader18.Frame)
+	}
+	if _, busy := c.waiters[cmd]; busy {
+		c.mu.Unlock()
+		return reader18.Frame{}, fmt.Errorf("command 0x%02X already waiting for a reply", cmd)
+	}
+	c.waiters[cmd] = waiter
+	running := c.inventoryOn
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		if c.waiters[cmd] == waiter {
+			delete(c.waiters, cmd)
+		}
+		c.mu.Unlock()
+	}()
+
+	if err := c.transport.SendRaw(command, 2*time.Second); err != nil {
+		return reader18.Frame{}, err
+	}
+
+	var packets <-chan reader.Packet
+	if !running {
+		packets = c.transport.Packets()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return reader18.Frame{}, fmt.Errorf("waiting for 0x%02X reply: %w", cmd, ctx.Err())
+		case frame := <-waiter:
+			return frame, nil
+		case packet, ok := <-packets:
+			if !ok {
+				return reader18.Frame{}, fmt.Errorf("reader packet channel closed")
+			}
+			c.consumePacket(packet.Data)
+		}
+	}
+}
+
+// deliverReply hands frame to a pending exchange; it reports whether one was waiting.
+func (c *Client) deliverReply(frame reader18.Frame) bool {
+	c.mu.Lock()
+	waiter, ok := c.waiters[frame.Command]
+	if ok {
+		delete(c.waiters, frame.Command)
+	}
+	c.mu.Unlock()
+	if ok {
+		waiter <- frame.Clone()
+	}
+	return ok
+}
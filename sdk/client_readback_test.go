@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"testing"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+func TestDiffConfigReportsOnlyComparableDrift(t *testing.T) {
+	want := DefaultInventoryConfig()
+	want.AntennaMask = 0x0F
+	if err := want.SetRegion("EU"); err != nil {
+		t.Fatalf("set region: %v", err)
+	}
+
+	got := InventoryConfig{
+		OutputPower: want.OutputPower,
+		ScanTime:    0x0A,
+		AntennaMask: 0,
+		RegionSet:   true,
+		WorkMode:    WorkModeActive,
+	}
+	got.RegionHigh, got.RegionLow = 0x31, 0x80 // US
+
+	drift := DiffConfig(want, got)
+	fields := map[string]ConfigDrift{}
+	for _, d := range drift {
+		fields[d.Field] = d
+	}
+	if len(drift) != 3 {
+		t.Fatalf("unexpected drift: %v", drift)
+	}
+	if d := fields["region"]; d.Want != "EU" || d.Got != "US" {
+		t.Fatalf("unexpected region drift: %+v", d)
+	}
+	if d := fields["work_mode"]; d.Want != "answer" || d.Got != "active" {
+		t.Fatalf("unexpected work mode drift: %+v", d)
+	}
+	if _, ok := fields["scan_time"]; !ok {
+		t.Fatalf("expected scan_time drift: %v", drift)
+	}
+
+	got.ScanTime, got.RegionHigh, got.RegionLow, got.WorkMode = want.ScanTime, want.RegionHigh, want.RegionLow, WorkModeUnknown
+	if drift := DiffConfig(want, got); len(drift) != 0 {
+		t.Fatalf("expected no drift, got %v", drift)
+	}
+}
+
+func TestConsumeFrameDeliversPendingReply(t *testing.T) {
+	c := NewClient()
+	waiter := make(chan reader18.Frame, 1)
+	c.waiters = map[byte]chan reader18.Frame{reader18.CmdGetReaderInfo: waiter}
+
+	c.consumeFrame(reader18.Frame{Command: reader18.CmdGetReaderInfo, Status: reader18.StatusSuccess})
+	select {
+	case frame := <-waiter:
+		if frame.Command != reader18.CmdGetReaderInfo {
+			t.Fatalf("unexpected frame: %+v", frame)
+		}
+	default:
+		t.Fatalf("expected reply to reach waiter")
+	}
+	if _, ok := c.waiters[reader18.CmdGetReaderInfo]; ok {
+		t.Fatalf("waiter must be removed after delivery")
+	}
+}
@@ -0,0 +1,149 @@
+package sdk
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	reader18 "new_era_go/internal/protocol/reader18"
+)
+
+// Inventory with TIDLen > 0 returns the TID instead of the EPC, so with
+// InventoryConfig.TIDWords set the SDK keeps inventorying EPCs and reads the
+// TID bank (ReadData 0x02) once per newly seen EPC between rounds. Reads of
+// that EPC are held back until its TID is known; every later read of it in
+// the session carries the TID.
+const (
+	// tidReadsPerRound bounds the TID reads between two rounds so a crowd
+	// of new tags does not stall inventory.
+	tidReadsPerRound = 8
+	// tidReadAttempts is how many failed reads an EPC gets before its reads
+	// pass without a TID for the rest of the session.
+	tidReadAttempts = 3
+	tidReadTimeout  = time.Second
+	// maxTIDEntries caps the per-session EPC to TID table; when full it is
+	// cleared and TIDs are read again.
+	maxTIDEntries = 65536
+)
+
+// tidEntry is the TID state of one EPC; guarded by Client.mu.
+type tidEntry struct {
+	tid      string
+	done     bool
+	attempts int
+	// held is the latest read waiting for the TID; it is queued in
+	// Client.tidQueue while set.
+	held *TagEvent
+}
+
+// holdForTID attaches a known TID to event, or holds the read back until
+// the EPC's TID has been read. It reports whether the read was held.
+func (c *Client) holdForTID(event *TagEvent) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cfg.TIDWords == 0 || reader18.IsStreamingMode(c.cfg.WorkMode) {
+		return false
+	}
+	entry := c.tids[event.EPC]
+	if entry == nil {
+		if c.tids == nil || len(c.tids) >= maxTIDEntries {
+			c.resetTIDsLocked()
+		}
+		entry = &tidEntry{}
+		c.tids[event.EPC] = entry
+	}
+	if entry.done {
+		event.TID = entry.tid
+		return false
+	}
+	if entry.held == nil {
+		c.tidQueue = append(c.tidQueue, event.EPC)
+	}
+	held := *event
+	entry.held = &held
+	return true
+}
+
+func (c *Client) resetTIDsLocked() {
+	c.tids = make(map[string]*tidEntry)
+	c.tidQueue = nil
+}
+
+// readHeldTIDs reads the TIDs of up to tidReadsPerRound held EPCs once the
+// round in flight has finished, then releases their reads. Callers must
+// not hold txMu.
+func (c *Client) readHeldTIDs(ctx context.Context) {
+	c.mu.Lock()
+	if len(c.tidQueue) == 0 {
+		c.mu.Unlock()
+		return
+	}
+	cfg := c.cfg
+	c.mu.Unlock()
+
+	c.txMu.Lock()
+	if err := c.waitRoundEnd(ctx, time.Duration(cfg.ScanTime)*100*time.Millisecond+roundReplyMargin); err != nil {
+		c.txMu.Unlock()
+		return
+	}
+	c.mu.Lock()
+	n := min(len(c.tidQueue), tidReadsPerRound)
+	epcs := append([]string(nil), c.tidQueue[:n]...)
+	c.tidQueue = c.tidQueue[n:]
+	c.mu.Unlock()
+
+	released := make([]TagEvent, 0, len(epcs))
+	for _, epc := range epcs {
+		tid, err := c.readTID(ctx, epc, cfg.TIDAddr, cfg.TIDWords)
+		if err != nil {
+			c.tidReadErrors.Add(1)
+		}
+		c.mu.Lock()
+		entry := c.tids[epc]
+		if entry == nil || entry.held == nil {
+			// The table was reset by a new session meanwhile.
+			c.mu.Unlock()
+			continue
+		}
+		event := *entry.held
+		entry.held = nil
+		if err == nil {
+			entry.tid, entry.done = tid, true
+		} else if entry.attempts++; entry.attempts >= tidReadAttempts {
+			entry.done = true
+		}
+		event.TID = entry.tid
+		c.mu.Unlock()
+		released = append(released, event)
+	}
+	c.txMu.Unlock()
+
+	// A failed read still releases the held read, without a TID, so ingest
+	// never waits on an unreadable tag.
+	for _, event := range released {
+		c.acceptTag(event)
+	}
+}
+
+func (c *Client) readTID(ctx context.Context, epcHex string, addr, words byte) (string, error) {
+	epc, err := hex.DecodeString(epcHex)
+	if err != nil {
+		return "", err
+	}
+	command, err := reader18.ReadDataCommand(c.currentReaderAddress(), epc, reader18.MemBankTID, addr, words, [4]byte{})
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, tidReadTimeout)
+	defer cancel()
+	frame, err := c.exchange(ctx, command, reader18.CmdReadData)
+	if err != nil {
+		return "", err
+	}
+	tid, err := reader18.ParseReadData(frame, int(words))
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(tid)), nil
+}
@@ -0,0 +1,92 @@
+package sdk
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultDedupMaxEntries bounds Deduper memory when no limit is given.
+const DefaultDedupMaxEntries = 65536
+
+// Deduper decides whether a read is new within a sliding time window.
+// Every read refreshes its EPC, so a tag that stays in the field is never
+// new again, while one that has been away for the window is. Memory is
+// bounded: past the entry limit the least recently read EPC is forgotten.
+// It is safe for concurrent use.
+type Deduper struct {
+	window     time.Duration
+	maxEntries int
+
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+type dedupEntry struct {
+	epc  string
+	last time.Time
+}
+
+// NewDeduper creates a deduper. A non-positive window never expires entries
+// (dedup for the whole session); a non-positive limit uses
+// DefaultDedupMaxEntries.
+func NewDeduper(window time.Duration, maxEntries int) *Deduper {
+	if window < 0 {
+		window = 0
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultDedupMaxEntries
+	}
+	return &Deduper{
+		window:     window,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Window returns the dedup window; zero means the whole session.
+func (d *Deduper) Window() time.Duration {
+	return d.window
+}
+
+// Check records a read of epc at at and reports whether it is new.
+func (d *Deduper) Check(epc string, at time.Time) bool {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if el, ok := d.items[epc]; ok {
+		entry := el.Value.(*dedupEntry)
+		fresh := d.window <= 0 || at.Sub(entry.last) < d.window
+		entry.last = at
+		d.order.MoveToFront(el)
+		return !fresh
+	}
+
+	d.items[epc] = d.order.PushFront(&dedupEntry{epc: epc, last: at})
+	for d.order.Len() > d.maxEntries {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.items, oldest.Value.(*dedupEntry).epc)
+	}
+	return true
+}
+
+// Len returns the number of remembered EPCs.
+func (d *Deduper) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.order.Len()
+}
+
+// Reset forgets every EPC.
+func (d *Deduper) Reset() {
+	d.mu.Lock()
+	d.order.Init()
+	d.items = make(map[string]*list.Element)
+	d.mu.Unlock()
+}
@@ -0,0 +1,75 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeduperSlidingWindow(t *testing.T) {
+	d := NewDeduper(10*time.Second, 0)
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	steps := []struct {
+		at   time.Duration
+		want bool
+	}{
+		{0, true},
+		{5 * time.Second, false},
+		// Still in the field: each read slides the window.
+		{14 * time.Second, false},
+		{23 * time.Second, false},
+		// Away for longer than the window: new again.
+		{40 * time.Second, true},
+	}
+	for _, step := range steps {
+		if got := d.Check("E200", t0.Add(step.at)); got != step.want {
+			t.Fatalf("Check at %s = %v, want %v", step.at, got, step.want)
+		}
+	}
+}
+
+func TestDeduperZeroWindowAndLRUBound(t *testing.T) {
+	d := NewDeduper(0, 2)
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	d.Check("A", t0)
+	d.Check("B", t0)
+	if d.Check("A", t0.Add(24*time.Hour)) {
+		t.Fatal("zero window should never expire an entry")
+	}
+	// A was read last, so C evicts B.
+	d.Check("C", t0)
+	if d.Len() != 2 {
+		t.Fatalf("len = %d, want 2", d.Len())
+	}
+	if !d.Check("B", t0) {
+		t.Fatal("evicted EPC should be new again")
+	}
+	if d.Check("C", t0) {
+		t.Fatal("recent EPC should stay deduplicated")
+	}
+	d.Reset()
+	if d.Len() != 0 || !d.Check("C", t0) {
+		t.Fatal("reset should forget everything")
+	}
+}
+
+func TestClientDedupWindowReportsReturningTags(t *testing.T) {
+	c := NewClient()
+	c.SetDedup(50*time.Millisecond, 0)
+	epc := []byte{0xE2, 0x00, 0x00, 0x02}
+
+	c.recordTag("inventory", 1, 0x50, epc)
+	c.recordTag("inventory", 1, 0x50, epc)
+	time.Sleep(80 * time.Millisecond)
+	c.recordTag("inventory", 1, 0x50, epc)
+
+	var got []bool
+	for range 3 {
+		got = append(got, (<-c.Tags()).IsNew)
+	}
+	if !got[0] || got[1] || !got[2] {
+		t.Fatalf("IsNew = %v, want [true false true]", got)
+	}
+	if unique := c.Stats().UniqueTags; unique != 2 {
+		t.Fatalf("unique tags = %d, want 2", unique)
+	}
+}
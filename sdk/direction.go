@@ -0,0 +1,209 @@
+package sdk
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Direction is the movement of a tag through an inside/outside antenna portal.
+type Direction string
+
+const (
+	DirectionUnknown Direction = ""
+	DirectionIn      Direction = "in"
+	DirectionOut     Direction = "out"
+)
+
+// DefaultDirectionWindow is how long a tag must stay unseen before its pass is decided.
+const DefaultDirectionWindow = 3 * time.Second
+
+// DirectionConfig describes a dock-door portal.
+type DirectionConfig struct {
+	InsideAntenna  int
+	OutsideAntenna int
+	// Window closes a pass after the tag has not been read for this long.
+	Window time.Duration
+}
+
+// Enabled reports whether both portal antennas are configured and distinct.
+func (c DirectionConfig) Enabled() bool {
+	return c.InsideAntenna > 0 && c.OutsideAntenna > 0 && c.InsideAntenna != c.OutsideAntenna
+}
+
+// DirectionEvent is emitted once per tag pass when the pass window closes.
+type DirectionEvent struct {
+	When      time.Time
+	EPC       string
+	Direction Direction
+	// Reads counts portal-antenna reads in the pass.
+	Reads int
+	// InsidePeak and OutsidePeak are the strongest-read times per side; zero when the side was not seen.
+	InsidePeak  time.Time
+	OutsidePeak time.Time
+	// Last is the last portal-antenna read that belongs to the pass.
+	Last TagEvent
+}
+
+type directionSide struct {
+	first    time.Time
+	peakAt   time.Time
+	peakRSSI int
+	reads    int
+}
+
+func (s *directionSide) add(at time.Time, rssi int) {
+	if s.reads == 0 {
+		s.first = at
+		s.peakAt = at
+		s.peakRSSI = rssi
+	} else if rssi > s.peakRSSI {
+		s.peakAt = at
+		s.peakRSSI = rssi
+	}
+	s.reads++
+}
+
+// moment is the time used to order sides: the RSSI peak when both sides
+// reported RSSI, otherwise the first sighting.
+func (s directionSide) moment(useRSSI bool) time.Time {
+	if useRSSI {
+		return s.peakAt
+	}
+	return s.first
+}
+
+type directionTrack struct {
+	inside  directionSide
+	outside directionSide
+	last    TagEvent
+}
+
+// DirectionDetector turns a TagEvent stream into per-pass in/out decisions.
+// A tag moving from the outside antenna to the inside antenna is "in".
+type DirectionDetector struct {
+	cfg DirectionConfig
+
+	mu     sync.Mutex
+	tracks map[string]*directionTrack
+}
+
+// NewDirectionDetector creates a detector; non-positive windows use DefaultDirectionWindow.
+func NewDirectionDetector(cfg DirectionConfig) *DirectionDetector {
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultDirectionWindow
+	}
+	return &DirectionDetector{cfg: cfg, tracks: make(map[string]*directionTrack)}
+}
+
+// Config returns the effective portal configuration.
+func (d *DirectionDetector) Config() DirectionConfig {
+	return d.cfg
+}
+
+// Observe feeds one read. It only looks at the read's own tag: when that
+// tag's previous pass has been idle for the window, the pass is closed and
+// returned before a new one starts. Other idle passes are closed by Flush,
+// which the caller runs on a timer. Reads from antennas outside the portal
+// are ignored.
+func (d *DirectionDetector) Observe(event TagEvent) []DirectionEvent {
+	at := event.When
+	if at.IsZero() {
+		at = time.Now()
+		event.When = at
+	}
+	if event.EPC == "" || (event.Antenna != d.cfg.InsideAntenna && event.Antenna != d.cfg.OutsideAntenna) {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var closed []DirectionEvent
+	track := d.tracks[event.EPC]
+	if track != nil && at.Sub(track.last.When) >= d.cfg.Window {
+		closed = append(closed, track.event(event.EPC))
+		track = nil
+	}
+	if track == nil {
+		track = &directionTrack{}
+		d.tracks[event.EPC] = track
+	}
+	if event.Antenna == d.cfg.InsideAntenna {
+		track.inside.add(at, event.RSSI)
+	} else {
+		track.outside.add(at, event.RSSI)
+	}
+	track.last = event
+	return closed
+}
+
+// Flush closes passes that have been idle for the window as of now.
+func (d *DirectionDetector) Flush(now time.Time) []DirectionEvent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.flushLocked(now)
+}
+
+// Reset drops all open passes without emitting them.
+func (d *DirectionDetector) Reset() {
+	d.mu.Lock()
+	d.tracks = make(map[string]*directionTrack)
+	d.mu.Unlock()
+}
+
+func (d *DirectionDetector) flushLocked(now time.Time) []DirectionEvent {
+	var out []DirectionEvent
+	for epc, track := range d.tracks {
+		if now.Sub(track.last.When) < d.cfg.Window {
+			continue
+		}
+		delete(d.tracks, epc)
+		out = append(out, track.event(epc))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Last.When.Before(out[j].Last.When) })
+	return out
+}
+
+func (t *directionTrack) event(epc string) DirectionEvent {
+	ev := DirectionEvent{
+		When:  t.last.When,
+		EPC:   epc,
+		Reads: t.inside.reads + t.outside.reads,
+		Last:  t.last,
+	}
+	if t.inside.reads > 0 {
+		ev.InsidePeak = t.inside.peakAt
+	}
+	if t.outside.reads > 0 {
+		ev.OutsidePeak = t.outside.peakAt
+	}
+	if t.inside.reads == 0 || t.outside.reads == 0 {
+		return ev
+	}
+
+	useRSSI := t.inside.peakRSSI > 0 && t.outside.peakRSSI > 0
+	insideAt := t.inside.moment(useRSSI)
+	outsideAt := t.outside.moment(useRSSI)
+	switch {
+	case outsideAt.Before(insideAt):
+		ev.Direction = DirectionIn
+	case insideAt.Before(outsideAt):
+		ev.Direction = DirectionOut
+	}
+	return ev
+}
+
+// ParseDirection accepts "in", "out" and empty/"any"/"unknown".
+func ParseDirection(raw string) (Direction, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "in":
+		return DirectionIn, true
+	case "out":
+		return DirectionOut, true
+	case "", "any", "unknown":
+		return DirectionUnknown, true
+	default:
+		return DirectionUnknown, false
+	}
+}
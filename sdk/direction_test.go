@@ -0,0 +1,68 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDirectionDetectorUsesRSSIPeakOrder(t *testing.T) {
+	d := NewDirectionDetector(DirectionConfig{InsideAntenna: 1, OutsideAntenna: 2, Window: time.Second})
+	base := time.Now()
+
+	// Outside peaks first, inside peaks later: the pallet moved in.
+	d.Observe(TagEvent{When: base, EPC: "A", Antenna: 1, RSSI: 40})
+	d.Observe(TagEvent{When: base.Add(100 * time.Millisecond), EPC: "A", Antenna: 2, RSSI: 70})
+	d.Observe(TagEvent{When: base.Add(200 * time.Millisecond), EPC: "A", Antenna: 2, RSSI: 50})
+	d.Observe(TagEvent{When: base.Add(400 * time.Millisecond), EPC: "A", Antenna: 1, RSSI: 75})
+	d.Observe(TagEvent{When: base.Add(500 * time.Millisecond), EPC: "A", Antenna: 3, RSSI: 90})
+
+	if got := d.Flush(base.Add(900 * time.Millisecond)); len(got) != 0 {
+		t.Fatalf("pass closed too early: %+v", got)
+	}
+	got := d.Flush(base.Add(1500 * time.Millisecond))
+	if len(got) != 1 {
+		t.Fatalf("expected one pass, got %d", len(got))
+	}
+	if got[0].Direction != DirectionIn || got[0].Reads != 4 || got[0].Last.Antenna != 1 {
+		t.Fatalf("unexpected pass: %+v", got[0])
+	}
+}
+
+func TestDirectionDetectorFallsBackToFirstSighting(t *testing.T) {
+	d := NewDirectionDetector(DirectionConfig{InsideAntenna: 1, OutsideAntenna: 2, Window: time.Second})
+	base := time.Now()
+
+	d.Observe(TagEvent{When: base, EPC: "B", Antenna: 1})
+	d.Observe(TagEvent{When: base.Add(200 * time.Millisecond), EPC: "B", Antenna: 2})
+	d.Observe(TagEvent{When: base.Add(300 * time.Millisecond), EPC: "C", Antenna: 2})
+
+	// A later read of another tag does not close them; Flush does.
+	if got := d.Observe(TagEvent{When: base.Add(2 * time.Second), EPC: "D", Antenna: 1}); len(got) != 0 {
+		t.Fatalf("observe closed other passes: %+v", got)
+	}
+	got := d.Flush(base.Add(2 * time.Second))
+	if len(got) != 2 {
+		t.Fatalf("expected two closed passes, got %+v", got)
+	}
+	if got[0].EPC != "B" || got[0].Direction != DirectionOut {
+		t.Fatalf("unexpected B pass: %+v", got[0])
+	}
+	if got[1].EPC != "C" || got[1].Direction != DirectionUnknown {
+		t.Fatalf("single-side pass must be unknown: %+v", got[1])
+	}
+}
+
+func TestDirectionDetectorObserveClosesOwnStalePass(t *testing.T) {
+	d := NewDirectionDetector(DirectionConfig{InsideAntenna: 1, OutsideAntenna: 2, Window: time.Second})
+	base := time.Now()
+
+	d.Observe(TagEvent{When: base, EPC: "E", Antenna: 2})
+	d.Observe(TagEvent{When: base.Add(100 * time.Millisecond), EPC: "E", Antenna: 1})
+	got := d.Observe(TagEvent{When: base.Add(3 * time.Second), EPC: "E", Antenna: 1})
+	if len(got) != 1 || got[0].Direction != DirectionIn || got[0].Reads != 2 {
+		t.Fatalf("stale pass not closed on its own read: %+v", got)
+	}
+	if rest := d.Flush(base.Add(5 * time.Second)); len(rest) != 1 || rest[0].Reads != 1 {
+		t.Fatalf("new pass must start with the late read: %+v", rest)
+	}
+}
@@ -0,0 +1,164 @@
+package sdk
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"new_era_go/internal/reader"
+)
+
+// DefaultSubscribeBuffer is the subscriber channel capacity when none is set.
+const DefaultSubscribeBuffer = 256
+
+// SubscribeFilter selects the tag events one subscriber receives.
+// Empty lists do not filter.
+type SubscribeFilter struct {
+	// Antennas applies only to reads that report an antenna.
+	Antennas []int
+	// EPCPrefixes are hex prefixes, matched case-insensitively.
+	EPCPrefixes []string
+	// Buffer is the channel capacity (DefaultSubscribeBuffer when <= 0).
+	Buffer int
+	// DedupWindow, when positive, passes an EPC only if this subscriber has
+	// not received it within the window; delivered events have IsNew set.
+	// The subscriber's memory is independent of the client's SetDedup.
+	DedupWindow time.Duration
+}
+
+// Match reports whether event passes the filter.
+func (f SubscribeFilter) Match(event TagEvent) bool {
+	if len(f.Antennas) > 0 && event.Antenna > 0 && !slices.Contains(f.Antennas, event.Antenna) {
+		return false
+	}
+	if len(f.EPCPrefixes) > 0 && !hasAnyPrefix(strings.ToUpper(event.EPC), f.EPCPrefixes) {
+		return false
+	}
+	return true
+}
+
+// TagFanout copies tag events to any number of independent subscribers,
+// each with its own buffer and filter. The zero value is ready to use and
+// it is safe for concurrent use.
+type TagFanout struct {
+	mu   sync.Mutex
+	next int
+	subs map[int]*subscriber
+}
+
+type subscriber struct {
+	filter SubscribeFilter
+	dedup  *Deduper
+	ch     chan TagEvent
+	done   chan struct{}
+	once   sync.Once
+
+	// mu guards ch against close while Publish is sending.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// Subscribe returns a channel that receives every published event matching
+// filter. cancel removes the subscriber and closes the channel; it may be
+// called more than once.
+func (f *TagFanout) Subscribe(filter SubscribeFilter) (<-chan TagEvent, func()) {
+	filter.EPCPrefixes = normalizeHexPrefixes(filter.EPCPrefixes)
+	if filter.Buffer <= 0 {
+		filter.Buffer = DefaultSubscribeBuffer
+	}
+	sub := &subscriber{
+		filter: filter,
+		ch:     make(chan TagEvent, filter.Buffer),
+		done:   make(chan struct{}),
+	}
+	if filter.DedupWindow > 0 {
+		sub.dedup = NewDeduper(filter.DedupWindow, 0)
+	}
+
+	f.mu.Lock()
+	if f.subs == nil {
+		f.subs = make(map[int]*subscriber)
+	}
+	f.next++
+	id := f.next
+	f.subs[id] = sub
+	f.mu.Unlock()
+
+	return sub.ch, func() {
+		f.mu.Lock()
+		delete(f.subs, id)
+		f.mu.Unlock()
+		sub.close()
+	}
+}
+
+// Len returns the number of active subscribers.
+func (f *TagFanout) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subs)
+}
+
+// Publish offers event to every matching subscriber under policy and
+// returns how many of them lost an event. With OverflowBlock a full
+// subscriber holds Publish until it reads, cancels or stop closes.
+func (f *TagFanout) Publish(event TagEvent, policy OverflowPolicy, stop <-chan struct{}) int {
+	f.mu.Lock()
+	if len(f.subs) == 0 {
+		f.mu.Unlock()
+		return 0
+	}
+	subs := make([]*subscriber, 0, len(f.subs))
+	for _, sub := range f.subs {
+		subs = append(subs, sub)
+	}
+	f.mu.Unlock()
+
+	lost := 0
+	for _, sub := range subs {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		ev := event
+		if sub.dedup != nil {
+			if !sub.dedup.Check(ev.EPC, ev.When) {
+				continue
+			}
+			ev.IsNew = true
+		}
+		if !sub.offer(ev, policy, stop) {
+			lost++
+		}
+	}
+	return lost
+}
+
+func (s *subscriber) offer(event TagEvent, policy OverflowPolicy, stop <-chan struct{}) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return true
+	}
+	if policy != OverflowBlock {
+		return reader.Offer(s.ch, event, policy, nil)
+	}
+	select {
+	case s.ch <- event:
+		return true
+	case <-s.done:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+func (s *subscriber) close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+	})
+}
@@ -0,0 +1,102 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribeFilterMatch(t *testing.T) {
+	f := SubscribeFilter{Antennas: []int{2}, EPCPrefixes: normalizeHexPrefixes([]string{"e2 00"})}
+	cases := []struct {
+		event TagEvent
+		want  bool
+	}{
+		{TagEvent{EPC: "E2001122", Antenna: 2}, true},
+		{TagEvent{EPC: "e2001122", Antenna: 0}, true},
+		{TagEvent{EPC: "E2001122", Antenna: 1}, false},
+		{TagEvent{EPC: "300833B2", Antenna: 2}, false},
+	}
+	for _, tc := range cases {
+		if got := f.Match(tc.event); got != tc.want {
+			t.Fatalf("Match(%+v) = %v, want %v", tc.event, got, tc.want)
+		}
+	}
+}
+
+func TestTagFanoutDeliversToEverySubscriber(t *testing.T) {
+	var f TagFanout
+	all, cancelAll := f.Subscribe(SubscribeFilter{})
+	ant1, cancelAnt1 := f.Subscribe(SubscribeFilter{Antennas: []int{1}, Buffer: 1})
+	defer cancelAll()
+
+	f.Publish(TagEvent{EPC: "E201", Antenna: 1}, OverflowDropNewest, nil)
+	f.Publish(TagEvent{EPC: "E202", Antenna: 2}, OverflowDropNewest, nil)
+	if lost := f.Publish(TagEvent{EPC: "E203", Antenna: 1}, OverflowDropNewest, nil); lost != 1 {
+		t.Fatalf("lost = %d, want 1 for the one-slot subscriber", lost)
+	}
+
+	for _, want := range []string{"E201", "E202", "E203"} {
+		if ev := <-all; ev.EPC != want {
+			t.Fatalf("all subscriber got %s, want %s", ev.EPC, want)
+		}
+	}
+	if ev := <-ant1; ev.EPC != "E201" {
+		t.Fatalf("antenna subscriber got %s", ev.EPC)
+	}
+
+	cancelAnt1()
+	cancelAnt1()
+	if _, ok := <-ant1; ok {
+		t.Fatal("cancelled subscriber channel still open")
+	}
+	if f.Len() != 1 {
+		t.Fatalf("subscribers = %d", f.Len())
+	}
+}
+
+func TestTagFanoutBlockReleasedByCancel(t *testing.T) {
+	var f TagFanout
+	_, cancel := f.Subscribe(SubscribeFilter{Buffer: 1})
+	f.Publish(TagEvent{EPC: "E201"}, OverflowBlock, nil)
+
+	done := make(chan int)
+	go func() { done <- f.Publish(TagEvent{EPC: "E202"}, OverflowBlock, nil) }()
+	select {
+	case <-done:
+		t.Fatal("block publish returned while the subscriber was full")
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	select {
+	case lost := <-done:
+		if lost != 0 {
+			t.Fatalf("cancel counted as loss: %d", lost)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancel did not release blocked publish")
+	}
+}
+
+func TestSubscriberDedupWindowIsPerSubscriber(t *testing.T) {
+	var f TagFanout
+	all, cancelAll := f.Subscribe(SubscribeFilter{})
+	deduped, cancelDeduped := f.Subscribe(SubscribeFilter{DedupWindow: time.Minute})
+	defer cancelAll()
+	defer cancelDeduped()
+
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	for _, at := range []time.Duration{0, time.Second, 2 * time.Minute} {
+		f.Publish(TagEvent{EPC: "E201", When: t0.Add(at)}, OverflowDropNewest, nil)
+	}
+	if len(all) != 3 {
+		t.Fatalf("plain subscriber got %d events, want 3", len(all))
+	}
+	if len(deduped) != 2 {
+		t.Fatalf("deduplicating subscriber got %d events, want 2", len(deduped))
+	}
+	for range 2 {
+		if ev := <-deduped; !ev.IsNew {
+			t.Fatalf("deduplicated event not marked new: %+v", ev)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFilterReadWindow is the MinReads window when none is configured.
+const DefaultFilterReadWindow = 2 * time.Second
+
+// FilterRules describes which reads are accepted before ingest.
+// Empty lists and zero values do not filter.
+type FilterRules struct {
+	AllowPrefixes []string `json:"allow_prefixes,omitempty"`
+	DenyPrefixes  []string `json:"deny_prefixes,omitempty"`
+	AllowRegex    []string `json:"allow_regex,omitempty"`
+	DenyRegex     []string `json:"deny_regex,omitempty"`
+	// CompanyPrefixes accepts only GS1 EPCs whose company prefix is listed.
+	CompanyPrefixes []string `json:"gs1_company_prefixes,omitempty"`
+	// MinRSSI applies only to reads that report RSSI.
+	MinRSSI int `json:"min_rssi,omitempty"`
+	// Antennas applies only to reads that report an antenna.
+	Antennas []int `json:"antennas,omitempty"`
+	// MinReads requires that many reads of one EPC within ReadWindowMS.
+	MinReads     int `json:"min_reads,omitempty"`
+	ReadWindowMS int `json:"read_window_ms,omitempty"`
+}
+
+// TagFilter evaluates FilterRules against TagEvents. It is safe for concurrent use.
+type TagFilter struct {
+	rules      FilterRules
+	allowRegex []*regexp.Regexp
+	denyRegex  []*regexp.Regexp
+	window     time.Duration
+
+	mu     sync.Mutex
+	reads  map[string][]time.Time
+	checks int
+}
+
+// NewTagFilter validates and compiles rules.
+func NewTagFilter(rules FilterRules) (*TagFilter, error) {
+	f := &TagFilter{
+		rules:  normalizeFilterRules(rules),
+		window: DefaultFilterReadWindow,
+		reads:  make(map[string][]time.Time),
+	}
+	if f.rules.ReadWindowMS > 0 {
+		f.window = time.Duration(f.rules.ReadWindowMS) * time.Millisecond
+	}
+	var err error
+	if f.allowRegex, err = compileFilterRegex(f.rules.AllowRegex); err != nil {
+		return nil, err
+	}
+	if f.denyRegex, err = compileFilterRegex(f.rules.DenyRegex); err != nil {
+		return nil, err
+	}
+	for _, antenna := range f.rules.Antennas {
+		if antenna < 1 || antenna > 16 {
+			return nil, fmt.Errorf("filter antenna out of range: %d", antenna)
+		}
+	}
+	return f, nil
+}
+
+// Rules returns the normalized rules.
+func (f *TagFilter) Rules() FilterRules {
+	return f.rules
+}
+
+// Check reports whether the read passes; reason names the first failed rule.
+// Every call counts as one read of the EPC for MinReads. A nil filter
+// accepts everything.
+func (f *TagFilter) Check(event TagEvent) (bool, string) {
+	return f.check(event, true)
+}
+
+// CheckWithoutMinReads is Check minus the MinReads rule, for callers that
+// do not see every radio read of a tag (such as IPC/HTTP ingest, which gets
+// one call per reported tag) and so cannot count reads.
+func (f *TagFilter) CheckWithoutMinReads(event TagEvent) (bool, string) {
+	return f.check(event, false)
+}
+
+func (f *TagFilter) check(event TagEvent, countReads bool) (bool, string) {
+	if f == nil {
+		return true, ""
+	}
+	epc := strings.ToUpper(strings.TrimSpace(event.EPC))
+	if epc == "" {
+		return false, "empty_epc"
+	}
+
+	r := f.rules
+	if len(r.DenyPrefixes) > 0 && hasAnyPrefix(epc, r.DenyPrefixes) {
+		return false, "deny_prefix"
+	}
+	for _, re := range f.denyRegex {
+		if re.MatchString(epc) {
+			return false, "deny_regex"
+		}
+	}
+	if len(r.AllowPrefixes) > 0 && !hasAnyPrefix(epc, r.AllowPrefixes) {
+		return false, "allow_prefix"
+	}
+	if len(f.allowRegex) > 0 && !slices.ContainsFunc(f.allowRegex, func(re *regexp.Regexp) bool { return re.MatchString(epc) }) {
+		return false, "allow_regex"
+	}
+	if len(r.CompanyPrefixes) > 0 {
+		company, ok := gs1CompanyPrefix(epc)
+		if !ok || !slices.Contains(r.CompanyPrefixes, company) {
+			return false, "company_prefix"
+		}
+	}
+	if r.MinRSSI > 0 && event.RSSI > 0 && event.RSSI < r.MinRSSI {
+		return false, "min_rssi"
+	}
+	if len(r.Antennas) > 0 && event.Antenna > 0 && !slices.Contains(r.Antennas, event.Antenna) {
+		return false, "antenna"
+	}
+	if countReads && r.MinReads > 1 && !f.countRead(epc, event.When) {
+		return false, "min_reads"
+	}
+	return true, ""
+}
+
+// countRead records one read and reports whether MinReads is reached within the window.
+func (f *TagFilter) countRead(epc string, at time.Time) bool {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	cutoff := at.Add(-f.window)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	times := pruneReadTimes(f.reads[epc], cutoff)
+	times = append(times, at)
+	f.reads[epc] = times
+
+	f.checks++
+	if f.checks%1024 == 0 {
+		for key, list := range f.reads {
+			if list = pruneReadTimes(list, cutoff); len(list) == 0 {
+				delete(f.reads, key)
+			} else {
+				f.reads[key] = list
+			}
+		}
+	}
+	return len(times) >= f.rules.MinReads
+}
+
+func pruneReadTimes(times []time.Time, cutoff time.Time) []time.Time {
+	drop := 0
+	for drop < len(times) && times[drop].Before(cutoff) {
+		drop++
+	}
+	if drop == 0 {
+		return times
+	}
+	return append(times[:0], times[drop:]...)
+}
+
+func normalizeFilterRules(r FilterRules) FilterRules {
+	r.AllowPrefixes = normalizeHexPrefixes(r.AllowPrefixes)
+	r.DenyPrefixes = normalizeHexPrefixes(r.DenyPrefixes)
+	companies := make([]string, 0, len(r.CompanyPrefixes))
+	for _, c := range r.CompanyPrefixes {
+		if c = strings.TrimSpace(c); c != "" {
+			companies = append(companies, c)
+		}
+	}
+	r.CompanyPrefixes = companies
+	if r.MinReads < 0 {
+		r.MinReads = 0
+	}
+	if r.ReadWindowMS < 0 {
+		r.ReadWindowMS = 0
+	}
+	return r
+}
+
+func normalizeHexPrefixes(list []string) []string {
+	out := make([]string, 0, len(list))
+	for _, p := range list {
+		p = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(p), " ", ""))
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func compileFilterRegex(patterns []string) ([]*regexp.Regexp, error) {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("filter regex %q: %w", p, err)
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+func hasAnyPrefix(epc string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(epc, p) {
+			return true
+		}
+	}
+	return false
+}
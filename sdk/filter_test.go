@@ -0,0 +1,86 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTagFilterRules(t *testing.T) {
+	f, err := NewTagFilter(FilterRules{
+		AllowPrefixes: []string{"e2", "30"},
+		DenyPrefixes:  []string{"E2DEAD"},
+		DenyRegex:     []string{"FFFF$"},
+		MinRSSI:       50,
+		Antennas:      []int{1, 2},
+	})
+	if err != nil {
+		t.Fatalf("new filter: %v", err)
+	}
+
+	cases := []struct {
+		event  TagEvent
+		ok     bool
+		reason string
+	}{
+		{TagEvent{EPC: "E2000001", Antenna: 1, RSSI: 60}, true, ""},
+		{TagEvent{EPC: "E2DEAD01", Antenna: 1, RSSI: 60}, false, "deny_prefix"},
+		{TagEvent{EPC: "E200FFFF", Antenna: 1, RSSI: 60}, false, "deny_regex"},
+		{TagEvent{EPC: "AA000001", Antenna: 1, RSSI: 60}, false, "allow_prefix"},
+		{TagEvent{EPC: "E2000001", Antenna: 1, RSSI: 40}, false, "min_rssi"},
+		{TagEvent{EPC: "E2000001", Antenna: 3, RSSI: 60}, false, "antenna"},
+		// Reads without RSSI or antenna are not judged by those rules.
+		{TagEvent{EPC: "E2000001"}, true, ""},
+	}
+	for _, tc := range cases {
+		ok, reason := f.Check(tc.event)
+		if ok != tc.ok || reason != tc.reason {
+			t.Fatalf("%+v: got ok=%v reason=%q", tc.event, ok, reason)
+		}
+	}
+}
+
+func TestTagFilterMinReadsWithinWindow(t *testing.T) {
+	f, err := NewTagFilter(FilterRules{MinReads: 3, ReadWindowMS: 1000})
+	if err != nil {
+		t.Fatalf("new filter: %v", err)
+	}
+	base := time.Now()
+	for i, want := range []bool{false, false, true, true} {
+		ok, _ := f.Check(TagEvent{EPC: "E2", When: base.Add(time.Duration(i) * 100 * time.Millisecond)})
+		if ok != want {
+			t.Fatalf("read %d: ok=%v want %v", i, ok, want)
+		}
+	}
+	// After the window the count starts over.
+	if ok, reason := f.Check(TagEvent{EPC: "E2", When: base.Add(5 * time.Second)}); ok || reason != "min_reads" {
+		t.Fatalf("expected min_reads after idle window, got ok=%v reason=%q", ok, reason)
+	}
+	if ok, _ := f.CheckWithoutMinReads(TagEvent{EPC: "E3", When: base}); !ok {
+		t.Fatalf("CheckWithoutMinReads must skip the read count")
+	}
+}
+
+func TestTagFilterCompanyPrefix(t *testing.T) {
+	// SGTIN-96, filter 3, partition 5: company 0614141, item 812345, serial 6789.
+	const sgtin = "3074257BF7194E4000001A85"
+	if got, ok := gs1CompanyPrefix(sgtin); !ok || got != "0614141" {
+		t.Fatalf("company prefix: got %q ok=%v", got, ok)
+	}
+
+	f, err := NewTagFilter(FilterRules{CompanyPrefixes: []string{"0614141"}})
+	if err != nil {
+		t.Fatalf("new filter: %v", err)
+	}
+	if ok, _ := f.Check(TagEvent{EPC: sgtin}); !ok {
+		t.Fatalf("expected SGTIN from allowed company to pass")
+	}
+	if ok, reason := f.Check(TagEvent{EPC: "E20000112233445566778899"}); ok || reason != "company_prefix" {
+		t.Fatalf("expected non-GS1 EPC to fail company rule, got ok=%v reason=%q", ok, reason)
+	}
+}
+
+func TestNewTagFilterRejectsBadRegex(t *testing.T) {
+	if _, err := NewTagFilter(FilterRules{AllowRegex: []string{"("}}); err == nil {
+		t.Fatalf("expected regex compile error")
+	}
+}
@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// gs1CompanyBits maps the 96-bit scheme partition value to company prefix
+// bit width and decimal digit count; SGTIN, SSCC, GRAI and GIAI share it.
+var gs1CompanyBits = [7][2]int{{40, 12}, {37, 11}, {34, 10}, {30, 9}, {27, 8}, {24, 7}, {20, 6}}
+
+// gs1CompanyPrefix extracts the GS1 company prefix from a 96-bit SGTIN,
+// SSCC, GRAI or GIAI EPC given as hex.
+func gs1CompanyPrefix(epcHex string) (string, bool) {
+	if len(epcHex) != 24 {
+		return "", false
+	}
+	raw, err := hex.DecodeString(epcHex)
+	if err != nil {
+		return "", false
+	}
+	switch raw[0] {
+	case 0x30, 0x31, 0x33, 0x34:
+	default:
+		return "", false
+	}
+	partition := int(readBits(raw, 11, 3))
+	if partition >= len(gs1CompanyBits) {
+		return "", false
+	}
+	bits, digits := gs1CompanyBits[partition][0], gs1CompanyBits[partition][1]
+	return fmt.Sprintf("%0*d", digits, readBits(raw, 14, bits)), true
+}
+
+// readBits reads n (<=64) bits starting at bit offset off, MSB first.
+func readBits(data []byte, off, n int) uint64 {
+	var v uint64
+	for i := 0; i < n; i++ {
+		bit := off + i
+		v = v<<1 | uint64(data[bit/8]>>(7-bit%8)&1)
+	}
+	return v
+}
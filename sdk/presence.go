@@ -0,0 +1,226 @@
+package sdk
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// PresenceKind is the type of a presence change.
+type PresenceKind string
+
+const (
+	TagArrived  PresenceKind = "arrived"
+	TagDeparted PresenceKind = "departed"
+	// TagDwell repeats every DwellInterval while a tag stays in the field.
+	TagDwell PresenceKind = "dwell"
+)
+
+// DefaultAbsenceTimeout is how long a tag must stay unread before it departs.
+const DefaultAbsenceTimeout = 5 * time.Second
+
+// PresenceConfig tunes a PresenceTracker.
+type PresenceConfig struct {
+	// AbsenceTimeout departs a tag that has not been read for this long.
+	AbsenceTimeout time.Duration
+	// DwellInterval emits TagDwell events while a tag stays; zero disables them.
+	DwellInterval time.Duration
+}
+
+// PresenceEvent reports a tag entering, staying in or leaving the field.
+type PresenceEvent struct {
+	When      time.Time
+	Kind      PresenceKind
+	EPC       string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Dwell is LastSeen-FirstSeen for departures and When-FirstSeen otherwise.
+	Dwell time.Duration
+	Reads int
+	// Last is the latest read of the tag.
+	Last TagEvent
+}
+
+// PresentTag is one entry of the current in-field set.
+type PresentTag struct {
+	EPC       string
+	Antenna   int
+	RSSI      int
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Reads     int
+}
+
+type presenceTrack struct {
+	first     time.Time
+	nextDwell time.Time
+	reads     int
+	last      TagEvent
+}
+
+// PresenceTracker turns a TagEvent stream into arrival, dwell and departure
+// events and keeps the set of tags currently in the field. It is safe for
+// concurrent use.
+type PresenceTracker struct {
+	cfg PresenceConfig
+
+	mu     sync.Mutex
+	tracks map[string]*presenceTrack
+}
+
+// NewPresenceTracker creates a tracker; a non-positive timeout uses DefaultAbsenceTimeout.
+func NewPresenceTracker(cfg PresenceConfig) *PresenceTracker {
+	if cfg.AbsenceTimeout <= 0 {
+		cfg.AbsenceTimeout = DefaultAbsenceTimeout
+	}
+	if cfg.DwellInterval < 0 {
+		cfg.DwellInterval = 0
+	}
+	return &PresenceTracker{cfg: cfg, tracks: make(map[string]*presenceTrack)}
+}
+
+// Config returns the effective configuration.
+func (p *PresenceTracker) Config() PresenceConfig {
+	return p.cfg
+}
+
+// Observe feeds one read and returns the arrival of this tag when it was
+// not in the field. It only looks at this tag, so a read costs the same
+// however many tags are present; other departures and dwell reminders come
+// from Flush. A read after the tag's own absence timeout departs and
+// re-arrives it even if Flush has not run yet.
+func (p *PresenceTracker) Observe(event TagEvent) []PresenceEvent {
+	at := event.When
+	if at.IsZero() {
+		at = time.Now()
+		event.When = at
+	}
+	if event.EPC == "" {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var out []PresenceEvent
+	track := p.tracks[event.EPC]
+	if track != nil && at.Sub(track.last.When) >= p.cfg.AbsenceTimeout {
+		delete(p.tracks, event.EPC)
+		out = append(out, track.event(TagDeparted, event.EPC, track.last.When.Add(p.cfg.AbsenceTimeout)))
+		track = nil
+	}
+	if track == nil {
+		track = &presenceTrack{first: at}
+		if p.cfg.DwellInterval > 0 {
+			track.nextDwell = at.Add(p.cfg.DwellInterval)
+		}
+		p.tracks[event.EPC] = track
+		track.reads = 1
+		track.last = event
+		return append(out, track.event(TagArrived, event.EPC, at))
+	}
+	track.reads++
+	track.last = event
+	return out
+}
+
+// Flush departs tags that have been absent for the timeout as of now and
+// emits due dwell events.
+func (p *PresenceTracker) Flush(now time.Time) []PresenceEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.flushLocked(now)
+}
+
+// DepartAll departs every tag in the field, as at the end of a session.
+func (p *PresenceTracker) DepartAll(now time.Time) []PresenceEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]PresenceEvent, 0, len(p.tracks))
+	for epc, track := range p.tracks {
+		out = append(out, track.event(TagDeparted, epc, now))
+	}
+	p.tracks = make(map[string]*presenceTrack)
+	sortPresence(out)
+	return out
+}
+
+// Reset forgets every tag without emitting events.
+func (p *PresenceTracker) Reset() {
+	p.mu.Lock()
+	p.tracks = make(map[string]*presenceTrack)
+	p.mu.Unlock()
+}
+
+// Present returns the tags currently in the field, oldest arrival first.
+func (p *PresenceTracker) Present() []PresentTag {
+	p.mu.Lock()
+	out := make([]PresentTag, 0, len(p.tracks))
+	for epc, track := range p.tracks {
+		out = append(out, PresentTag{
+			EPC:       epc,
+			Antenna:   track.last.Antenna,
+			RSSI:      track.last.RSSI,
+			FirstSeen: track.first,
+			LastSeen:  track.last.When,
+			Reads:     track.reads,
+		})
+	}
+	p.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].FirstSeen.Equal(out[j].FirstSeen) {
+			return out[i].FirstSeen.Before(out[j].FirstSeen)
+		}
+		return out[i].EPC < out[j].EPC
+	})
+	return out
+}
+
+// Len returns the size of the in-field set.
+func (p *PresenceTracker) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.tracks)
+}
+
+func (p *PresenceTracker) flushLocked(now time.Time) []PresenceEvent {
+	var out []PresenceEvent
+	for epc, track := range p.tracks {
+		if now.Sub(track.last.When) >= p.cfg.AbsenceTimeout {
+			delete(p.tracks, epc)
+			out = append(out, track.event(TagDeparted, epc, track.last.When.Add(p.cfg.AbsenceTimeout)))
+			continue
+		}
+		if p.cfg.DwellInterval > 0 && !now.Before(track.nextDwell) {
+			out = append(out, track.event(TagDwell, epc, now))
+			track.nextDwell = now.Add(p.cfg.DwellInterval)
+		}
+	}
+	sortPresence(out)
+	return out
+}
+
+func (t *presenceTrack) event(kind PresenceKind, epc string, when time.Time) PresenceEvent {
+	ev := PresenceEvent{
+		When:      when,
+		Kind:      kind,
+		EPC:       epc,
+		FirstSeen: t.first,
+		LastSeen:  t.last.When,
+		Dwell:     when.Sub(t.first),
+		Reads:     t.reads,
+		Last:      t.last,
+	}
+	if kind == TagDeparted {
+		ev.Dwell = t.last.When.Sub(t.first)
+	}
+	return ev
+}
+
+func sortPresence(events []PresenceEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].When.Equal(events[j].When) {
+			return events[i].When.Before(events[j].When)
+		}
+		return events[i].EPC < events[j].EPC
+	})
+}
@@ -0,0 +1,82 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPresenceTrackerArrivalDwellDeparture(t *testing.T) {
+	p := NewPresenceTracker(PresenceConfig{AbsenceTimeout: 2 * time.Second, DwellInterval: time.Second})
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	read := func(epc string, at time.Duration) []PresenceEvent {
+		return p.Observe(TagEvent{EPC: epc, Antenna: 1, When: t0.Add(at)})
+	}
+
+	if evs := read("A", 0); len(evs) != 1 || evs[0].Kind != TagArrived || evs[0].EPC != "A" {
+		t.Fatalf("expected arrival of A: %+v", evs)
+	}
+	if evs := read("A", 500*time.Millisecond); len(evs) != 0 {
+		t.Fatalf("repeat read must not re-arrive: %+v", evs)
+	}
+	read("B", 800*time.Millisecond)
+
+	evs := p.Flush(t0.Add(1200 * time.Millisecond))
+	if len(evs) != 1 || evs[0].Kind != TagDwell || evs[0].EPC != "A" || evs[0].Dwell != 1200*time.Millisecond {
+		t.Fatalf("expected dwell of A: %+v", evs)
+	}
+	if present := p.Present(); len(present) != 2 || present[0].EPC != "A" || present[0].Reads != 2 {
+		t.Fatalf("unexpected in-field set: %+v", present)
+	}
+
+	read("B", 2*time.Second)
+	evs = p.Flush(t0.Add(2600 * time.Millisecond))
+	if len(evs) != 2 || evs[0].Kind != TagDeparted || evs[0].EPC != "A" || evs[1].Kind != TagDwell || evs[1].EPC != "B" {
+		t.Fatalf("expected departure of A then dwell of B: %+v", evs)
+	}
+	if evs[0].Dwell != 500*time.Millisecond || !evs[0].When.Equal(t0.Add(2500*time.Millisecond)) {
+		t.Fatalf("departure dwell %s at %s", evs[0].Dwell, evs[0].When)
+	}
+
+	// A read reports only its own tag; B's dwell waits for Flush.
+	evs = read("A", 3*time.Second)
+	if len(evs) != 1 || evs[0].Kind != TagArrived || evs[0].EPC != "A" {
+		t.Fatalf("expected only A arrival: %+v", evs)
+	}
+	if evs = p.Flush(t0.Add(3600 * time.Millisecond)); len(evs) != 1 || evs[0].Kind != TagDwell || evs[0].EPC != "B" {
+		t.Fatalf("expected B dwell from Flush: %+v", evs)
+	}
+
+	// A read past the tag's own timeout departs it before Flush does.
+	evs = read("A", 5500*time.Millisecond)
+	if len(evs) != 2 || evs[0].Kind != TagDeparted || evs[0].EPC != "A" || evs[1].Kind != TagArrived {
+		t.Fatalf("expected A departure then arrival: %+v", evs)
+	}
+	evs = p.DepartAll(t0.Add(5500 * time.Millisecond))
+	if len(evs) != 2 || p.Len() != 0 {
+		t.Fatalf("DepartAll left %d tags: %+v", p.Len(), evs)
+	}
+}
+
+func TestClientPresenceResetsPerSession(t *testing.T) {
+	c := NewClient()
+	c.SetPresence(NewPresenceTracker(PresenceConfig{}))
+	c.inventoryOn = true
+
+	epc := []byte{0xE2, 0x00, 0x00, 0x01}
+	c.recordTag("inventory", 1, 0x50, epc)
+	c.recordTag("inventory", 1, 0x50, epc)
+	if ev := <-c.PresenceEvents(); ev.Kind != TagArrived || ev.EPC != "E2000001" {
+		t.Fatalf("unexpected presence event: %+v", ev)
+	}
+	if got := c.PresentTags(); len(got) != 1 || got[0].Reads != 2 {
+		t.Fatalf("present tags = %+v", got)
+	}
+
+	c.finishInventoryRun()
+	if ev := <-c.PresenceEvents(); ev.Kind != TagDeparted || ev.Reads != 2 {
+		t.Fatalf("session end should depart the tag: %+v", ev)
+	}
+	if len(c.PresentTags()) != 0 {
+		t.Fatal("in-field set not cleared at session end")
+	}
+}
@@ -0,0 +1,302 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Profile is a named inventory configuration in the profile file. It holds
+// the radio and polling settings only; reader address, TID, mask and work
+// mode stay with the caller (see Apply).
+type Profile struct {
+	Name               string `json:"name"`
+	Description        string `json:"description,omitempty"`
+	QValue             byte   `json:"q"`
+	Session            byte   `json:"session"`
+	Target             byte   `json:"target"`
+	AntennaMask        byte   `json:"antenna_mask"`
+	ScanTime           byte   `json:"scan_time"`
+	PollMS             int    `json:"poll_ms"`
+	OutputPower        byte   `json:"power"`
+	PerAntennaPower    []int  `json:"per_antenna_power,omitempty"`
+	Region             string `json:"region,omitempty"`
+	NoTagABSwitch      int    `json:"no_tag_ab_switch"`
+	SingleFallbackEach int    `json:"single_fallback_each"`
+	AdaptiveQ          bool   `json:"adaptive_q,omitempty"`
+	AdaptiveSession    bool   `json:"adaptive_session,omitempty"`
+}
+
+// ProfileFromConfig captures the profile fields of cfg under name.
+func ProfileFromConfig(name string, cfg InventoryConfig) Profile {
+	p := Profile{
+		Name:               name,
+		QValue:             cfg.QValue,
+		Session:            cfg.Session,
+		Target:             cfg.Target,
+		AntennaMask:        cfg.AntennaMask,
+		ScanTime:           cfg.ScanTime,
+		PollMS:             int(cfg.PollInterval / time.Millisecond),
+		OutputPower:        cfg.OutputPower,
+		Region:             cfg.RegionCode(),
+		NoTagABSwitch:      cfg.NoTagABSwitch,
+		SingleFallbackEach: cfg.SingleFallbackEach,
+		AdaptiveQ:          cfg.AdaptiveQ,
+		AdaptiveSession:    cfg.AdaptiveSession,
+	}
+	if p.Region == "custom" {
+		p.Region = ""
+	}
+	for _, power := range cfg.PerAntennaPower {
+		p.PerAntennaPower = append(p.PerAntennaPower, int(power))
+	}
+	return p
+}
+
+// Apply overlays the profile on base. An empty Region leaves base's region.
+func (p Profile) Apply(base InventoryConfig) InventoryConfig {
+	cfg := cloneInventoryConfig(base)
+	cfg.QValue = p.QValue
+	cfg.Session = p.Session
+	cfg.Target = p.Target
+	cfg.AntennaMask = p.AntennaMask
+	cfg.ScanTime = p.ScanTime
+	cfg.PollInterval = time.Duration(p.PollMS) * time.Millisecond
+	cfg.OutputPower = p.OutputPower
+	cfg.NoTagABSwitch = p.NoTagABSwitch
+	cfg.SingleFallbackEach = p.SingleFallbackEach
+	cfg.AdaptiveQ = p.AdaptiveQ
+	cfg.AdaptiveSession = p.AdaptiveSession
+	cfg.PerAntennaPower = nil
+	for _, power := range p.PerAntennaPower {
+		cfg.PerAntennaPower = append(cfg.PerAntennaPower, byte(power))
+	}
+	if p.Region != "" {
+		_ = cfg.SetRegion(p.Region)
+	}
+	return normalizeConfig(cfg)
+}
+
+// Validate checks the name, ranges and region code.
+func (p Profile) Validate() error {
+	if !validProfileName(p.Name) {
+		return fmt.Errorf("invalid profile name %q (letters, digits, '-', '_')", p.Name)
+	}
+	if p.QValue > 15 {
+		return fmt.Errorf("profile %s: q out of range: %d", p.Name, p.QValue)
+	}
+	if p.Session > 3 {
+		return fmt.Errorf("profile %s: session out of range: %d", p.Name, p.Session)
+	}
+	if p.OutputPower > maxOutputPower {
+		return fmt.Errorf("profile %s: power out of range: %d (max %d dBm)", p.Name, p.OutputPower, maxOutputPower)
+	}
+	if p.ScanTime == 0 {
+		return fmt.Errorf("profile %s: scan_time must be 1..255 (x100 ms)", p.Name)
+	}
+	if p.AntennaMask == 0 {
+		return fmt.Errorf("profile %s: antenna_mask selects no antenna", p.Name)
+	}
+	if p.PollMS < 0 {
+		return fmt.Errorf("profile %s: negative poll_ms", p.Name)
+	}
+	if len(p.PerAntennaPower) > 8 {
+		return fmt.Errorf("profile %s: at most 8 per-antenna powers", p.Name)
+	}
+	for _, power := range p.PerAntennaPower {
+		if power < 0 || power > maxOutputPower {
+			return fmt.Errorf("profile %s: per-antenna power out of range: %d", p.Name, power)
+		}
+	}
+	if p.Region != "" {
+		var cfg InventoryConfig
+		if err := cfg.SetRegion(p.Region); err != nil {
+			return fmt.Errorf("profile %s: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+func validProfileName(name string) bool {
+	if name == "" || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// LongRangeInventoryConfig is the far-read profile: full power on four
+// ports, long scan time and the whole US band.
+func LongRangeInventoryConfig() InventoryConfig {
+	cfg := DefaultInventoryConfig()
+	// Vendor Java manual exposes SetRfPower range as 0..30.
+	cfg.OutputPower = 30
+	cfg.ScanTime = 10
+	cfg.QValue = 4
+	cfg.Session = 0
+	cfg.NoTagABSwitch = 0
+	cfg.SingleFallbackEach = 4
+	cfg.PollInterval = 200 * time.Millisecond
+	cfg.AntennaMask = 0x0F
+	_ = cfg.SetRegion("US")
+	cfg.PerAntennaPower = []byte{30, 30, 30, 30, 0, 0, 0, 0}
+	return cfg
+}
+
+// Built-in profile names; a profile file may override them.
+const (
+	ProfileBalanced  = "balanced"
+	ProfileLongRange = "long-range"
+)
+
+// BuiltinProfiles returns the profiles available without a profile file.
+func BuiltinProfiles() []Profile {
+	balanced := ProfileFromConfig(ProfileBalanced, DefaultInventoryConfig())
+	balanced.Description = "low-latency default"
+	longRange := ProfileFromConfig(ProfileLongRange, LongRangeInventoryConfig())
+	longRange.Description = "max power, 4 antennas, US band"
+	return []Profile{balanced, longRange}
+}
+
+// profileFile is the on-disk layout of a ProfileStore.
+type profileFile struct {
+	Profiles []Profile `json:"profiles"`
+}
+
+// ProfileStore is a set of named profiles backed by a JSON file. It is safe
+// for concurrent use; built-in profiles are present unless the file
+// overrides them by name. Only user-defined profiles are written back.
+type ProfileStore struct {
+	mu       sync.Mutex
+	path     string
+	profiles map[string]Profile
+	// user holds the names that came from the file or Put.
+	user map[string]bool
+}
+
+// NewProfileStore returns a store holding only the built-in profiles.
+func NewProfileStore(path string) *ProfileStore {
+	s := &ProfileStore{path: strings.TrimSpace(path), profiles: make(map[string]Profile), user: make(map[string]bool)}
+	for _, p := range BuiltinProfiles() {
+		s.profiles[p.Name] = p
+	}
+	return s
+}
+
+// LoadProfileStore reads path; a missing file yields the built-in profiles.
+func LoadProfileStore(path string) (*ProfileStore, error) {
+	s := NewProfileStore(path)
+	if s.path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file profileFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse profile file: %w", err)
+	}
+	for _, p := range file.Profiles {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		s.profiles[p.Name] = p
+		s.user[p.Name] = true
+	}
+	return s, nil
+}
+
+// Path is the backing file, or "" for an in-memory store.
+func (s *ProfileStore) Path() string {
+	return s.path
+}
+
+// Names lists profile names in sorted order.
+func (s *ProfileStore) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.profiles))
+	for name := range s.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns a profile by name, case-insensitively.
+func (s *ProfileStore) Get(name string) (Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.profiles[name]; ok {
+		return p, true
+	}
+	for key, p := range s.profiles {
+		if strings.EqualFold(key, strings.TrimSpace(name)) {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// Put adds or replaces a profile and writes the file.
+func (s *ProfileStore) Put(p Profile) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, existed := s.profiles[p.Name]
+	wasUser := s.user[p.Name]
+	s.profiles[p.Name] = p
+	s.user[p.Name] = true
+	if err := s.saveLocked(); err != nil {
+		if existed {
+			s.profiles[p.Name] = prev
+		} else {
+			delete(s.profiles, p.Name)
+		}
+		if !wasUser {
+			delete(s.user, p.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *ProfileStore) saveLocked() error {
+	if s.path == "" {
+		return fmt.Errorf("profile file not configured")
+	}
+	file := profileFile{Profiles: make([]Profile, 0, len(s.user))}
+	for name := range s.user {
+		file.Profiles = append(file.Profiles, s.profiles[name])
+	}
+	sort.Slice(file.Profiles, func(i, j int) bool { return file.Profiles[i].Name < file.Profiles[j].Name })
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,111 @@
+package sdk
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProfileRoundTripsConfig(t *testing.T) {
+	base := DefaultInventoryConfig()
+	base.TIDWords = 6
+	base.WorkMode = WorkModeActive
+
+	long := LongRangeInventoryConfig()
+	p := ProfileFromConfig("far", long)
+	if p.Region != "US" || p.PollMS != 200 || len(p.PerAntennaPower) != 8 {
+		t.Fatalf("unexpected profile: %+v", p)
+	}
+	cfg := p.Apply(base)
+	if cfg.OutputPower != long.OutputPower || cfg.ScanTime != long.ScanTime || cfg.PollInterval != 200*time.Millisecond {
+		t.Fatalf("radio settings not applied: %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.PerAntennaPower, long.PerAntennaPower) || cfg.RegionCode() != "US" {
+		t.Fatalf("per-antenna/region not applied: %v %q", cfg.PerAntennaPower, cfg.RegionCode())
+	}
+	if cfg.TIDWords != 6 || cfg.WorkMode != WorkModeActive {
+		t.Fatalf("caller options must be kept: tid=%d mode=%d", cfg.TIDWords, cfg.WorkMode)
+	}
+}
+
+func TestProfileValidate(t *testing.T) {
+	cases := []Profile{
+		{Name: ""},
+		{Name: "has space"},
+		{Name: "q", QValue: 16},
+		{Name: "s", Session: 4},
+		{Name: "p", PerAntennaPower: []int{31}},
+		{Name: "r", Region: "XX"},
+		{Name: "power", ScanTime: 1, AntennaMask: 1, OutputPower: 31},
+		{Name: "scan", AntennaMask: 1},
+		{Name: "ant", ScanTime: 1},
+	}
+	for _, p := range cases {
+		if err := p.Validate(); err == nil {
+			t.Fatalf("expected error for %+v", p)
+		}
+	}
+	if err := (Profile{Name: "dock_1", QValue: 4, ScanTime: 1, AntennaMask: 0x03, OutputPower: 30, Region: "eu"}).Validate(); err != nil {
+		t.Fatalf("valid profile rejected: %v", err)
+	}
+}
+
+func TestProfileStoreLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	store, err := LoadProfileStore(path)
+	if err != nil {
+		t.Fatalf("missing file should load builtins: %v", err)
+	}
+	if got := store.Names(); !reflect.DeepEqual(got, []string{ProfileBalanced, ProfileLongRange}) {
+		t.Fatalf("unexpected builtins: %v", got)
+	}
+
+	dock := ProfileFromConfig("dock", DefaultInventoryConfig())
+	dock.OutputPower = 18
+	if err := store.Put(dock); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := store.Put(Profile{Name: "bad name"}); err == nil {
+		t.Fatal("expected invalid profile to be rejected")
+	}
+
+	reloaded, err := LoadProfileStore(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	got, ok := reloaded.Get("DOCK")
+	if !ok || got.OutputPower != 18 {
+		t.Fatalf("saved profile not reloaded: %+v ok=%v", got, ok)
+	}
+	if len(reloaded.Names()) != 3 {
+		t.Fatalf("unexpected names: %v", reloaded.Names())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file profileFile
+	if err := json.Unmarshal(data, &file); err != nil || len(file.Profiles) != 1 || file.Profiles[0].Name != "dock" {
+		t.Fatalf("file must hold only user profiles: %s err=%v", data, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"profiles":[{"name":"x","q":20}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProfileStore(path); err == nil {
+		t.Fatal("expected invalid file to fail")
+	}
+}
+
+func TestProfileStoreWithoutPathIsReadOnly(t *testing.T) {
+	store := NewProfileStore("")
+	if err := store.Put(Profile{Name: "dock"}); err == nil {
+		t.Fatal("expected error without a profile file")
+	}
+	if _, ok := store.Get("dock"); ok {
+		t.Fatal("failed put must not keep the profile")
+	}
+}
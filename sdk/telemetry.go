@@ -0,0 +1,351 @@
+package sdk
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultTelemetryWindow is the rolling window used by Client telemetry.
+const DefaultTelemetryWindow = 10 * time.Second
+
+// telemetryBucket is the width of one telemetry time bucket. The window is
+// covered by whole buckets, so rates are exact to the second.
+const telemetryBucket = time.Second
+
+// Telemetry keeps rolling read-rate and RSSI statistics over a time window.
+// Reads are counted into one bucket per second of the window, per antenna
+// and per EPC, so memory does not grow with the read rate and an old second
+// is dropped by reusing its bucket. It is safe for concurrent use and can be
+// fed from any TagEvent stream.
+type Telemetry struct {
+	mu        sync.Mutex
+	window    time.Duration
+	startedAt time.Time
+	buckets   []telemetrySecond
+}
+
+// telemetrySecond aggregates the reads of one bucket; sec is its start in
+// Unix seconds and zero while unused.
+type telemetrySecond struct {
+	sec      int64
+	reads    int
+	antennas map[int]*antennaSecond
+	tags     map[string]*tagSecond
+}
+
+type antennaSecond struct {
+	reads int
+	rssi  rssiAgg
+}
+
+type tagSecond struct {
+	reads    int
+	antennas map[int]struct{}
+	last     time.Time
+	rssi     rssiAgg
+}
+
+func (b *telemetrySecond) reset(sec int64) {
+	b.sec = sec
+	b.reads = 0
+	clear(b.antennas)
+	clear(b.tags)
+}
+
+// RSSIStats summarizes reported RSSI values; zero (unknown) readings are skipped.
+type RSSIStats struct {
+	Samples int     `json:"samples"`
+	Min     int     `json:"min"`
+	Avg     float64 `json:"avg"`
+	Max     int     `json:"max"`
+}
+
+// AntennaStats is the per-antenna slice of a telemetry snapshot.
+type AntennaStats struct {
+	Antenna     int       `json:"antenna"`
+	Reads       int       `json:"reads"`
+	UniqueTags  int       `json:"unique_tags"`
+	ReadsPerSec float64   `json:"reads_per_sec"`
+	RSSI        RSSIStats `json:"rssi"`
+}
+
+// TagStats is the per-EPC slice of a telemetry snapshot.
+type TagStats struct {
+	EPC         string    `json:"epc"`
+	Reads       int       `json:"reads"`
+	Antennas    []int     `json:"antennas"`
+	ReadsPerSec float64   `json:"reads_per_sec"`
+	LastSeen    time.Time `json:"last_seen"`
+	RSSI        RSSIStats `json:"rssi"`
+}
+
+// TelemetrySnapshot is a point-in-time view of the rolling window.
+// In JSON the window is "window_sec" (seconds).
+type TelemetrySnapshot struct {
+	Window       time.Duration  `json:"-"`
+	Reads        int            `json:"reads"`
+	UniqueTags   int            `json:"unique_tags"`
+	ReadsPerSec  float64        `json:"reads_per_sec"`
+	UniquePerSec float64        `json:"unique_per_sec"`
+	Antennas     []AntennaStats `json:"antennas"`
+	Tags         []TagStats     `json:"tags"`
+}
+
+type telemetrySnapshotJSON TelemetrySnapshot
+
+// MarshalJSON encodes Window as seconds.
+func (s TelemetrySnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		WindowSec float64 `json:"window_sec"`
+		telemetrySnapshotJSON
+	}{s.Window.Seconds(), telemetrySnapshotJSON(s)})
+}
+
+// UnmarshalJSON reads the form written by MarshalJSON.
+func (s *TelemetrySnapshot) UnmarshalJSON(data []byte) error {
+	var v struct {
+		WindowSec float64 `json:"window_sec"`
+		telemetrySnapshotJSON
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = TelemetrySnapshot(v.telemetrySnapshotJSON)
+	s.Window = time.Duration(v.WindowSec * float64(time.Second))
+	return nil
+}
+
+// NewTelemetry creates a tracker; non-positive windows use DefaultTelemetryWindow.
+// The window is rounded up to whole seconds.
+func NewTelemetry(window time.Duration) *Telemetry {
+	if window <= 0 {
+		window = DefaultTelemetryWindow
+	}
+	n := int((window + telemetryBucket - 1) / telemetryBucket)
+	return &Telemetry{
+		window:    time.Duration(n) * telemetryBucket,
+		startedAt: time.Now(),
+		buckets:   make([]telemetrySecond, n),
+	}
+}
+
+// Observe records one tag read. Reads older than the window are ignored.
+func (t *Telemetry) Observe(event TagEvent) {
+	if event.EPC == "" {
+		return
+	}
+	at := event.When
+	if at.IsZero() {
+		at = time.Now()
+	}
+	sec := at.Unix()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b := &t.buckets[bucketIndex(sec, len(t.buckets))]
+	switch {
+	case b.sec == sec:
+	case b.sec > sec:
+		return
+	default:
+		b.reset(sec)
+	}
+	if b.antennas == nil {
+		b.antennas = make(map[int]*antennaSecond)
+		b.tags = make(map[string]*tagSecond)
+	}
+	b.reads++
+	a := b.antennas[event.Antenna]
+	if a == nil {
+		a = &antennaSecond{}
+		b.antennas[event.Antenna] = a
+	}
+	a.reads++
+	a.rssi.add(event.RSSI)
+	g := b.tags[event.EPC]
+	if g == nil {
+		g = &tagSecond{antennas: make(map[int]struct{}, 1)}
+		b.tags[event.EPC] = g
+	}
+	g.reads++
+	g.antennas[event.Antenna] = struct{}{}
+	if at.After(g.last) {
+		g.last = at
+	}
+	g.rssi.add(event.RSSI)
+}
+
+// Reset clears all buckets and restarts the rate baseline.
+func (t *Telemetry) Reset() {
+	t.mu.Lock()
+	for i := range t.buckets {
+		t.buckets[i].reset(0)
+	}
+	t.startedAt = time.Now()
+	t.mu.Unlock()
+}
+
+// Snapshot returns statistics for the window ending now.
+func (t *Telemetry) Snapshot() TelemetrySnapshot {
+	return t.SnapshotAt(time.Now())
+}
+
+// SnapshotAt returns statistics for the window ending at now: the bucket
+// holding now and the whole seconds before it.
+func (t *Telemetry) SnapshotAt(now time.Time) TelemetrySnapshot {
+	type antAgg struct {
+		reads int
+		epcs  map[string]struct{}
+		rssi  rssiAgg
+	}
+	type tagAgg struct {
+		reads    int
+		antennas map[int]struct{}
+		last     time.Time
+		rssi     rssiAgg
+	}
+	ants := make(map[int]*antAgg)
+	tags := make(map[string]*tagAgg)
+	reads := 0
+
+	t.mu.Lock()
+	span := t.window
+	if elapsed := now.Sub(t.startedAt); elapsed > 0 && elapsed < span {
+		span = elapsed
+	}
+	newest := now.Unix()
+	oldest := newest - int64(len(t.buckets)) + 1
+	for i := range t.buckets {
+		b := &t.buckets[i]
+		if b.sec < oldest || b.sec > newest || b.reads == 0 {
+			continue
+		}
+		reads += b.reads
+		for antenna, s := range b.antennas {
+			a := ants[antenna]
+			if a == nil {
+				a = &antAgg{epcs: make(map[string]struct{})}
+				ants[antenna] = a
+			}
+			a.reads += s.reads
+			a.rssi.merge(s.rssi)
+		}
+		for epc, s := range b.tags {
+			g := tags[epc]
+			if g == nil {
+				g = &tagAgg{antennas: make(map[int]struct{})}
+				tags[epc] = g
+			}
+			g.reads += s.reads
+			for antenna := range s.antennas {
+				g.antennas[antenna] = struct{}{}
+				ants[antenna].epcs[epc] = struct{}{}
+			}
+			if s.last.After(g.last) {
+				g.last = s.last
+			}
+			g.rssi.merge(s.rssi)
+		}
+	}
+	t.mu.Unlock()
+
+	snap := TelemetrySnapshot{Window: t.window, Reads: reads}
+	if reads == 0 {
+		return snap
+	}
+	seconds := span.Seconds()
+	if seconds <= 0 {
+		seconds = t.window.Seconds()
+	}
+
+	snap.UniqueTags = len(tags)
+	snap.ReadsPerSec = float64(reads) / seconds
+	snap.UniquePerSec = float64(len(tags)) / seconds
+
+	for antenna, a := range ants {
+		snap.Antennas = append(snap.Antennas, AntennaStats{
+			Antenna:     antenna,
+			Reads:       a.reads,
+			UniqueTags:  len(a.epcs),
+			ReadsPerSec: float64(a.reads) / seconds,
+			RSSI:        a.rssi.stats(),
+		})
+	}
+	sort.Slice(snap.Antennas, func(i, j int) bool { return snap.Antennas[i].Antenna < snap.Antennas[j].Antenna })
+
+	for epc, g := range tags {
+		antennas := make([]int, 0, len(g.antennas))
+		for antenna := range g.antennas {
+			antennas = append(antennas, antenna)
+		}
+		sort.Ints(antennas)
+		snap.Tags = append(snap.Tags, TagStats{
+			EPC:         epc,
+			Reads:       g.reads,
+			Antennas:    antennas,
+			ReadsPerSec: float64(g.reads) / seconds,
+			LastSeen:    g.last,
+			RSSI:        g.rssi.stats(),
+		})
+	}
+	sort.Slice(snap.Tags, func(i, j int) bool {
+		if snap.Tags[i].Reads != snap.Tags[j].Reads {
+			return snap.Tags[i].Reads > snap.Tags[j].Reads
+		}
+		return snap.Tags[i].EPC < snap.Tags[j].EPC
+	})
+	return snap
+}
+
+// bucketIndex maps a Unix second onto a ring of n buckets.
+func bucketIndex(sec int64, n int) int {
+	i := int(sec % int64(n))
+	if i < 0 {
+		i += n
+	}
+	return i
+}
+
+type rssiAgg struct {
+	n   int
+	sum int
+	min int
+	max int
+}
+
+func (r *rssiAgg) add(v int) {
+	if v <= 0 {
+		return
+	}
+	if r.n == 0 || v < r.min {
+		r.min = v
+	}
+	if r.n == 0 || v > r.max {
+		r.max = v
+	}
+	r.n++
+	r.sum += v
+}
+
+func (r *rssiAgg) merge(o rssiAgg) {
+	if o.n == 0 {
+		return
+	}
+	if r.n == 0 || o.min < r.min {
+		r.min = o.min
+	}
+	if r.n == 0 || o.max > r.max {
+		r.max = o.max
+	}
+	r.n += o.n
+	r.sum += o.sum
+}
+
+func (r rssiAgg) stats() RSSIStats {
+	if r.n == 0 {
+		return RSSIStats{}
+	}
+	return RSSIStats{Samples: r.n, Min: r.min, Avg: float64(r.sum) / float64(r.n), Max: r.max}
+}
@@ -0,0 +1,100 @@
+package sdk
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTelemetrySnapshotAggregatesPerAntennaAndEPC(t *testing.T) {
+	tel := NewTelemetry(10 * time.Second)
+	base := time.Now()
+	tel.startedAt = base.Add(-10 * time.Second)
+
+	tel.Observe(TagEvent{When: base.Add(-9 * time.Second), EPC: "E1", Antenna: 1, RSSI: 60})
+	tel.Observe(TagEvent{When: base.Add(-5 * time.Second), EPC: "E1", Antenna: 2, RSSI: 70})
+	tel.Observe(TagEvent{When: base.Add(-4 * time.Second), EPC: "E2", Antenna: 1, RSSI: 50})
+	tel.Observe(TagEvent{When: base.Add(-3 * time.Second), EPC: "E1", Antenna: 1, RSSI: 0})
+
+	snap := tel.SnapshotAt(base)
+	if snap.Reads != 4 || snap.UniqueTags != 2 {
+		t.Fatalf("unexpected totals: reads=%d unique=%d", snap.Reads, snap.UniqueTags)
+	}
+	if snap.ReadsPerSec != 0.4 || snap.UniquePerSec != 0.2 {
+		t.Fatalf("unexpected rates: %.2f %.2f", snap.ReadsPerSec, snap.UniquePerSec)
+	}
+	if len(snap.Antennas) != 2 || snap.Antennas[0].Antenna != 1 {
+		t.Fatalf("unexpected antennas: %+v", snap.Antennas)
+	}
+	ant1 := snap.Antennas[0]
+	if ant1.Reads != 3 || ant1.UniqueTags != 2 || ant1.RSSI.Samples != 2 || ant1.RSSI.Min != 50 || ant1.RSSI.Max != 60 {
+		t.Fatalf("unexpected ant1 stats: %+v", ant1)
+	}
+	if snap.Tags[0].EPC != "E1" || snap.Tags[0].Reads != 3 || len(snap.Tags[0].Antennas) != 2 {
+		t.Fatalf("unexpected top tag: %+v", snap.Tags[0])
+	}
+	if snap.Tags[0].RSSI.Avg != 65 {
+		t.Fatalf("unexpected E1 rssi avg: %.1f", snap.Tags[0].RSSI.Avg)
+	}
+}
+
+func TestTelemetryDropsSamplesOutsideWindow(t *testing.T) {
+	tel := NewTelemetry(2 * time.Second)
+	base := time.Now()
+	tel.Observe(TagEvent{When: base.Add(-5 * time.Second), EPC: "OLD", Antenna: 1})
+	tel.Observe(TagEvent{When: base.Add(-time.Second), EPC: "NEW", Antenna: 1})
+
+	snap := tel.SnapshotAt(base)
+	if snap.Reads != 1 || snap.Tags[0].EPC != "NEW" {
+		t.Fatalf("expected only NEW in window, got %+v", snap.Tags)
+	}
+
+	tel.Reset()
+	if got := tel.SnapshotAt(base).Reads; got != 0 {
+		t.Fatalf("expected empty snapshot after reset, got %d", got)
+	}
+}
+
+func TestTelemetryCountsEveryReadAtHighRates(t *testing.T) {
+	tel := NewTelemetry(2 * time.Second)
+	base := time.Unix(1_700_000_000, 0)
+	tel.startedAt = base.Add(-time.Minute)
+	// More reads than the old sample cap, spread over two seconds.
+	const perSecond = 50000
+	for sec := 0; sec < 2; sec++ {
+		for i := 0; i < perSecond; i++ {
+			at := base.Add(time.Duration(sec)*time.Second + time.Duration(i)*time.Microsecond)
+			tel.Observe(TagEvent{When: at, EPC: "E1", Antenna: 1 + i%2, RSSI: 60})
+		}
+	}
+
+	snap := tel.SnapshotAt(base.Add(time.Second + 500*time.Millisecond))
+	if snap.Reads != 2*perSecond || snap.ReadsPerSec != perSecond {
+		t.Fatalf("expected every read counted: reads=%d rate=%.1f", snap.Reads, snap.ReadsPerSec)
+	}
+	if len(snap.Antennas) != 2 || snap.Antennas[0].Reads != perSecond || snap.Antennas[0].UniqueTags != 1 {
+		t.Fatalf("unexpected antennas: %+v", snap.Antennas)
+	}
+
+	// A read two seconds later reuses the oldest bucket.
+	tel.Observe(TagEvent{When: base.Add(2 * time.Second), EPC: "E2", Antenna: 1})
+	snap = tel.SnapshotAt(base.Add(2 * time.Second))
+	if snap.Reads != perSecond+1 || snap.UniqueTags != 2 {
+		t.Fatalf("expected the first second dropped: reads=%d unique=%d", snap.Reads, snap.UniqueTags)
+	}
+}
+
+func TestTelemetrySnapshotJSONWindowInSeconds(t *testing.T) {
+	data, err := json.Marshal(TelemetrySnapshot{Window: 10 * time.Second, Reads: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"window_sec":10`) || !strings.Contains(string(data), `"reads":3`) {
+		t.Fatalf("unexpected json: %s", data)
+	}
+	var back TelemetrySnapshot
+	if err := json.Unmarshal(data, &back); err != nil || back.Window != 10*time.Second || back.Reads != 3 {
+		t.Fatalf("round trip: %+v %v", back, err)
+	}
+}
@@ -1,23 +1,58 @@
 package sdk
 
 import (
+	"io"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	reader18 "new_era_go/internal/protocol/reader18"
+	"new_era_go/internal/reader"
+	"new_era_go/internal/regions"
 )
 
-// Endpoint is a public network address of reader.
+// Endpoint is a public network address of reader. When Serial.Device is
+// set the reader is on a serial line and Host/Port are ignored; when
+// Replay.File is set a recorded capture is played back instead.
 type Endpoint struct {
-	Host string
-	Port int
+	Host   string
+	Port   int
+	Serial SerialConfig
+	Replay ReplayConfig
 }
 
 func (e Endpoint) Address() string {
+	if e.Replay.File != "" {
+		return "replay:" + e.Replay.File
+	}
+	if e.Serial.Device != "" {
+		return e.Serial.String()
+	}
 	return net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
 }
 
+// SerialConfig describes a UART, USB-serial or RS-485 line.
+type SerialConfig = reader.SerialConfig
+
+// ReplayConfig points a Client at a capture file made by a Recorder.
+type ReplayConfig = reader.ReplayConfig
+
+// Recorder writes a session's TX/RX chunks with timestamps to a capture
+// file (JSON Lines) that ReplayConfig can play back.
+type Recorder = reader.Recorder
+
+// NewRecorder records to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return reader.NewRecorder(w)
+}
+
+// ParseSerial reads "/dev/ttyUSB0", "/dev/ttyUSB0@115200" or
+// "/dev/ttyUSB0@57600,8E1".
+func ParseSerial(spec string) (SerialConfig, error) {
+	return reader.ParseSerial(spec)
+}
+
 // ScanOptions controls LAN discovery behavior in SDK API.
 type ScanOptions struct {
 	Ports                 []int
@@ -40,21 +75,54 @@ type Candidate struct {
 
 // InventoryConfig controls how the reader performs inventory polling.
 type InventoryConfig struct {
-	ReaderAddress      byte
-	AutoAddress        bool
-	QValue             byte
-	Session            byte
-	Target             byte
-	AntennaMask        byte
-	ScanTime           byte
-	PollInterval       time.Duration
-	OutputPower        byte
-	RegionSet          bool
+	ReaderAddress byte
+	AutoAddress   bool
+	QValue        byte
+	Session       byte
+	Target        byte
+	AntennaMask   byte
+	ScanTime      byte
+	PollInterval  time.Duration
+	OutputPower   byte
+	RegionSet     bool
+	// Region is the catalogue code chosen with SetRegion. Several presets
+	// share one encoding, so the code is kept rather than decoded back.
+	Region             string
 	RegionHigh         byte
 	RegionLow          byte
 	PerAntennaPower    []byte
 	NoTagABSwitch      int
 	SingleFallbackEach int
+	// AdaptiveQ tunes Q per round from tag yield; AdaptiveSession also moves
+	// large populations to session 2 and back. Decisions arrive as StatusEvents.
+	AdaptiveQ       bool
+	AdaptiveSession bool
+	// TIDWords > 0 reads that many TID words from TIDAddr for every new EPC
+	// with a ReadData command between rounds; the EPC's reads are held until
+	// then and carry the TID afterwards. Streaming work modes report EPC
+	// only and ignore both fields.
+	TIDAddr  byte
+	TIDWords byte
+	// WorkMode is the SetWorkMode Read_mode byte. WorkModeAnswer polls with
+	// inventory commands; active and trigger modes let the reader push tags.
+	WorkMode byte
+	// StreamTagTime is the reader-side same-tag suppression in seconds for
+	// streaming work modes.
+	StreamTagTime byte
+	// StreamFrame is the 0xEE payload layout the reader's firmware sends in
+	// streaming work modes (ActiveFrameAntenna by default).
+	StreamFrame ActiveFrame
+	// Mask limits inventory to tags matching a Gen2 Select mask; see EPCPrefixMask.
+	Mask InventoryMask
+}
+
+// InventoryMask is a Gen2 Select mask (bank, bit pointer, bits). It is sent to
+// the reader, which only supports selecting the tags that match it.
+type InventoryMask = reader18.InventoryMask
+
+// EPCPrefixMask returns a mask that selects EPCs starting with the hex prefix.
+func EPCPrefixMask(prefix string) (InventoryMask, error) {
+	return reader18.EPCPrefixMask(prefix)
 }
 
 // DefaultInventoryConfig returns a balanced low-latency configuration.
@@ -74,6 +142,40 @@ func DefaultInventoryConfig() InventoryConfig {
 	}
 }
 
+// SetRegion selects a regions.Catalog preset (e.g. "EU") for command 0x22.
+// An empty code clears the region so the reader keeps its own setting.
+func (c *InventoryConfig) SetRegion(code string) error {
+	if strings.TrimSpace(code) == "" {
+		c.RegionSet, c.Region, c.RegionHigh, c.RegionLow = false, "", 0, 0
+		return nil
+	}
+	high, low, err := regions.Encode(code)
+	if err != nil {
+		return err
+	}
+	region, _ := regions.Lookup(code)
+	c.RegionSet, c.Region, c.RegionHigh, c.RegionLow = true, region.Code, high, low
+	return nil
+}
+
+// RegionCode names the configured region: the code given to SetRegion, a
+// catalogue code decoded from the bytes when exactly one preset uses them,
+// "custom" otherwise, or "" when no region is set.
+func (c InventoryConfig) RegionCode() string {
+	if !c.RegionSet {
+		return ""
+	}
+	if region, ok := regions.Lookup(c.Region); ok {
+		if high, low := region.Encode(); high == c.RegionHigh && low == c.RegionLow {
+			return region.Code
+		}
+	}
+	if region, ok := regions.Decode(c.RegionHigh, c.RegionLow); ok {
+		return region.Code
+	}
+	return "custom"
+}
+
 // EffectiveInterval is the real inventory cycle; firmware scan-time is a hard lower bound.
 func (c InventoryConfig) EffectiveInterval() time.Duration {
 	min := time.Duration(c.ScanTime) * 100 * time.Millisecond
@@ -94,8 +196,8 @@ func normalizeConfig(cfg InventoryConfig) InventoryConfig {
 	if cfg.ScanTime == 0 {
 		cfg.ScanTime = 0x01
 	}
-	if cfg.OutputPower > 0x1E {
-		cfg.OutputPower = 0x1E
+	if cfg.OutputPower > maxOutputPower {
+		cfg.OutputPower = maxOutputPower
 	}
 	if cfg.PollInterval <= 0 {
 		cfg.PollInterval = 40 * time.Millisecond
@@ -106,9 +208,21 @@ func normalizeConfig(cfg InventoryConfig) InventoryConfig {
 	if cfg.NoTagABSwitch < 0 {
 		cfg.NoTagABSwitch = 0
 	}
+	if cfg.TIDWords > maxTIDWords {
+		cfg.TIDWords = maxTIDWords
+	}
+	if cfg.WorkMode > WorkModeTriggerHigh {
+		cfg.WorkMode = WorkModeAnswer
+	}
+	if cfg.StreamFrame > ActiveFrameEPC {
+		cfg.StreamFrame = ActiveFrameAntenna
+	}
+	if cfg.Mask.Validate() != nil {
+		cfg.Mask = InventoryMask{}
+	}
 	for i := range cfg.PerAntennaPower {
-		if cfg.PerAntennaPower[i] > 0x1E {
-			cfg.PerAntennaPower[i] = 0x1E
+		if cfg.PerAntennaPower[i] > maxOutputPower {
+			cfg.PerAntennaPower[i] = maxOutputPower
 		}
 	}
 	return cfg
@@ -119,18 +233,31 @@ func cloneInventoryConfig(cfg InventoryConfig) InventoryConfig {
 	if len(cfg.PerAntennaPower) > 0 {
 		out.PerAntennaPower = append([]byte(nil), cfg.PerAntennaPower...)
 	}
+	if len(cfg.Mask.Data) > 0 {
+		out.Mask.Data = append([]byte(nil), cfg.Mask.Data...)
+	}
 	return out
 }
 
+// maxOutputPower is the highest SetOutputPower value, 30 dBm.
+const maxOutputPower = 0x1E
+
+// maxTIDWords caps TID reads at 15 words, the largest length Reader18 firmware accepts.
+const maxTIDWords = 15
+
 // TagEvent is one decoded EPC read event.
 type TagEvent struct {
-	When       time.Time
-	Source     string
-	EPC        string
-	Antenna    int
-	RSSI       int
-	IsNew      bool
-	Rounds     int
+	When    time.Time
+	Source  string
+	EPC     string
+	TID     string
+	Antenna int
+	RSSI    int
+	// IsNew is set when the EPC was not read within the dedup window
+	// (the whole session unless Client.SetDedup says otherwise).
+	IsNew  bool
+	Rounds int
+	// UniqueTags counts IsNew reads in the session.
 	UniqueTags int
 }
 
@@ -148,4 +275,28 @@ type Stats struct {
 	LastTagEPC  string
 	ReaderAddr  byte
 	TargetValue byte
+	// QValue and Session are the values used by the latest round; they
+	// differ from the config when adaptive tuning is on.
+	QValue  byte
+	Session byte
+	// Parser health since the client was created: frames rejected for a
+	// bad CRC, bytes dropped while resynchronising, and partial frames cut
+	// when a new inventory session discarded them.
+	CRCErrors         int
+	ResyncBytes       int
+	BufferTruncations int
+	// Events lost to full channels since the client was created: raw
+	// packets below the decoder, Tags (subscribers included),
+	// Statuses, Errors and PresenceEvents events.
+	DroppedPackets  uint64
+	DroppedTags     uint64
+	DroppedStatuses uint64
+	DroppedErrors   uint64
+	DroppedPresence uint64
+	// EmptyEPCReads counts reads that carried no EPC; they are rejected,
+	// never emitted as TagEvents.
+	EmptyEPCReads uint64
+	// TIDReadErrors counts failed TID reads (TIDWords > 0). A read held for
+	// its TID is released without one when the read fails.
+	TIDReadErrors uint64
 }
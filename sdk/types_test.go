@@ -3,6 +3,8 @@ package sdk
 import (
 	"testing"
 	"time"
+
+	"new_era_go/internal/regions"
 )
 
 func TestEffectiveIntervalUsesScanTimeFloor(t *testing.T) {
@@ -83,3 +85,41 @@ func TestNormalizeConfigClampsPerAntennaPowerAndCopies(t *testing.T) {
 		t.Fatalf("expected clamped per-antenna value, got 0x%02X", cfg.PerAntennaPower[1])
 	}
 }
+
+func TestInventoryConfigSetRegion(t *testing.T) {
+	cfg := DefaultInventoryConfig()
+	if err := cfg.SetRegion("eu"); err != nil {
+		t.Fatalf("set region: %v", err)
+	}
+	if !cfg.RegionSet || cfg.RegionHigh != 0x4E || cfg.RegionLow != 0x00 || cfg.RegionCode() != "EU" {
+		t.Fatalf("unexpected EU region: %+v code=%q", cfg, cfg.RegionCode())
+	}
+	if err := cfg.SetRegion("mars"); err == nil {
+		t.Fatalf("expected unknown region error")
+	}
+	if err := cfg.SetRegion(""); err != nil || cfg.RegionSet || cfg.RegionCode() != "" {
+		t.Fatalf("empty code should clear region: %+v", cfg)
+	}
+}
+
+func TestInventoryConfigRegionCodeRoundTripsEveryPreset(t *testing.T) {
+	for _, region := range regions.Catalog {
+		var cfg InventoryConfig
+		if err := cfg.SetRegion(region.Code); err != nil {
+			t.Fatalf("%s: %v", region.Code, err)
+		}
+		if got := cfg.RegionCode(); got != region.Code {
+			t.Fatalf("%s: RegionCode() = %q", region.Code, got)
+		}
+		if got := ProfileFromConfig("p", cfg).Region; got != region.Code {
+			t.Fatalf("%s: profile region = %q", region.Code, got)
+		}
+	}
+
+	// Bytes read back from a reader cannot tell KR from JP.
+	high, low, _ := regions.Encode("KR")
+	readback := InventoryConfig{RegionSet: true, RegionHigh: high, RegionLow: low}
+	if got := readback.RegionCode(); got != "custom" {
+		t.Fatalf("shared encoding must not pick a preset, got %q", got)
+	}
+}